@@ -1,21 +1,90 @@
 package main
 
 import (
+	"InternshipTask/db"
 	"InternshipTask/internal/app"
+	"InternshipTask/internal/domain/idempotency"
+	"InternshipTask/internal/infrastructure/postgres"
+	"InternshipTask/internal/infrastructure/postgres/migrate"
+	"InternshipTask/internal/infrastructure/sqlite"
+	sqlitemigrate "InternshipTask/internal/infrastructure/sqlite/migrate"
+	"context"
+	"flag"
+	"fmt"
 	"log"
+	"log/slog"
 	"os"
+	"strconv"
+	"strings"
 	"time"
+	_ "time/tzdata"
+
+	"github.com/gin-gonic/gin"
 )
 
+const usage = `usage:
+  service [-migrate] [-seed]      run the HTTP server
+  service migrate up              apply all pending migrations
+  service migrate down [N]        roll back the last N migrations (default 1)
+  service migrate status          list migrations and when they were applied
+  service migrate seed            load the demo data
+`
+
 func main() {
+	migrateOnStart := flag.Bool("migrate", envBool("MIGRATE_ON_START", false), "apply pending migrations before serving")
+	seedOnStart := flag.Bool("seed", envBool("SEED_ON_START", false), "load the demo data before serving")
+	flag.Usage = func() { fmt.Fprint(flag.CommandLine.Output(), usage) }
+	flag.Parse()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: envLogLevel("LOG_LEVEL", slog.LevelInfo)}))
+	slog.SetDefault(logger)
+	if os.Getenv(gin.EnvGinMode) == "" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	storage := os.Getenv("STORAGE")
 	dsn := os.Getenv("DATABASE_DSN")
-	if dsn == "" {
+	if dsn == "" && (storage != app.StorageMemory || len(flag.Args()) > 0) {
 		log.Fatal("DATABASE_DSN env is required")
 	}
 
+	if args := flag.Args(); len(args) > 0 {
+		if args[0] != "migrate" {
+			flag.Usage()
+			os.Exit(2)
+		}
+		if err := runMigrate(dsn, args[1:]); err != nil {
+			log.Fatalf("migrate: %v", err)
+		}
+		return
+	}
+
 	engine, err := app.New(app.Config{
-		PostgresDSN:    dsn,
-		ConnectTimeout: 5 * time.Second,
+		Storage:             storage,
+		DatabaseDSN:         dsn,
+		ConnectTimeout:      5 * time.Second,
+		MaxConns:            int32(envInt("DB_MAX_CONNS", 0)),
+		MinConns:            int32(envInt("DB_MIN_CONNS", 0)),
+		MaxConnLifetime:     envDuration("DB_MAX_CONN_LIFETIME", 0),
+		MaxConnIdleTime:     envDuration("DB_MAX_CONN_IDLE_TIME", 0),
+		ReviewSLA:           time.Duration(envInt("REVIEW_SLA_HOURS", 24)) * time.Hour,
+		MigrateOnStart:      *migrateOnStart,
+		SeedOnStart:         *seedOnStart,
+		EventSinks:          envList("EVENT_SINKS", []string{app.SinkLog}),
+		EventWebhookURL:     os.Getenv("EVENT_WEBHOOK_URL"),
+		OutboxPollInterval:  envDuration("OUTBOX_POLL_INTERVAL", 0),
+		OutboxMaxAttempts:   envInt("OUTBOX_MAX_ATTEMPTS", 0),
+		RetentionDays:       envInt("RETENTION_DAYS", 0),
+		RetentionInterval:   envDuration("RETENTION_INTERVAL", 0),
+		IdempotencyTTL:      envDuration("IDEMPOTENCY_TTL", idempotency.DefaultTTL),
+		AuthEnabled:         envBool("AUTH_ENABLED", false),
+		AuthAdminToken:      os.Getenv("AUTH_ADMIN_TOKEN"),
+		AuthFailureLimit:    os.Getenv("AUTH_FAILURE_LIMIT"),
+		RateLimits:          envList("RATE_LIMITS", nil),
+		OpenAPIValidation:   os.Getenv("OPENAPI_VALIDATION"),
+		GitHubWebhookSecret: os.Getenv("GITHUB_WEBHOOK_SECRET"),
+		GitHubLogins:        envList("GITHUB_LOGINS", nil),
+		Logger:              logger,
 	})
 	if err != nil {
 		log.Fatalf("init app: %v", err)
@@ -26,4 +95,167 @@ func main() {
 	}
 }
 
+func runMigrate(dsn string, args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+	migrator, seed, closeDB, err := openMigrator(ctx, dsn)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("applied %03d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		rolledBack, err := migrator.Down(ctx, steps)
+		for _, m := range rolledBack {
+			fmt.Printf("rolled back %03d_%s\n", m.Version, m.Name)
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, st := range statuses {
+			applied := "pending"
+			if st.AppliedAt != nil {
+				applied = st.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%03d_%-24s %s\n", st.Version, st.Name, applied)
+		}
+		return nil
+	case "seed":
+		if err := migrator.Seed(ctx, seed); err != nil {
+			return err
+		}
+		fmt.Println("seed applied")
+		return nil
+	default:
+		flag.Usage()
+		os.Exit(2)
+		return nil
+	}
+}
+
+type migrator interface {
+	Up(ctx context.Context) ([]migrate.Migration, error)
+	Down(ctx context.Context, steps int) ([]migrate.Migration, error)
+	Status(ctx context.Context) ([]migrate.Status, error)
+	Seed(ctx context.Context, script string) error
+}
+
+// openMigrator connects to the database named by dsn and returns its
+// migrator together with the matching seed script.
+func openMigrator(ctx context.Context, dsn string) (migrator, string, func(), error) {
+	storage, err := app.StorageFromDSN(dsn)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	if storage == app.StorageSQLite {
+		conn, err := sqlite.Open(ctx, dsn)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		m, err := sqlitemigrate.New(conn, db.SQLiteMigrations())
+		if err != nil {
+			conn.Close()
+			return nil, "", nil, err
+		}
+		return m, db.SQLiteSeed, func() { conn.Close() }, nil
+	}
 
+	pool, err := postgres.NewPool(ctx, &postgres.Config{DatabaseDSN: dsn, ConnectTimeout: 5 * time.Second})
+	if err != nil {
+		return nil, "", nil, err
+	}
+	m, err := migrate.New(pool, db.Migrations())
+	if err != nil {
+		pool.Close()
+		return nil, "", nil, err
+	}
+	return m, db.Seed, pool.Close, nil
+}
+
+func envInt(name string, def int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		log.Fatalf("invalid %s: %q", name, v)
+	}
+	return n
+}
+
+func envBool(name string, def bool) bool {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Fatalf("invalid %s: %q", name, v)
+	}
+	return b
+}
+
+func envDuration(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		log.Fatalf("invalid %s: %q", name, v)
+	}
+	return d
+}
+
+func envLogLevel(name string, def slog.Level) slog.Level {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(v)); err != nil {
+		log.Fatalf("invalid %s: %q", name, v)
+	}
+	return level
+}
+
+// envList reads a comma-separated list. Unlike the other helpers it tells an
+// unset variable from an empty one, so that the list can be set to nothing.
+func envList(name string, def []string) []string {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return def
+	}
+	list := make([]string, 0)
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+//go:embed sqlite/migrations/*.sql
+var sqliteMigrations embed.FS
+
+//go:embed seed/seed.sql
+var Seed string
+
+//go:embed sqlite/seed.sql
+var SQLiteSeed string
+
+// Migrations returns the Postgres migrations, named NNN_name.up.sql and
+// NNN_name.down.sql.
+func Migrations() fs.FS {
+	return sub(migrations, "migrations")
+}
+
+// SQLiteMigrations returns the SQLite migrations, named like the Postgres ones.
+func SQLiteMigrations() fs.FS {
+	return sub(sqliteMigrations, "sqlite/migrations")
+}
+
+func sub(fsys embed.FS, dir string) fs.FS {
+	s, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
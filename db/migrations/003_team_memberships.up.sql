@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS team_memberships (
+    team_name TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    user_id   TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    PRIMARY KEY (team_name, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_memberships_user_id ON team_memberships(user_id);
+
+INSERT INTO team_memberships (team_name, user_id)
+SELECT team_name, user_id FROM users
+    ON CONFLICT (team_name, user_id) DO NOTHING;
+
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS team_name TEXT REFERENCES teams(team_name);
+
+UPDATE pull_requests AS pr
+SET team_name = u.team_name
+FROM users AS u
+WHERE u.user_id = pr.author_id AND pr.team_name IS NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS required_skills;
+
+DROP TABLE IF EXISTS user_skills;
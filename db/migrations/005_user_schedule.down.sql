@@ -0,0 +1,4 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_work_hours_check;
+ALTER TABLE users DROP COLUMN IF EXISTS work_end_minute;
+ALTER TABLE users DROP COLUMN IF EXISTS work_start_minute;
+ALTER TABLE users DROP COLUMN IF EXISTS time_zone;
@@ -0,0 +1,7 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS time_zone TEXT NOT NULL DEFAULT 'UTC';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS work_start_minute SMALLINT NOT NULL DEFAULT 540;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS work_end_minute SMALLINT NOT NULL DEFAULT 1080;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_work_hours_check;
+ALTER TABLE users ADD CONSTRAINT users_work_hours_check
+    CHECK (work_start_minute >= 0 AND work_start_minute < work_end_minute AND work_end_minute <= 1440);
@@ -0,0 +1,12 @@
+-- Only current reviewers survive the rollback; the history is lost.
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS assigned_reviewers TEXT[] NOT NULL DEFAULT '{}';
+
+UPDATE pull_requests AS pr
+SET assigned_reviewers = ARRAY(
+    SELECT ra.reviewer_id
+    FROM review_assignments AS ra
+    WHERE ra.pull_request_id = pr.pull_request_id AND ra.unassigned_at IS NULL
+    ORDER BY ra.slot, ra.assigned_at
+);
+
+DROP TABLE IF EXISTS review_assignments;
@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS review_assignments (
+    id              BIGSERIAL PRIMARY KEY,
+    pull_request_id TEXT NOT NULL
+        CONSTRAINT review_assignments_pull_request_id_fkey REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    reviewer_id     TEXT NOT NULL REFERENCES users(user_id),
+    slot            SMALLINT NOT NULL,
+    assigned_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    assigned_reason TEXT NOT NULL,
+    assigned_by     TEXT,
+    unassigned_at   TIMESTAMPTZ,
+    unassign_reason TEXT,
+    unassigned_by   TEXT
+);
+
+-- A reviewer can hold at most one open assignment per PR.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_review_assignments_active
+    ON review_assignments(pull_request_id, reviewer_id)
+    WHERE unassigned_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_review_assignments_reviewer_id ON review_assignments(reviewer_id);
+
+-- Move the legacy assigned_reviewers array into the table and drop it.
+-- Reviewer ids that do not reference an existing user are skipped.
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'pull_requests' AND column_name = 'assigned_reviewers'
+    ) THEN
+        INSERT INTO review_assignments (pull_request_id, reviewer_id, slot, assigned_at, assigned_reason)
+        SELECT pr.pull_request_id, r.reviewer_id, r.ord - 1, COALESCE(pr.created_at, NOW()), 'CREATED'
+        FROM pull_requests AS pr
+        CROSS JOIN LATERAL unnest(pr.assigned_reviewers) WITH ORDINALITY AS r(reviewer_id, ord)
+        WHERE EXISTS (SELECT 1 FROM users AS u WHERE u.user_id = r.reviewer_id)
+        ON CONFLICT DO NOTHING;
+
+        ALTER TABLE pull_requests DROP COLUMN assigned_reviewers;
+    END IF;
+END $$;
@@ -0,0 +1,31 @@
+-- Merged PRs past the retention period are moved here together with their
+-- assignment history. Archived rows keep their ids but no foreign keys to
+-- the live tables.
+CREATE TABLE IF NOT EXISTS pull_requests_archive (
+    pull_request_id   TEXT PRIMARY KEY,
+    pull_request_name TEXT NOT NULL,
+    author_id         TEXT NOT NULL,
+    team_name         TEXT,
+    status            TEXT NOT NULL,
+    required_skills   TEXT[] NOT NULL DEFAULT '{}',
+    created_at        TIMESTAMPTZ,
+    merged_at         TIMESTAMPTZ,
+    version           BIGINT NOT NULL,
+    archived_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS review_assignments_archive (
+    id              BIGINT PRIMARY KEY,
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests_archive(pull_request_id) ON DELETE CASCADE,
+    reviewer_id     TEXT NOT NULL,
+    slot            SMALLINT NOT NULL,
+    assigned_at     TIMESTAMPTZ NOT NULL,
+    assigned_reason TEXT NOT NULL,
+    assigned_by     TEXT,
+    unassigned_at   TIMESTAMPTZ,
+    unassign_reason TEXT,
+    unassigned_by   TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_assignments_archive_reviewer_id ON review_assignments_archive(reviewer_id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_merged_at ON pull_requests(merged_at) WHERE status = 'MERGED';
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_pull_requests_name_trgm;
+DROP INDEX IF EXISTS idx_pull_requests_archive_created_at;
+DROP INDEX IF EXISTS idx_pull_requests_team_created_at;
+DROP INDEX IF EXISTS idx_pull_requests_author_created_at;
+DROP INDEX IF EXISTS idx_pull_requests_status_created_at;
+DROP INDEX IF EXISTS idx_pull_requests_created_at;
+
+ALTER TABLE pull_requests_archive ALTER COLUMN created_at DROP NOT NULL;
+ALTER TABLE pull_requests ALTER COLUMN created_at DROP NOT NULL;
+ALTER TABLE pull_requests ALTER COLUMN created_at DROP DEFAULT;
@@ -0,0 +1,19 @@
+-- /pullRequest/list pages by (created_at, pull_request_id), so created_at is
+-- required from now on. The composite indexes serve the list with and
+-- without the most selective equality filters.
+UPDATE pull_requests SET created_at = COALESCE(merged_at, NOW()) WHERE created_at IS NULL;
+ALTER TABLE pull_requests ALTER COLUMN created_at SET DEFAULT NOW();
+ALTER TABLE pull_requests ALTER COLUMN created_at SET NOT NULL;
+
+UPDATE pull_requests_archive SET created_at = COALESCE(merged_at, archived_at) WHERE created_at IS NULL;
+ALTER TABLE pull_requests_archive ALTER COLUMN created_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_created_at ON pull_requests(created_at, pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_status_created_at ON pull_requests(status, created_at, pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_author_created_at ON pull_requests(author_id, created_at, pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_team_created_at ON pull_requests(team_name, created_at, pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_archive_created_at ON pull_requests_archive(created_at, pull_request_id);
+
+-- Substring search on the name.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX IF NOT EXISTS idx_pull_requests_name_trgm ON pull_requests USING GIN (pull_request_name gin_trgm_ops);
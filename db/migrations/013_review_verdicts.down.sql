@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_review_assignments_active_reviewer;
+
+ALTER TABLE review_assignments_archive DROP COLUMN IF EXISTS verdict_at, DROP COLUMN IF EXISTS verdict;
+ALTER TABLE review_assignments DROP COLUMN IF EXISTS verdict_at, DROP COLUMN IF EXISTS verdict;
@@ -0,0 +1,14 @@
+-- The verdict a reviewer submitted for their current stint on a PR.
+ALTER TABLE review_assignments
+    ADD COLUMN IF NOT EXISTS verdict TEXT NOT NULL DEFAULT 'PENDING'
+        CHECK (verdict IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+    ADD COLUMN IF NOT EXISTS verdict_at TIMESTAMPTZ;
+
+ALTER TABLE review_assignments_archive
+    ADD COLUMN IF NOT EXISTS verdict TEXT NOT NULL DEFAULT 'PENDING',
+    ADD COLUMN IF NOT EXISTS verdict_at TIMESTAMPTZ;
+
+-- /users/getReview looks up the current reviews of one user.
+CREATE INDEX IF NOT EXISTS idx_review_assignments_active_reviewer
+    ON review_assignments(reviewer_id, pull_request_id)
+    WHERE unassigned_at IS NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_outbox_aggregate;
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS parked_at;
@@ -0,0 +1,36 @@
+-- Demo data for manual testing. Safe to apply more than once.
+
+INSERT INTO teams (team_name) VALUES
+    ('backend'),
+    ('frontend')
+ON CONFLICT (team_name) DO NOTHING;
+
+INSERT INTO users (user_id, username, team_name, is_active) VALUES
+    ('u1', 'Alice',   'backend',  true),
+    ('u2', 'Bob',     'backend',  true),
+    ('u3', 'Charlie', 'backend',  false),
+    ('u4', 'Dave',    'frontend', true),
+    ('u5', 'Eve',     'frontend', true),
+    ('u6', 'Frank',   'frontend', false)
+ON CONFLICT (user_id) DO NOTHING;
+
+INSERT INTO team_memberships (team_name, user_id)
+SELECT team_name, user_id FROM users WHERE user_id IN ('u1', 'u2', 'u3', 'u4', 'u5', 'u6')
+ON CONFLICT (team_name, user_id) DO NOTHING;
+
+INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, team_name, status, created_at, merged_at) VALUES
+    ('pr-1001', 'Add search feature', 'u1', 'backend', 'OPEN',   NOW(),                     NULL),
+    ('pr-1002', 'Refactor payments',  'u1', 'backend', 'MERGED', NOW() - INTERVAL '2 days', NOW() - INTERVAL '1 day'),
+    ('pr-1003', 'Docs update',        'u3', 'backend', 'OPEN',   NOW() - INTERVAL '3 days', NULL)
+ON CONFLICT (pull_request_id) DO NOTHING;
+
+INSERT INTO review_assignments (pull_request_id, reviewer_id, slot, assigned_at, assigned_reason)
+SELECT v.pull_request_id, v.reviewer_id, v.slot, pr.created_at, 'CREATED'
+FROM (VALUES
+    ('pr-1001', 'u2', 0),
+    ('pr-1001', 'u4', 1),
+    ('pr-1002', 'u2', 0),
+    ('pr-1002', 'u5', 1)
+) AS v(pull_request_id, reviewer_id, slot)
+JOIN pull_requests AS pr ON pr.pull_request_id = v.pull_request_id
+WHERE NOT EXISTS (SELECT 1 FROM review_assignments AS ra WHERE ra.pull_request_id = v.pull_request_id);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS review_assignments;
+DROP TABLE IF EXISTS pull_requests;
+DROP TABLE IF EXISTS user_skills;
+DROP TABLE IF EXISTS team_memberships;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS teams;
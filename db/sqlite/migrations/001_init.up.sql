@@ -0,0 +1,72 @@
+-- SQLite has no arrays: required_skills is a JSON array of strings, and
+-- timestamps are UTC TEXT in a fixed-width RFC 3339 layout so that they sort
+-- correctly as strings.
+
+CREATE TABLE IF NOT EXISTS teams (
+    team_name TEXT PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    user_id           TEXT PRIMARY KEY,
+    username          TEXT NOT NULL,
+    team_name         TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    is_active         INTEGER NOT NULL,
+    time_zone         TEXT NOT NULL DEFAULT 'UTC',
+    work_start_minute INTEGER NOT NULL DEFAULT 540,
+    work_end_minute   INTEGER NOT NULL DEFAULT 1080,
+    departed_at       TEXT,
+    CONSTRAINT users_work_hours_check
+        CHECK (work_start_minute >= 0 AND work_start_minute < work_end_minute AND work_end_minute <= 1440)
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_team_name ON users(team_name);
+
+CREATE TABLE IF NOT EXISTS team_memberships (
+    team_name TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    user_id   TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    PRIMARY KEY (team_name, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_memberships_user_id ON team_memberships(user_id);
+
+CREATE TABLE IF NOT EXISTS user_skills (
+    user_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    skill   TEXT NOT NULL,
+    PRIMARY KEY (user_id, skill)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_skills_skill ON user_skills(skill);
+
+CREATE TABLE IF NOT EXISTS pull_requests (
+    pull_request_id   TEXT PRIMARY KEY,
+    pull_request_name TEXT NOT NULL,
+    author_id         TEXT NOT NULL REFERENCES users(user_id),
+    team_name         TEXT REFERENCES teams(team_name),
+    status            TEXT NOT NULL CHECK (status IN ('OPEN', 'MERGED')),
+    required_skills   TEXT NOT NULL DEFAULT '[]',
+    created_at        TEXT,
+    merged_at         TEXT,
+    version           INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_status ON pull_requests(status);
+
+CREATE TABLE IF NOT EXISTS review_assignments (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    reviewer_id     TEXT NOT NULL REFERENCES users(user_id),
+    slot            INTEGER NOT NULL,
+    assigned_at     TEXT NOT NULL,
+    assigned_reason TEXT NOT NULL,
+    assigned_by     TEXT,
+    unassigned_at   TEXT,
+    unassign_reason TEXT,
+    unassigned_by   TEXT
+);
+
+-- A reviewer can hold at most one open assignment per PR.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_review_assignments_active
+    ON review_assignments(pull_request_id, reviewer_id)
+    WHERE unassigned_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_review_assignments_reviewer_id ON review_assignments(reviewer_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_pull_requests_merged_at;
+DROP TABLE IF EXISTS review_assignments_archive;
+DROP TABLE IF EXISTS pull_requests_archive;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_pull_requests_archive_created_at;
+DROP INDEX IF EXISTS idx_pull_requests_team_created_at;
+DROP INDEX IF EXISTS idx_pull_requests_author_created_at;
+DROP INDEX IF EXISTS idx_pull_requests_status_created_at;
+DROP INDEX IF EXISTS idx_pull_requests_created_at;
@@ -0,0 +1,12 @@
+-- /pullRequest/list pages by (created_at, pull_request_id). SQLite cannot add
+-- NOT NULL to an existing column, so only the missing values are filled in.
+UPDATE pull_requests
+SET created_at = COALESCE(merged_at, strftime('%Y-%m-%dT%H:%M:%S.000000000Z', 'now'))
+WHERE created_at IS NULL;
+UPDATE pull_requests_archive SET created_at = COALESCE(merged_at, archived_at) WHERE created_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_created_at ON pull_requests(created_at, pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_status_created_at ON pull_requests(status, created_at, pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_author_created_at ON pull_requests(author_id, created_at, pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_team_created_at ON pull_requests(team_name, created_at, pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_archive_created_at ON pull_requests_archive(created_at, pull_request_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_review_assignments_active_reviewer;
+
+ALTER TABLE review_assignments_archive DROP COLUMN verdict_at;
+ALTER TABLE review_assignments_archive DROP COLUMN verdict;
+ALTER TABLE review_assignments DROP COLUMN verdict_at;
+ALTER TABLE review_assignments DROP COLUMN verdict;
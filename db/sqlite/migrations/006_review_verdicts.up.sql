@@ -0,0 +1,12 @@
+-- The verdict a reviewer submitted for their current stint on a PR.
+ALTER TABLE review_assignments ADD COLUMN verdict TEXT NOT NULL DEFAULT 'PENDING'
+    CHECK (verdict IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED', 'COMMENTED'));
+ALTER TABLE review_assignments ADD COLUMN verdict_at TEXT;
+
+ALTER TABLE review_assignments_archive ADD COLUMN verdict TEXT NOT NULL DEFAULT 'PENDING';
+ALTER TABLE review_assignments_archive ADD COLUMN verdict_at TEXT;
+
+-- /users/getReview looks up the current reviews of one user.
+CREATE INDEX IF NOT EXISTS idx_review_assignments_active_reviewer
+    ON review_assignments(reviewer_id, pull_request_id)
+    WHERE unassigned_at IS NULL;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS api_tokens (
+    token_id    TEXT PRIMARY KEY,
+    user_id     TEXT NOT NULL,
+    role        TEXT NOT NULL CHECK (role IN ('admin', 'team-lead', 'member')),
+    description TEXT NOT NULL DEFAULT '',
+    token_hash  TEXT NOT NULL UNIQUE,
+    created_at  TEXT NOT NULL,
+    expires_at  TEXT,
+    revoked_at  TEXT
+);
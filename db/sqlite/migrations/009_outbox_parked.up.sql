@@ -0,0 +1,7 @@
+-- Set once an event ran out of delivery attempts; parked events are skipped.
+ALTER TABLE outbox ADD COLUMN parked_at TEXT;
+
+-- The dispatcher reads the oldest unparked event of every aggregate.
+CREATE INDEX IF NOT EXISTS idx_outbox_aggregate
+    ON outbox(aggregate_type, aggregate_id, id)
+    WHERE parked_at IS NULL;
@@ -0,0 +1,39 @@
+-- Demo data for manual testing, same as seed/seed.sql. Safe to apply more
+-- than once.
+
+INSERT INTO teams (team_name) VALUES
+    ('backend'),
+    ('frontend')
+ON CONFLICT (team_name) DO NOTHING;
+
+INSERT INTO users (user_id, username, team_name, is_active) VALUES
+    ('u1', 'Alice',   'backend',  1),
+    ('u2', 'Bob',     'backend',  1),
+    ('u3', 'Charlie', 'backend',  0),
+    ('u4', 'Dave',    'frontend', 1),
+    ('u5', 'Eve',     'frontend', 1),
+    ('u6', 'Frank',   'frontend', 0)
+ON CONFLICT (user_id) DO NOTHING;
+
+INSERT INTO team_memberships (team_name, user_id)
+SELECT team_name, user_id FROM users WHERE user_id IN ('u1', 'u2', 'u3', 'u4', 'u5', 'u6')
+ON CONFLICT (team_name, user_id) DO NOTHING;
+
+-- %f is SS.SSS, the trailing zeros pad it to the nanosecond layout the
+-- storages write.
+INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, team_name, status, created_at, merged_at) VALUES
+    ('pr-1001', 'Add search feature', 'u1', 'backend', 'OPEN',   strftime('%Y-%m-%dT%H:%M:%f000000Z', 'now'),           NULL),
+    ('pr-1002', 'Refactor payments',  'u1', 'backend', 'MERGED', strftime('%Y-%m-%dT%H:%M:%f000000Z', 'now', '-2 days'), strftime('%Y-%m-%dT%H:%M:%f000000Z', 'now', '-1 day')),
+    ('pr-1003', 'Docs update',        'u3', 'backend', 'OPEN',   strftime('%Y-%m-%dT%H:%M:%f000000Z', 'now', '-3 days'), NULL)
+ON CONFLICT (pull_request_id) DO NOTHING;
+
+INSERT INTO review_assignments (pull_request_id, reviewer_id, slot, assigned_at, assigned_reason)
+SELECT v.column1, v.column2, v.column3, pr.created_at, 'CREATED'
+FROM (VALUES
+    ('pr-1001', 'u2', 0),
+    ('pr-1001', 'u4', 1),
+    ('pr-1002', 'u2', 0),
+    ('pr-1002', 'u5', 1)
+) AS v
+JOIN pull_requests AS pr ON pr.pull_request_id = v.column1
+WHERE NOT EXISTS (SELECT 1 FROM review_assignments AS ra WHERE ra.pull_request_id = v.column1);
@@ -1,64 +1,432 @@
 package app
 
 import (
+	api "InternshipTask"
+	"InternshipTask/db"
 	httpapp "InternshipTask/internal/app/http"
 	logger "InternshipTask/internal/app/middleware"
+	"InternshipTask/internal/domain/audit"
+	"InternshipTask/internal/domain/auth"
+	"InternshipTask/internal/domain/clock"
+	"InternshipTask/internal/domain/events"
+	"InternshipTask/internal/domain/idempotency"
+	"InternshipTask/internal/domain/logging"
+	"InternshipTask/internal/domain/offboarding"
 	"InternshipTask/internal/domain/pull_request"
 	"InternshipTask/internal/domain/team"
+	"InternshipTask/internal/domain/txn"
 	"InternshipTask/internal/domain/user"
+	"InternshipTask/internal/infrastructure/memory"
+	"InternshipTask/internal/infrastructure/postgres"
+	auditpg "InternshipTask/internal/infrastructure/postgres/audit"
+	idempotencypg "InternshipTask/internal/infrastructure/postgres/idempotency"
+	"InternshipTask/internal/infrastructure/postgres/migrate"
+	outboxpg "InternshipTask/internal/infrastructure/postgres/outbox"
 	prpg "InternshipTask/internal/infrastructure/postgres/pull_request"
 	teampg "InternshipTask/internal/infrastructure/postgres/team"
+	tokenpg "InternshipTask/internal/infrastructure/postgres/token"
 	userpg "InternshipTask/internal/infrastructure/postgres/user"
+	"InternshipTask/internal/infrastructure/sink"
+	"InternshipTask/internal/infrastructure/sqlite"
+	auditsqlite "InternshipTask/internal/infrastructure/sqlite/audit"
+	idempotencysqlite "InternshipTask/internal/infrastructure/sqlite/idempotency"
+	sqlitemigrate "InternshipTask/internal/infrastructure/sqlite/migrate"
+	outboxsqlite "InternshipTask/internal/infrastructure/sqlite/outbox"
+	prsqlite "InternshipTask/internal/infrastructure/sqlite/pull_request"
+	teamsqlite "InternshipTask/internal/infrastructure/sqlite/team"
+	tokensqlite "InternshipTask/internal/infrastructure/sqlite/token"
+	usersqlite "InternshipTask/internal/infrastructure/sqlite/user"
+	"cmp"
+	"context"
 	"fmt"
+	"log/slog"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	StoragePostgres = "postgres"
+	StorageSQLite   = "sqlite"
+	StorageMemory   = "memory"
+)
+
+const (
+	SinkLog     = "log"
+	SinkWebhook = "webhook"
+)
+
+// DefaultAuthFailureLimit lets an IP send 10 bad tokens at once and then one
+// a second.
+const DefaultAuthFailureLimit = "1:10"
+
 type Config struct {
-	PostgresDSN    string
-	ConnectTimeout time.Duration
+	// Storage selects the backend. When empty it is derived from the scheme of
+	// DatabaseDSN, see StorageFromDSN. The memory backend loses all data on
+	// restart.
+	Storage         string
+	DatabaseDSN     string
+	ConnectTimeout  time.Duration
+	MaxConns        int32
+	MinConns        int32
+	MaxConnLifetime time.Duration
+	MaxConnIdleTime time.Duration
+	ReviewSLA       time.Duration
+	// MigrateOnStart applies pending migrations before serving; SeedOnStart
+	// additionally loads the demo data.
+	MigrateOnStart bool
+	SeedOnStart    bool
+	// EventSinks lists where the outbox dispatcher delivers domain events.
+	// With no sinks the dispatcher is not started and events stay in the
+	// outbox, e.g. for a replica that should not dispatch.
+	EventSinks         []string
+	EventWebhookURL    string
+	OutboxPollInterval time.Duration
+	// OutboxMaxAttempts is how many failed deliveries park an event; zero
+	// means events.DefaultMaxAttempts.
+	OutboxMaxAttempts int
+	// RetentionDays moves PRs merged more than that many days ago to the
+	// archive every RetentionInterval. Zero keeps everything.
+	RetentionDays     int
+	RetentionInterval time.Duration
+	// IdempotencyTTL is how long the response to a POST with an
+	// Idempotency-Key is kept for replays. Zero disables the header.
+	IdempotencyTTL time.Duration
+	// AuthEnabled requires a bearer token on every route but /health and the
+	// GitHub webhook, and the actor is then taken from the token instead of
+	// X-Actor-ID.
+	// AuthAdminToken, if set, is kept as the secret of an admin token, which
+	// is how the first tokens get issued.
+	// AuthFailureLimit throttles, per IP, requests with a token that fails
+	// authentication, in the "rate:burst" form of
+	// logger.ParseAuthFailureLimit. Empty means DefaultAuthFailureLimit and
+	// "off" disables it.
+	AuthEnabled      bool
+	AuthAdminToken   string
+	AuthFailureLimit string
+	// RateLimits are token buckets per client and route group, in the
+	// "prefix=rate:burst" form of logger.ParseRateLimit. None disables rate
+	// limiting.
+	RateLimits []string
+	// OpenAPIValidation is how requests and responses are checked against
+	// openapi.yml and openapi-v2.yml, see logger.OpenAPIMode. Empty means off.
+	OpenAPIValidation string
+	// GitHubWebhookSecret enables POST /integrations/github/webhook, which
+	// accepts deliveries signed with it. GitHubLogins maps the logins of PR
+	// authors to user ids, as "login=user_id" pairs.
+	GitHubWebhookSecret string
+	GitHubLogins        []string
+	// Logger receives the request log and everything logged through the
+	// request context. Nil means slog.Default().
+	Logger *slog.Logger
+}
+
+type storages struct {
+	teams       team.Storager
+	users       user.Storager
+	prs         pull_request.Repository
+	audit       audit.Storager
+	outbox      events.Outbox
+	idempotency idempotency.Store
+	tokens      auth.Storager
+	tx          txn.Manager
 }
 
 func New(cfg Config) (*gin.Engine, error) {
-	if cfg.PostgresDSN == "" {
-		return nil, fmt.Errorf("postgres DSN is empty")
+	var (
+		st  storages
+		err error
+	)
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	background := logging.WithLogger(context.Background(), cfg.Logger)
+	if cfg.Storage == "" {
+		if cfg.Storage, err = StorageFromDSN(cfg.DatabaseDSN); err != nil {
+			return nil, err
+		}
+	}
+
+	switch cfg.Storage {
+	case StoragePostgres:
+		st, err = newPostgresStorages(cfg)
+	case StorageSQLite:
+		st, err = newSQLiteStorages(cfg)
+	case StorageMemory:
+		st = newMemoryStorages()
+	default:
+		err = fmt.Errorf("unknown storage %q", cfg.Storage)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sinks, err := newSinks(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	rateLimits := make([]logger.RateLimit, 0, len(cfg.RateLimits))
+	for _, spec := range cfg.RateLimits {
+		rl, err := logger.ParseRateLimit(spec)
+		if err != nil {
+			return nil, err
+		}
+		rateLimits = append(rateLimits, rl)
+	}
+
+	var authFailureLimit *logger.RateLimit
+	if cfg.AuthEnabled && cfg.AuthFailureLimit != "off" {
+		rl, err := logger.ParseAuthFailureLimit(cmp.Or(cfg.AuthFailureLimit, DefaultAuthFailureLimit))
+		if err != nil {
+			return nil, err
+		}
+		authFailureLimit = &rl
+	}
+
+	var githubLogins map[string]string
+	if cfg.GitHubWebhookSecret != "" {
+		if githubLogins, err = httpapp.ParseGitHubLogins(cfg.GitHubLogins); err != nil {
+			return nil, err
+		}
+	}
+
+	openAPIMode := logger.OpenAPIOff
+	if cfg.OpenAPIValidation != "" {
+		if openAPIMode, err = logger.ParseOpenAPIMode(cfg.OpenAPIValidation); err != nil {
+			return nil, err
+		}
+	}
+	var openAPIValidator *logger.OpenAPIValidator
+	if openAPIMode != logger.OpenAPIOff {
+		if openAPIValidator, err = logger.NewOpenAPIValidator(api.OpenAPI, api.OpenAPIV2); err != nil {
+			return nil, err
+		}
+	}
+
+	auditService := audit.NewService(st.audit)
+	publisher := events.NewPublisher(st.outbox, nil)
+	teamService := team.NewService(st.teams,
+		team.WithTxManager(st.tx),
+		team.WithAuditLog(auditService),
+	)
+	userService := user.NewService(st.users,
+		user.WithTxManager(st.tx),
+		user.WithAuditLog(auditService),
+		user.WithEvents(publisher),
+	)
+	prService := pull_request.NewService(st.prs, userService, teamService,
+		pull_request.WithReviewSLA(cfg.ReviewSLA),
+		pull_request.WithTxManager(st.tx),
+		pull_request.WithAuditLog(auditService),
+		pull_request.WithEvents(publisher),
+	)
+	offboardingService := offboarding.NewService(userService, prService,
+		offboarding.WithTxManager(st.tx),
+	)
+
+	if len(sinks) > 0 {
+		var opts []events.DispatcherOption
+		if cfg.OutboxPollInterval > 0 {
+			opts = append(opts, events.WithPollInterval(cfg.OutboxPollInterval))
+		}
+		if cfg.OutboxMaxAttempts > 0 {
+			opts = append(opts, events.WithMaxAttempts(cfg.OutboxMaxAttempts))
+		}
+		go events.NewDispatcher(st.outbox, sinks, opts...).Run(background)
+	}
+
+	if cfg.RetentionDays > 0 {
+		maxAge := time.Duration(cfg.RetentionDays) * 24 * time.Hour
+		go pull_request.NewRetention(prService, maxAge, cfg.RetentionInterval).Run(background)
+	}
+
+	var authService *auth.Service
+	if cfg.AuthEnabled {
+		authService = auth.NewService(st.tokens, teamService,
+			auth.WithTxManager(st.tx),
+			auth.WithAuditLog(auditService),
+		)
+		if cfg.AuthAdminToken != "" {
+			err := authService.EnsureToken(background, auth.IssueParams{
+				UserID:      "admin",
+				Role:        auth.RoleAdmin,
+				Description: "AUTH_ADMIN_TOKEN",
+				Secret:      cfg.AuthAdminToken,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("ensure admin token: %w", err)
+			}
+		}
+	}
+
+	router := gin.New()
+	router.Use(logger.LoggerMiddleware(cfg.Logger), logger.RecoveryMiddleware())
+	if authFailureLimit != nil {
+		router.Use(logger.AuthFailureLimitMiddleware(*authFailureLimit, clock.Real{}))
+	}
+	if authService != nil {
+		router.Use(logger.AuthMiddleware(authService))
+	} else {
+		router.Use(logger.ActorMiddleware())
+	}
+	if len(rateLimits) > 0 {
+		router.Use(logger.RateLimitMiddleware(rateLimits, clock.Real{}))
+	}
+	if openAPIValidator != nil {
+		router.Use(logger.OpenAPIMiddleware(openAPIValidator, openAPIMode))
+	}
+	if cfg.IdempotencyTTL > 0 {
+		router.Use(logger.IdempotencyMiddleware(st.idempotency, cfg.IdempotencyTTL, clock.Real{}))
+		go idempotency.RunExpiry(background, st.idempotency, clock.Real{}, idempotency.DefaultExpiryInterval)
+	}
+	httpapp.RegisterRoutes(router, teamService, userService, prService, offboardingService, auditService, authService)
+	if cfg.GitHubWebhookSecret != "" {
+		httpapp.RegisterGitHubWebhook(router, prService, httpapp.GitHubWebhookConfig{
+			Secret: cfg.GitHubWebhookSecret,
+			Logins: githubLogins,
+		})
+	}
+
+	return router, nil
+}
+
+// StorageFromDSN picks the backend for dsn by its scheme: postgres:// and
+// postgresql:// for Postgres, sqlite:// and file: for SQLite.
+func StorageFromDSN(dsn string) (string, error) {
+	switch {
+	case dsn == "":
+		return "", fmt.Errorf("database DSN is empty")
+	case strings.HasPrefix(dsn, "postgres://"), strings.HasPrefix(dsn, "postgresql://"):
+		return StoragePostgres, nil
+	case strings.HasPrefix(dsn, "sqlite:"), strings.HasPrefix(dsn, "file:"):
+		return StorageSQLite, nil
+	default:
+		return "", fmt.Errorf("unsupported database DSN scheme in %q", dsn)
+	}
+}
+
+func newSinks(cfg Config) ([]events.Sink, error) {
+	sinks := make([]events.Sink, 0, len(cfg.EventSinks))
+	for _, name := range cfg.EventSinks {
+		switch name {
+		case SinkLog:
+			sinks = append(sinks, sink.NewLog(os.Stdout))
+		case SinkWebhook:
+			if cfg.EventWebhookURL == "" {
+				return nil, fmt.Errorf("webhook sink needs an event webhook URL")
+			}
+			sinks = append(sinks, sink.NewWebhook(cfg.EventWebhookURL, nil))
+		default:
+			return nil, fmt.Errorf("unknown event sink %q", name)
+		}
+	}
+	return sinks, nil
+}
+
+func newPostgresStorages(cfg Config) (storages, error) {
+	if cfg.DatabaseDSN == "" {
+		return storages{}, fmt.Errorf("postgres DSN is empty")
 	}
 	if cfg.ConnectTimeout <= 0 {
 		cfg.ConnectTimeout = 5 * time.Second
 	}
 
-	teamStorage, err := teampg.NewPostgresStorage(&teampg.Config{
-		DatabaseDSN:    cfg.PostgresDSN,
-		ConnectTimeout: cfg.ConnectTimeout,
+	pool, err := postgres.NewPool(context.Background(), &postgres.Config{
+		DatabaseDSN:     cfg.DatabaseDSN,
+		ConnectTimeout:  cfg.ConnectTimeout,
+		MaxConns:        cfg.MaxConns,
+		MinConns:        cfg.MinConns,
+		MaxConnLifetime: cfg.MaxConnLifetime,
+		MaxConnIdleTime: cfg.MaxConnIdleTime,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("init team storage: %w", err)
+		return storages{}, fmt.Errorf("init postgres pool: %w", err)
 	}
 
-	userStorage, err := userpg.NewPostgresStorage(&userpg.Config{
-		DatabaseDSN:    cfg.PostgresDSN,
-		ConnectTimeout: cfg.ConnectTimeout,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("init user storage: %w", err)
+	if cfg.MigrateOnStart || cfg.SeedOnStart {
+		migrator, err := migrate.New(pool, db.Migrations())
+		if err != nil {
+			return storages{}, fmt.Errorf("load migrations: %w", err)
+		}
+		if cfg.MigrateOnStart {
+			if _, err := migrator.Up(context.Background()); err != nil {
+				return storages{}, fmt.Errorf("apply migrations: %w", err)
+			}
+		}
+		if cfg.SeedOnStart {
+			if err := migrator.Seed(context.Background(), db.Seed); err != nil {
+				return storages{}, fmt.Errorf("seed: %w", err)
+			}
+		}
 	}
 
-	prStorage, err := prpg.NewPostgresStorage(&prpg.Config{
-		DatabaseDSN:    cfg.PostgresDSN,
-		ConnectTimeout: cfg.ConnectTimeout,
-	})
+	return storages{
+		teams:       teampg.NewPostgresStorage(pool),
+		users:       userpg.NewPostgresStorage(pool),
+		prs:         prpg.NewPostgresStorage(pool),
+		audit:       auditpg.NewPostgresStorage(pool),
+		outbox:      outboxpg.NewPostgresStorage(pool),
+		idempotency: idempotencypg.NewPostgresStorage(pool),
+		tokens:      tokenpg.NewPostgresStorage(pool),
+		tx:          postgres.NewTxManager(pool),
+	}, nil
+}
+
+func newSQLiteStorages(cfg Config) (storages, error) {
+	if cfg.ConnectTimeout <= 0 {
+		cfg.ConnectTimeout = 5 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout)
+	defer cancel()
+
+	conn, err := sqlite.Open(ctx, cfg.DatabaseDSN)
 	if err != nil {
-		return nil, fmt.Errorf("init pull_request storage: %w", err)
+		return storages{}, fmt.Errorf("init sqlite: %w", err)
 	}
 
-	teamService := team.NewService(teamStorage)
-	userService := user.NewService(userStorage)
-	prService := pull_request.NewService(prStorage, userService, teamService)
+	if cfg.MigrateOnStart || cfg.SeedOnStart {
+		migrator, err := sqlitemigrate.New(conn, db.SQLiteMigrations())
+		if err != nil {
+			return storages{}, fmt.Errorf("load migrations: %w", err)
+		}
+		if cfg.MigrateOnStart {
+			if _, err := migrator.Up(context.Background()); err != nil {
+				return storages{}, fmt.Errorf("apply migrations: %w", err)
+			}
+		}
+		if cfg.SeedOnStart {
+			if err := migrator.Seed(context.Background(), db.SQLiteSeed); err != nil {
+				return storages{}, fmt.Errorf("seed: %w", err)
+			}
+		}
+	}
 
-	router := gin.Default()
-	router.Use(logger.LoggerMiddleware())
-	httpapp.RegisterRoutes(router, teamService, userService, prService)
+	return storages{
+		teams:       teamsqlite.NewSQLiteStorage(conn),
+		users:       usersqlite.NewSQLiteStorage(conn),
+		prs:         prsqlite.NewSQLiteStorage(conn),
+		audit:       auditsqlite.NewSQLiteStorage(conn),
+		outbox:      outboxsqlite.NewSQLiteStorage(conn),
+		idempotency: idempotencysqlite.NewSQLiteStorage(conn),
+		tokens:      tokensqlite.NewSQLiteStorage(conn),
+		tx:          sqlite.NewTxManager(conn),
+	}, nil
+}
 
-	return router, nil
+func newMemoryStorages() storages {
+	memDB := memory.NewDB()
+	return storages{
+		teams:       memory.NewTeamStorage(memDB),
+		users:       memory.NewUserStorage(memDB),
+		prs:         memory.NewPullRequestStorage(memDB),
+		audit:       memory.NewAuditStorage(memDB),
+		outbox:      memory.NewOutboxStorage(memDB),
+		idempotency: memory.NewIdempotencyStorage(memDB),
+		tokens:      memory.NewTokenStorage(memDB),
+		tx:          txn.NewMemory(),
+	}
 }
@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditEntryDTO struct {
+	ID         int64           `json:"id"`
+	At         time.Time       `json:"at"`
+	Actor      string          `json:"actor,omitempty"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+}
+
+type AuditLogResponse struct {
+	Entries []AuditEntryDTO `json:"entries"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+type IssueTokenRequest struct {
+	UserID         string `json:"user_id" binding:"required"`
+	Role           string `json:"role" binding:"required"`
+	Description    string `json:"description"`
+	ExpiresInHours int    `json:"expires_in_hours" binding:"min=0"`
+}
+
+type RevokeTokenRequest struct {
+	TokenID string `json:"token_id" binding:"required"`
+}
+
+type TokenDTO struct {
+	TokenID     string     `json:"token_id"`
+	UserID      string     `json:"user_id"`
+	Role        string     `json:"role"`
+	Description string     `json:"description"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+}
+
+// IssueTokenResponse is the only place the secret of a token is ever shown.
+type IssueTokenResponse struct {
+	Token  TokenDTO `json:"token"`
+	Secret string   `json:"secret"`
+}
@@ -0,0 +1,33 @@
+package dto
+
+// GitHubPullRequestEvent holds the fields of a GitHub pull_request webhook
+// payload that the service uses; the rest of the payload is ignored.
+type GitHubPullRequestEvent struct {
+	Action      string            `json:"action"`
+	Number      int               `json:"number"`
+	PullRequest GitHubPullRequest `json:"pull_request"`
+	Repository  GitHubRepository  `json:"repository"`
+}
+
+type GitHubPullRequest struct {
+	Title  string        `json:"title"`
+	Draft  bool          `json:"draft"`
+	Merged bool          `json:"merged"`
+	User   GitHubAccount `json:"user"`
+}
+
+type GitHubRepository struct {
+	FullName string `json:"full_name"`
+}
+
+type GitHubAccount struct {
+	Login string `json:"login"`
+}
+
+// GitHubWebhookResponse tells GitHub, and whoever reads its delivery log,
+// what became of an event.
+type GitHubWebhookResponse struct {
+	Result        string `json:"result"`
+	PullRequestID string `json:"pull_request_id,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
@@ -3,9 +3,11 @@ package dto
 import "time"
 
 type CreatePullRequestRequest struct {
-	PullRequestID   string `json:"pull_request_id" binding:"required"`
-	PullRequestName string `json:"pull_request_name" binding:"required"`
-	AuthorID        string `json:"author_id" binding:"required"`
+	PullRequestID   string   `json:"pull_request_id" binding:"required"`
+	PullRequestName string   `json:"pull_request_name" binding:"required"`
+	AuthorID        string   `json:"author_id" binding:"required"`
+	TeamName        string   `json:"team_name"`
+	RequiredSkills  []string `json:"required_skills,omitempty"`
 }
 
 type MergePullRequestRequest struct {
@@ -21,15 +23,71 @@ type PullRequestDTO struct {
 	PullRequestID     string     `json:"pull_request_id"`
 	PullRequestName   string     `json:"pull_request_name"`
 	AuthorID          string     `json:"author_id"`
+	TeamName          string     `json:"team_name,omitempty"`
 	Status            string     `json:"status"`
 	AssignedReviewers []string   `json:"assigned_reviewers"`
+	RequiredSkills    []string   `json:"required_skills,omitempty"`
 	CreatedAt         *time.Time `json:"createdAt,omitempty"`
 	MergedAt          *time.Time `json:"mergedAt,omitempty"`
 }
 
+// PullRequestUserDTO is a user inlined into a PR by expand, with the number
+// of OPEN PRs they currently review.
+type PullRequestUserDTO struct {
+	UserDTO
+	OpenReviews int64 `json:"open_reviews"`
+}
+
+type PullRequestDetailsDTO struct {
+	PullRequestDTO
+	Author    *PullRequestUserDTO  `json:"author,omitempty"`
+	Reviewers []PullRequestUserDTO `json:"reviewers,omitempty"`
+}
+
+type PullRequestListResponse struct {
+	PullRequests []PullRequestDTO `json:"pull_requests"`
+	NextCursor   string           `json:"next_cursor,omitempty"`
+}
+
 type PullRequestShortDTO struct {
 	PullRequestID   string `json:"pull_request_id"`
 	PullRequestName string `json:"pull_request_name"`
 	AuthorID        string `json:"author_id"`
 	Status          string `json:"status"`
 }
+
+type ReviewerSLADTO struct {
+	UserID       string  `json:"user_id"`
+	TimeZone     string  `json:"time_zone"`
+	WorkingHours float64 `json:"working_hours"`
+	Breached     bool    `json:"breached"`
+}
+
+type ReviewSLADTO struct {
+	PullRequestID string           `json:"pull_request_id"`
+	SLAHours      float64          `json:"sla_hours"`
+	Reviewers     []ReviewerSLADTO `json:"reviewers"`
+}
+
+type ReviewAssignmentDTO struct {
+	ReviewerID     string     `json:"reviewer_id"`
+	AssignedAt     time.Time  `json:"assigned_at"`
+	AssignedReason string     `json:"assigned_reason"`
+	AssignedBy     string     `json:"assigned_by,omitempty"`
+	UnassignedAt   *time.Time `json:"unassigned_at,omitempty"`
+	UnassignReason string     `json:"unassign_reason,omitempty"`
+	UnassignedBy   string     `json:"unassigned_by,omitempty"`
+	Verdict        string     `json:"verdict"`
+	VerdictAt      *time.Time `json:"verdict_at,omitempty"`
+}
+
+type SetVerdictRequest struct {
+	PullRequestID string `json:"pull_request_id" binding:"required"`
+	UserID        string `json:"user_id" binding:"required"`
+	Verdict       string `json:"verdict" binding:"required"`
+}
+
+type ReviewAssignmentsResponse struct {
+	PullRequestID string                `json:"pull_request_id"`
+	Assignments   []ReviewAssignmentDTO `json:"assignments"`
+}
@@ -3,10 +3,17 @@ package dto
 type TeamMemberDTO struct {
 	UserID   string `json:"user_id" binding:"required"`
 	Username string `json:"username" binding:"required"`
-	IsActive bool   `json:"is_active" binding:"required"`
+	// Not binding:"required": that rejects false. Presence is checked
+	// against openapi.yml by OpenAPIMiddleware.
+	IsActive bool `json:"is_active"`
 }
 
 type TeamDTO struct {
 	TeamName string          `json:"team_name" binding:"required"`
 	Members  []TeamMemberDTO `json:"members" binding:"required"`
 }
+
+type TeamMembershipRequest struct {
+	TeamName string `json:"team_name" binding:"required"`
+	UserID   string `json:"user_id" binding:"required"`
+}
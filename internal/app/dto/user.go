@@ -1,18 +1,71 @@
 package dto
 
+import "time"
+
 type SetUserActiveRequest struct {
 	UserID   string `json:"user_id" binding:"required"`
 	IsActive bool   `json:"is_active"`
 }
 
+type SetUserSkillsRequest struct {
+	UserID string   `json:"user_id" binding:"required"`
+	Skills []string `json:"skills" binding:"required"`
+}
+
+type SetUserScheduleRequest struct {
+	UserID    string `json:"user_id" binding:"required"`
+	TimeZone  string `json:"time_zone" binding:"required"`
+	WorkStart string `json:"work_start" binding:"required"`
+	WorkEnd   string `json:"work_end" binding:"required"`
+}
+
 type UserDTO struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	UserID     string     `json:"user_id"`
+	Username   string     `json:"username"`
+	TeamName   string     `json:"team_name"`
+	IsActive   bool       `json:"is_active"`
+	Skills     []string   `json:"skills,omitempty"`
+	TimeZone   string     `json:"time_zone,omitempty"`
+	WorkStart  string     `json:"work_start,omitempty"`
+	WorkEnd    string     `json:"work_end,omitempty"`
+	DepartedAt *time.Time `json:"departed_at,omitempty"`
+}
+
+// UserReviewDTO is a PR the user currently reviews, with how long they have
+// had it and their verdict.
+type UserReviewDTO struct {
+	PullRequestShortDTO
+	TeamName   string     `json:"team_name,omitempty"`
+	CreatedAt  *time.Time `json:"createdAt,omitempty"`
+	AssignedAt time.Time  `json:"assigned_at"`
+	AgeHours   float64    `json:"age_hours"`
+	Verdict    string     `json:"verdict"`
+	VerdictAt  *time.Time `json:"verdict_at,omitempty"`
 }
 
 type UserReviewsResponse struct {
-	UserID       string                `json:"user_id"`
-	PullRequests []PullRequestShortDTO `json:"pull_requests"`
+	UserID       string          `json:"user_id"`
+	PullRequests []UserReviewDTO `json:"pull_requests"`
+	NextCursor   string          `json:"next_cursor,omitempty"`
+}
+
+type UserTeamsResponse struct {
+	UserID string   `json:"user_id"`
+	Teams  []string `json:"teams"`
+}
+
+type OffboardUserRequest struct {
+	UserID     string `json:"user_id" binding:"required"`
+	TransferTo string `json:"transfer_authorship_to"`
+}
+
+type OffboardReassignmentDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReplacedBy    string `json:"replaced_by,omitempty"`
+}
+
+type OffboardUserResponse struct {
+	User        UserDTO                   `json:"user"`
+	Reassigned  []OffboardReassignmentDTO `json:"reassigned"`
+	Transferred []string                  `json:"transferred"`
 }
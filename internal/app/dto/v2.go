@@ -0,0 +1,49 @@
+package dto
+
+import "time"
+
+// The /v2 API takes the request bodies of the legacy routes where they fit
+// and returns resources without a wrapping object.
+
+// PullRequestV2DTO is PullRequestDTO with snake_case timestamps.
+type PullRequestV2DTO struct {
+	PullRequestID     string     `json:"pull_request_id"`
+	PullRequestName   string     `json:"pull_request_name"`
+	AuthorID          string     `json:"author_id"`
+	TeamName          string     `json:"team_name,omitempty"`
+	Status            string     `json:"status"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	RequiredSkills    []string   `json:"required_skills,omitempty"`
+	CreatedAt         *time.Time `json:"created_at,omitempty"`
+	MergedAt          *time.Time `json:"merged_at,omitempty"`
+	// SkillFallback is only reported when the PR is created.
+	SkillFallback bool `json:"skill_fallback,omitempty"`
+}
+
+type PullRequestListV2Response struct {
+	PullRequests []PullRequestV2DTO `json:"pull_requests"`
+	NextCursor   string             `json:"next_cursor,omitempty"`
+}
+
+type TeamListResponse struct {
+	Teams []TeamDTO `json:"teams"`
+}
+
+// UpdateUserRequest is a partial update of a user: absent fields are left as
+// they are. The schedule fields go together.
+type UpdateUserRequest struct {
+	IsActive  *bool    `json:"is_active"`
+	Skills    []string `json:"skills"`
+	TimeZone  *string  `json:"time_zone"`
+	WorkStart *string  `json:"work_start"`
+	WorkEnd   *string  `json:"work_end"`
+}
+
+type ReplaceReviewerRequest struct {
+	OldReviewerID string `json:"old_reviewer_id" binding:"required"`
+}
+
+type ReplaceReviewerResponse struct {
+	PullRequest PullRequestV2DTO `json:"pull_request"`
+	ReplacedBy  string           `json:"replaced_by"`
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"InternshipTask/internal/app/dto"
+	"InternshipTask/internal/domain/audit"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) getAuditLog(c *gin.Context) {
+	f := audit.Filter{
+		EntityType: audit.EntityType(c.Query("entity_type")),
+		EntityID:   c.Query("entity_id"),
+		Actor:      c.Query("actor"),
+	}
+
+	if !timeQuery(c, map[string]*time.Time{"from": &f.From, "to": &f.To}) {
+		return
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			writeError(c, http.StatusBadRequest, "INVALID_REQUEST", "limit must be a positive integer")
+			return
+		}
+		f.Limit = limit
+	}
+
+	entries, err := h.auditService.List(c.Request.Context(), f)
+	if err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	resp := dto.AuditLogResponse{Entries: make([]dto.AuditEntryDTO, 0, len(entries))}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, dto.AuditEntryDTO{
+			ID:         e.ID,
+			At:         e.At,
+			Actor:      e.Actor,
+			Action:     string(e.Action),
+			EntityType: string(e.EntityType),
+			EntityID:   e.EntityID,
+			Before:     e.Before,
+			After:      e.After,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
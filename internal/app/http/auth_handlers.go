@@ -0,0 +1,144 @@
+package http
+
+import (
+	"InternshipTask/internal/app/dto"
+	"InternshipTask/internal/domain/auth"
+	"InternshipTask/internal/domain/pull_request"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const permissionKey = "permission"
+
+// require declares the permission a route needs. It rejects callers whose
+// role lacks it altogether; handlers of routes whose permission depends on
+// the resource check the rest with authorize. Without an auth service every
+// route is open.
+func (h *Handler) require(perm auth.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h.authService == nil {
+			c.Next()
+			return
+		}
+
+		p := auth.FromContext(c.Request.Context())
+		if p == nil {
+			writeDomainError(c, auth.ErrUnauthenticated)
+			c.Abort()
+			return
+		}
+		if !p.Role.Allows(perm) {
+			writeDomainError(c, auth.ErrForbidden)
+			c.Abort()
+			return
+		}
+
+		c.Set(permissionKey, perm)
+		c.Next()
+	}
+}
+
+// authorize checks the route's permission against res. On a denial it writes
+// a 403 and returns false.
+func (h *Handler) authorize(c *gin.Context, res auth.Resource) bool {
+	if h.authService == nil {
+		return true
+	}
+
+	ctx := c.Request.Context()
+	perm, _ := c.Get(permissionKey)
+	if err := h.authService.Authorize(ctx, auth.FromContext(ctx), perm.(auth.Permission), res); err != nil {
+		writeDomainError(c, err)
+		return false
+	}
+	return true
+}
+
+// authorizePR is authorize for a resource in the team of PR prID.
+func (h *Handler) authorizePR(c *gin.Context, prID, userID string) bool {
+	if h.authService == nil {
+		return true
+	}
+
+	d, err := h.prService.GetDetails(c.Request.Context(), prID, pull_request.Expand{})
+	if err != nil {
+		writeDomainError(c, err)
+		return false
+	}
+	return h.authorize(c, auth.Resource{UserID: userID, Team: d.PR.TeamName})
+}
+
+func (h *Handler) issueToken(c *gin.Context) {
+	var req dto.IssueTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	t, secret, err := h.authService.Issue(c.Request.Context(), auth.IssueParams{
+		UserID:      req.UserID,
+		Role:        auth.Role(req.Role),
+		Description: req.Description,
+		TTL:         time.Duration(req.ExpiresInHours) * time.Hour,
+	})
+	if err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	// Keeps the secret out of caches and the idempotency store.
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, dto.IssueTokenResponse{
+		Token:  toTokenDTO(t),
+		Secret: secret,
+	})
+}
+
+func (h *Handler) listTokens(c *gin.Context) {
+	tokens, err := h.authService.List(c.Request.Context())
+	if err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	result := make([]dto.TokenDTO, 0, len(tokens))
+	for i := range tokens {
+		result = append(result, toTokenDTO(&tokens[i]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tokens": result,
+	})
+}
+
+func (h *Handler) revokeToken(c *gin.Context) {
+	var req dto.RevokeTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	t, err := h.authService.Revoke(c.Request.Context(), req.TokenID)
+	if err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token": toTokenDTO(t),
+	})
+}
+
+func toTokenDTO(t *auth.Token) dto.TokenDTO {
+	return dto.TokenDTO{
+		TokenID:     t.ID,
+		UserID:      t.UserID,
+		Role:        string(t.Role),
+		Description: t.Description,
+		CreatedAt:   t.CreatedAt,
+		ExpiresAt:   t.ExpiresAt,
+		RevokedAt:   t.RevokedAt,
+	}
+}
@@ -2,11 +2,22 @@ package http
 
 import (
 	"InternshipTask/internal/app/dto"
+	logger "InternshipTask/internal/app/middleware"
+	"InternshipTask/internal/domain/auth"
+	"InternshipTask/internal/domain/logging"
+	"InternshipTask/internal/domain/offboarding"
+	"InternshipTask/internal/domain/pull_request"
+	"InternshipTask/internal/domain/team"
+	"InternshipTask/internal/domain/user"
+	"errors"
+	"log/slog"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
 func writeError(c *gin.Context, status int, code, message string) {
+	c.Set(logger.ErrorCodeKey, code)
 	c.JSON(status, dto.ErrorDTO{
 		Error: dto.ErrorContent{
 			Code:    code,
@@ -14,3 +25,59 @@ func writeError(c *gin.Context, status int, code, message string) {
 		},
 	})
 }
+
+// domainError maps a domain sentinel to the status and code from openapi.yml.
+type domainError struct {
+	err    error
+	status int
+	code   string
+	// detailed reports the whole error chain instead of the sentinel text,
+	// for validation errors whose details the client needs.
+	detailed bool
+}
+
+var domainErrors = []domainError{
+	{err: pull_request.ErrPRExists, status: http.StatusConflict, code: "PR_EXISTS"},
+	{err: pull_request.ErrPRMerged, status: http.StatusConflict, code: "PR_MERGED"},
+	{err: pull_request.ErrNotAssigned, status: http.StatusConflict, code: "NOT_ASSIGNED"},
+	{err: pull_request.ErrNoCandidate, status: http.StatusConflict, code: "NO_CANDIDATE"},
+	{err: pull_request.ErrConflict, status: http.StatusConflict, code: "CONFLICT"},
+	{err: pull_request.ErrAuthorNotInTeam, status: http.StatusConflict, code: "NOT_MEMBER"},
+	{err: pull_request.ErrNotFound, status: http.StatusNotFound, code: "NOT_FOUND"},
+	{err: user.ErrUserNotFound, status: http.StatusNotFound, code: "NOT_FOUND"},
+	{err: team.ErrTeamNotFound, status: http.StatusNotFound, code: "NOT_FOUND"},
+	{err: team.ErrNotMember, status: http.StatusNotFound, code: "NOT_FOUND"},
+	{err: team.ErrTeamExists, status: http.StatusBadRequest, code: "TEAM_EXISTS"},
+	{err: team.ErrPrimaryMembership, status: http.StatusConflict, code: "PRIMARY_TEAM"},
+	{err: user.ErrUserDeparted, status: http.StatusConflict, code: "USER_DEPARTED"},
+	{err: user.ErrInvalidSkill, status: http.StatusBadRequest, code: "INVALID_REQUEST", detailed: true},
+	{err: user.ErrInvalidSchedule, status: http.StatusBadRequest, code: "INVALID_REQUEST", detailed: true},
+	{err: pull_request.ErrInvalidFilter, status: http.StatusBadRequest, code: "INVALID_REQUEST", detailed: true},
+	{err: pull_request.ErrInvalidVerdict, status: http.StatusBadRequest, code: "INVALID_REQUEST", detailed: true},
+	{err: pull_request.ErrInvalidCursor, status: http.StatusBadRequest, code: "INVALID_REQUEST", detailed: true},
+	{err: offboarding.ErrInvalidTransferTarget, status: http.StatusBadRequest, code: "INVALID_REQUEST", detailed: true},
+	{err: auth.ErrUnauthenticated, status: http.StatusUnauthorized, code: "UNAUTHORIZED"},
+	{err: auth.ErrForbidden, status: http.StatusForbidden, code: "FORBIDDEN", detailed: true},
+	{err: auth.ErrTokenNotFound, status: http.StatusNotFound, code: "NOT_FOUND"},
+	{err: auth.ErrInvalidToken, status: http.StatusBadRequest, code: "INVALID_REQUEST", detailed: true},
+}
+
+// writeDomainError is the single place where errors from the domain services
+// become HTTP responses. Anything it does not know is a 500 INTERNAL.
+func writeDomainError(c *gin.Context, err error) {
+	for _, m := range domainErrors {
+		if !errors.Is(err, m.err) {
+			continue
+		}
+		message := m.err.Error()
+		if m.detailed {
+			message = err.Error()
+		}
+		writeError(c, m.status, m.code, message)
+		return
+	}
+
+	ctx := c.Request.Context()
+	logging.FromContext(ctx).ErrorContext(ctx, "internal error", slog.Any("err", err))
+	writeError(c, http.StatusInternalServerError, "INTERNAL", err.Error())
+}
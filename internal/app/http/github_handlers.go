@@ -0,0 +1,176 @@
+package http
+
+import (
+	"InternshipTask/internal/app/dto"
+	"InternshipTask/internal/domain/actor"
+	"InternshipTask/internal/domain/pull_request"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	GitHubEventHeader     = "X-GitHub-Event"
+	GitHubSignatureHeader = "X-Hub-Signature-256"
+
+	// GitHubActor is the actor of the changes made on behalf of webhooks.
+	GitHubActor = "github"
+)
+
+// GitHubWebhookConfig configures POST /integrations/github/webhook.
+type GitHubWebhookConfig struct {
+	// Secret is the webhook secret set on the GitHub side; every delivery
+	// must be signed with it.
+	Secret string
+	// Logins maps GitHub logins, in lower case, to user ids. PRs of
+	// unmapped authors are ignored.
+	Logins map[string]string
+}
+
+type githubWebhook struct {
+	prService *pull_request.Service
+	cfg       GitHubWebhookConfig
+}
+
+// RegisterGitHubWebhook adds the GitHub webhook route. It does not need a
+// token: deliveries are authenticated by their signature instead.
+func RegisterGitHubWebhook(r *gin.Engine, prSvc *pull_request.Service, cfg GitHubWebhookConfig) {
+	h := &githubWebhook{prService: prSvc, cfg: cfg}
+	r.POST("/integrations/github/webhook", h.handle)
+}
+
+// ParseGitHubLogins reads "login=user_id" pairs into the map of
+// GitHubWebhookConfig.Logins.
+func ParseGitHubLogins(specs []string) (map[string]string, error) {
+	logins := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		login, userID, ok := strings.Cut(spec, "=")
+		login, userID = strings.TrimSpace(login), strings.TrimSpace(userID)
+		if !ok || login == "" || userID == "" {
+			return nil, fmt.Errorf("github login %q: want login=user_id", spec)
+		}
+		logins[strings.ToLower(login)] = userID
+	}
+	return logins, nil
+}
+
+func (h *githubWebhook) handle(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", "read body: "+err.Error())
+		return
+	}
+	if !validGitHubSignature(h.cfg.Secret, c.GetHeader(GitHubSignatureHeader), body) {
+		writeError(c, http.StatusUnauthorized, "UNAUTHORIZED", "missing or invalid "+GitHubSignatureHeader)
+		return
+	}
+
+	if event := c.GetHeader(GitHubEventHeader); event != "pull_request" {
+		ignore(c, "", "unsupported event "+event)
+		return
+	}
+
+	var ev dto.GitHubPullRequestEvent
+	if err := json.Unmarshal(body, &ev); err != nil {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", "decode pull_request event: "+err.Error())
+		return
+	}
+	if ev.Repository.FullName == "" || ev.Number <= 0 {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", "pull_request event without repository or number")
+		return
+	}
+
+	c.Request = c.Request.WithContext(actor.WithID(c.Request.Context(), GitHubActor))
+	id := fmt.Sprintf("%s#%d", ev.Repository.FullName, ev.Number)
+
+	switch ev.Action {
+	case "opened", "reopened", "ready_for_review":
+		h.create(c, id, ev)
+	case "closed":
+		h.merge(c, id, ev)
+	default:
+		ignore(c, id, "unsupported action "+ev.Action)
+	}
+}
+
+// create starts tracking a PR once it is open for review. Drafts wait for
+// ready_for_review.
+func (h *githubWebhook) create(c *gin.Context, id string, ev dto.GitHubPullRequestEvent) {
+	if ev.PullRequest.Draft {
+		ignore(c, id, "draft")
+		return
+	}
+	login := ev.PullRequest.User.Login
+	authorID, ok := h.cfg.Logins[strings.ToLower(login)]
+	if !ok {
+		ignore(c, id, "no user for GitHub login "+login)
+		return
+	}
+
+	_, err := h.prService.Create(c.Request.Context(), pull_request.CreateParams{
+		ID:       id,
+		Name:     ev.PullRequest.Title,
+		AuthorID: authorID,
+	})
+	if errors.Is(err, pull_request.ErrPRExists) {
+		ignore(c, id, "already tracked")
+		return
+	}
+	if err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GitHubWebhookResponse{Result: "created", PullRequestID: id})
+}
+
+// merge marks a merged PR as MERGED. A PR closed without merging stays OPEN:
+// there is no such status here.
+func (h *githubWebhook) merge(c *gin.Context, id string, ev dto.GitHubPullRequestEvent) {
+	if !ev.PullRequest.Merged {
+		ignore(c, id, "closed without merge")
+		return
+	}
+
+	_, err := h.prService.Merge(c.Request.Context(), id)
+	if errors.Is(err, pull_request.ErrNotFound) {
+		ignore(c, id, "not tracked")
+		return
+	}
+	if err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GitHubWebhookResponse{Result: "merged", PullRequestID: id})
+}
+
+// ignore acknowledges an event that needs no change, so that GitHub does not
+// report the delivery as failed.
+func ignore(c *gin.Context, id, reason string) {
+	c.JSON(http.StatusOK, dto.GitHubWebhookResponse{Result: "ignored", PullRequestID: id, Reason: reason})
+}
+
+// validGitHubSignature checks header, "sha256=" and the hex HMAC-SHA256 of
+// body, against secret.
+func validGitHubSignature(secret, header string, body []byte) bool {
+	sig, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
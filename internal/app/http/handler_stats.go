@@ -12,9 +12,14 @@ func (h *Handler) health(c *gin.Context) {
 }
 
 func (h *Handler) stats(c *gin.Context) {
-	stats, err := h.prService.ReviewerStats(c.Request.Context())
+	archived, ok := includeArchived(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.prService.ReviewerStats(c.Request.Context(), archived)
 	if err != nil {
-		writeError(c, http.StatusInternalServerError, "INTERNAL", err.Error())
+		writeDomainError(c, err)
 		return
 	}
 
@@ -22,5 +27,3 @@ func (h *Handler) stats(c *gin.Context) {
 		ReviewAssignments: stats,
 	})
 }
-
-
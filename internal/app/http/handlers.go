@@ -1,37 +1,127 @@
 package http
 
 import (
+	"InternshipTask/internal/domain/audit"
+	"InternshipTask/internal/domain/auth"
+	"InternshipTask/internal/domain/offboarding"
 	"InternshipTask/internal/domain/pull_request"
 	"InternshipTask/internal/domain/team"
 	"InternshipTask/internal/domain/user"
+	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Handler struct {
-	teamService *team.Service
-	userService *user.Service
-	prService   *pull_request.Service
+	teamService        *team.Service
+	userService        *user.Service
+	prService          *pull_request.Service
+	offboardingService *offboarding.Service
+	auditService       *audit.Service
+	authService        *auth.Service
 }
 
-func RegisterRoutes(r *gin.Engine, teamSvc *team.Service, userSvc *user.Service, prSvc *pull_request.Service) {
+// RegisterRoutes wires the handlers, each behind the permission it needs.
+// With a nil authSvc authentication is off and every route is open.
+func RegisterRoutes(r *gin.Engine, teamSvc *team.Service, userSvc *user.Service, prSvc *pull_request.Service, offboardingSvc *offboarding.Service, auditSvc *audit.Service, authSvc *auth.Service) {
 	h := &Handler{
-		teamService: teamSvc,
-		userService: userSvc,
-		prService:   prSvc,
+		teamService:        teamSvc,
+		userService:        userSvc,
+		prService:          prSvc,
+		offboardingService: offboardingSvc,
+		auditService:       auditSvc,
+		authService:        authSvc,
 	}
 
 	r.GET("/health", h.health)
 
-	r.GET("/stats", h.stats)
+	r.GET("/stats", h.require(auth.PermRead), h.stats)
 
-	r.POST("/team/add", h.createTeam)
-	r.GET("/team/get", h.getTeam)
+	r.POST("/team/add", h.require(auth.PermCreateTeam), h.createTeam)
+	r.GET("/team/get", h.require(auth.PermRead), h.getTeam)
+	r.POST("/team/addMember", h.require(auth.PermManageTeam), h.addTeamMember)
+	r.POST("/team/removeMember", h.require(auth.PermManageTeam), h.removeTeamMember)
 
-	r.POST("/users/setIsActive", h.setUserIsActive)
-	r.GET("/users/getReview", h.getUserReviews)
+	r.POST("/users/setIsActive", h.require(auth.PermManageUser), h.setUserIsActive)
+	r.POST("/users/setSkills", h.require(auth.PermManageUser), h.setUserSkills)
+	r.POST("/users/setSchedule", h.require(auth.PermManageUser), h.setUserSchedule)
+	r.POST("/users/offboard", h.require(auth.PermManageUser), h.offboardUser)
+	r.GET("/users/getReview", h.require(auth.PermRead), h.getUserReviews)
+	r.GET("/users/getTeams", h.require(auth.PermRead), h.getUserTeams)
 
-	r.POST("/pullRequest/create", h.createPullRequest)
-	r.POST("/pullRequest/merge", h.mergePullRequest)
-	r.POST("/pullRequest/reassign", h.reassignPullRequest)
+	r.GET("/pullRequest/get", h.require(auth.PermRead), h.getPullRequest)
+	r.GET("/pullRequest/list", h.require(auth.PermRead), h.listPullRequests)
+	r.POST("/pullRequest/create", h.require(auth.PermManagePR), h.createPullRequest)
+	r.POST("/pullRequest/merge", h.require(auth.PermManagePR), h.mergePullRequest)
+	r.POST("/pullRequest/reassign", h.require(auth.PermReassign), h.reassignPullRequest)
+	r.POST("/pullRequest/verdict", h.require(auth.PermVerdict), h.setPullRequestVerdict)
+	r.GET("/pullRequest/sla", h.require(auth.PermRead), h.getPullRequestSLA)
+	r.GET("/pullRequest/assignments", h.require(auth.PermRead), h.getPullRequestAssignments)
+
+	r.GET("/audit", h.require(auth.PermRead), h.getAuditLog)
+
+	registerV2Routes(r, h)
+
+	if authSvc != nil {
+		r.POST("/admin/tokens/create", h.require(auth.PermManageTokens), h.issueToken)
+		r.GET("/admin/tokens/list", h.require(auth.PermManageTokens), h.listTokens)
+		r.POST("/admin/tokens/revoke", h.require(auth.PermManageTokens), h.revokeToken)
+	}
+}
+
+// includeArchived reads the optional include_archived query parameter. On a
+// malformed value it writes a 400 and reports false as its second result.
+func includeArchived(c *gin.Context) (bool, bool) {
+	v := c.Query("include_archived")
+	if v == "" {
+		return false, true
+	}
+	include, err := strconv.ParseBool(v)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", "include_archived must be a boolean")
+		return false, false
+	}
+	return include, true
+}
+
+// timeQuery reads the optional RFC 3339 query parameters into their
+// destinations. On a malformed value it writes a 400 and returns false.
+func timeQuery(c *gin.Context, params map[string]*time.Time) bool {
+	for param, dst := range params {
+		if v := c.Query(param); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				writeError(c, http.StatusBadRequest, "INVALID_REQUEST", param+" must be an RFC 3339 time")
+				return false
+			}
+			*dst = t
+		}
+	}
+	return true
+}
+
+// pageQuery reads the optional limit and cursor query parameters. On a
+// malformed value it writes a 400 and reports false as its last result.
+func pageQuery(c *gin.Context) (int, *pull_request.Cursor, bool) {
+	var limit int
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(c, http.StatusBadRequest, "INVALID_REQUEST", "limit must be a positive integer")
+			return 0, nil, false
+		}
+		limit = n
+	}
+
+	if v := c.Query("cursor"); v != "" {
+		cursor, err := pull_request.ParseCursor(v)
+		if err != nil {
+			writeDomainError(c, err)
+			return 0, nil, false
+		}
+		return limit, &cursor, true
+	}
+	return limit, nil, true
 }
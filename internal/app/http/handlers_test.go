@@ -1,134 +1,154 @@
 package http
 
 import (
+	api "InternshipTask"
 	"InternshipTask/internal/app/dto"
+	logger "InternshipTask/internal/app/middleware"
+	"InternshipTask/internal/domain/audit"
+	"InternshipTask/internal/domain/auth"
+	"InternshipTask/internal/domain/offboarding"
 	"InternshipTask/internal/domain/pull_request"
 	"InternshipTask/internal/domain/team"
+	"InternshipTask/internal/domain/txn"
 	"InternshipTask/internal/domain/user"
+	"InternshipTask/internal/infrastructure/memory"
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-type stubTeamStorage struct {
-	createdTeam *team.Team
-	teamByName  map[string]team.Team
+// faultyPRRepo lets a test make Update fail on top of a real backend.
+type faultyPRRepo struct {
+	pull_request.Repository
+	updateErr error
 }
 
-func (s *stubTeamStorage) Create(_ context.Context, t team.Team) error {
-	s.createdTeam = &t
-	if s.teamByName == nil {
-		s.teamByName = make(map[string]team.Team)
+func (r *faultyPRRepo) Update(ctx context.Context, pr *pull_request.PR) error {
+	if r.updateErr != nil {
+		return r.updateErr
 	}
-	s.teamByName[t.TeamName] = t
-	return nil
+	return r.Repository.Update(ctx, pr)
 }
 
-func (s *stubTeamStorage) GetByTeamName(_ context.Context, name string) (team.Team, error) {
-	if s.teamByName == nil {
-		return team.Team{}, team.ErrTeamNotFound
-	}
-	t, ok := s.teamByName[name]
-	if !ok {
-		return team.Team{}, team.ErrTeamNotFound
-	}
-	return t, nil
+const testWebhookSecret = "It's a Secret to Everybody"
+
+type testStorages struct {
+	teams team.Storager
+	users user.Storager
+	prs   *faultyPRRepo
 }
 
-type stubUserStorage struct {
-	users map[string]*user.User
+// buildRouter wires the handlers to the in-memory backend seeded with the
+// backend team: author, u2 and u3, all active.
+func buildRouter(t *testing.T) (*gin.Engine, testStorages) {
+	t.Helper()
+
+	r, st, _ := newRouter(t, false)
+	return r, st
 }
 
-func (s *stubUserStorage) GetByID(_ context.Context, id string) (*user.User, error) {
-	if u, ok := s.users[id]; ok {
-		return u, nil
-	}
-	return nil, user.ErrUserNotFound
+// buildAuthRouter is buildRouter with authentication on.
+func buildAuthRouter(t *testing.T) (*gin.Engine, testStorages, *auth.Service) {
+	t.Helper()
+
+	return newRouter(t, true)
 }
 
-func (s *stubUserStorage) SetIsActive(_ context.Context, id string, isActive bool) (*user.User, error) {
-	if s.users == nil {
-		s.users = make(map[string]*user.User)
+func newRouter(t *testing.T, withAuth bool) (*gin.Engine, testStorages, *auth.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db := memory.NewDB()
+	st := testStorages{
+		teams: memory.NewTeamStorage(db),
+		users: memory.NewUserStorage(db),
+		prs:   &faultyPRRepo{Repository: memory.NewPullRequestStorage(db)},
 	}
-	u, ok := s.users[id]
-	if !ok {
-		u = &user.User{UserId: id}
-		s.users[id] = u
+	tx := txn.NewMemory()
+
+	backend := team.NewTeam("backend")
+	backend.Members[0] = user.NewUser("author", "Author", "backend", true)
+	backend.Members[1] = user.NewUser("u2", "Alice", "backend", true)
+	backend.Members[2] = user.NewUser("u3", "Bob", "backend", true)
+	if err := st.teams.Create(context.Background(), *backend); err != nil {
+		t.Fatalf("seed team: %v", err)
 	}
-	u.IsActive = isActive
-	return u, nil
-}
 
-type stubPRRepo struct {
-	prByID        map[string]*pull_request.PR
-	prsByReviewer []pull_request.PullRequestShort
-	stats         map[string]int64
-}
+	auditSvc := audit.NewService(memory.NewAuditStorage(db))
+	teamSvc := team.NewService(st.teams, team.WithTxManager(tx), team.WithAuditLog(auditSvc))
+	userSvc := user.NewService(st.users, user.WithTxManager(tx), user.WithAuditLog(auditSvc))
+	prSvc := pull_request.NewService(st.prs, userSvc, teamSvc, pull_request.WithTxManager(tx), pull_request.WithAuditLog(auditSvc))
+	offboardingSvc := offboarding.NewService(userSvc, prSvc, offboarding.WithTxManager(tx))
 
-func (r *stubPRRepo) Create(_ context.Context, pr *pull_request.PR) error {
-	if r.prByID == nil {
-		r.prByID = make(map[string]*pull_request.PR)
+	validator, err := logger.NewOpenAPIValidator(api.OpenAPI, api.OpenAPIV2)
+	if err != nil {
+		t.Fatalf("load openapi spec: %v", err)
 	}
-	r.prByID[pr.PullRequestId] = pr
-	return nil
-}
 
-func (r *stubPRRepo) GetByID(_ context.Context, id string) (*pull_request.PR, error) {
-	if pr, ok := r.prByID[id]; ok {
-		return pr, nil
+	r := gin.New()
+	var authSvc *auth.Service
+	if withAuth {
+		authSvc = auth.NewService(memory.NewTokenStorage(db), teamSvc, auth.WithTxManager(tx), auth.WithAuditLog(auditSvc))
+		r.Use(logger.AuthMiddleware(authSvc))
+	} else {
+		r.Use(logger.ActorMiddleware())
 	}
-	return nil, pull_request.ErrNotFound
+	r.Use(logger.OpenAPIMiddleware(validator, logger.OpenAPITest))
+	RegisterRoutes(r, teamSvc, userSvc, prSvc, offboardingSvc, auditSvc, authSvc)
+	RegisterGitHubWebhook(r, prSvc, GitHubWebhookConfig{
+		Secret: testWebhookSecret,
+		Logins: map[string]string{"octocat": "author"},
+	})
+	return r, st, authSvc
 }
 
-func (r *stubPRRepo) Update(_ context.Context, pr *pull_request.PR) error {
-	if r.prByID == nil {
-		r.prByID = make(map[string]*pull_request.PR)
+func seedPR(t *testing.T, st testStorages, id, authorID string, reviewers ...string) {
+	t.Helper()
+
+	pr := pull_request.NewPR(id, "Test", authorID, pull_request.OPEN)
+	pr.TeamName = "backend"
+	pr.AssignedReviewers = reviewers
+	pr.ReviewerChange = pull_request.ReviewerChange{Reason: pull_request.ReasonCreated, At: *pr.CreatedAt}
+	if err := st.prs.Create(context.Background(), pr); err != nil {
+		t.Fatalf("seed pr %s: %v", id, err)
 	}
-	r.prByID[pr.PullRequestId] = pr
-	return nil
 }
 
-func (r *stubPRRepo) GetByReviewerID(_ context.Context, _ string) ([]pull_request.PullRequestShort, error) {
-	return r.prsByReviewer, nil
-}
+func mustGetPR(t *testing.T, st testStorages, id string) *pull_request.PR {
+	t.Helper()
 
-func (r *stubPRRepo) GetReviewerStats(_ context.Context) (map[string]int64, error) {
-	return r.stats, nil
+	pr, err := st.prs.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("get pr %s: %v", id, err)
+	}
+	return pr
 }
 
-func buildRouter() (*gin.Engine, *stubTeamStorage, *stubUserStorage, *stubPRRepo) {
-	gin.SetMode(gin.TestMode)
+func mustGetUser(t *testing.T, st testStorages, id string) *user.User {
+	t.Helper()
 
-	teamStorage := &stubTeamStorage{}
-	userStorage := &stubUserStorage{
-		users: map[string]*user.User{
-			"author": {UserId: "author", UserName: "Author", TeamName: "backend", IsActive: true},
-			"u2":     {UserId: "u2", UserName: "Alice", TeamName: "backend", IsActive: true},
-		},
+	u, err := st.users.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("get user %s: %v", id, err)
 	}
-	prRepo := &stubPRRepo{
-		stats: map[string]int64{"u2": 2},
-		prsByReviewer: []pull_request.PullRequestShort{
-			*pull_request.NewPullRequestShort("pr-1", "PR 1", "author", pull_request.OPEN),
-		},
-	}
-
-	teamSvc := team.NewService(teamStorage)
-	userSvc := user.NewService(userStorage)
-	prSvc := pull_request.NewService(prRepo, userSvc, teamSvc)
-
-	r := gin.Default()
-	RegisterRoutes(r, teamSvc, userSvc, prSvc)
-	return r, teamStorage, userStorage, prRepo
+	return u
 }
 
 func TestHealthHandler_OK(t *testing.T) {
-	r, _, _, _ := buildRouter()
+	r, _ := buildRouter(t)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, "/health", nil)
@@ -140,7 +160,8 @@ func TestHealthHandler_OK(t *testing.T) {
 }
 
 func TestStatsHandler_OK(t *testing.T) {
-	r, _, _, _ := buildRouter()
+	r, st := buildRouter(t)
+	seedPR(t, st, "pr-1", "author", "u2")
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, "/stats", nil)
@@ -152,10 +173,10 @@ func TestStatsHandler_OK(t *testing.T) {
 }
 
 func TestCreateTeamHandler_Success(t *testing.T) {
-	r, teamStorage, _, _ := buildRouter()
+	r, st := buildRouter(t)
 
 	body := dto.TeamDTO{
-		TeamName: "backend",
+		TeamName: "frontend",
 		Members: []dto.TeamMemberDTO{
 			{UserID: "u1", Username: "Alice", IsActive: true},
 		},
@@ -170,18 +191,41 @@ func TestCreateTeamHandler_Success(t *testing.T) {
 	if w.Code != http.StatusCreated {
 		t.Fatalf("expected status 201, got %d, body=%s", w.Code, w.Body.String())
 	}
-	if teamStorage.createdTeam == nil || teamStorage.createdTeam.TeamName != "backend" {
-		t.Fatalf("expected team to be created in storage")
+	created, err := st.teams.GetByTeamName(context.Background(), "frontend")
+	if err != nil || len(created.Members) != 1 || created.Members[0].UserId != "u1" {
+		t.Fatalf("expected team to be created in storage, got %+v, %v", created, err)
 	}
 }
 
-func TestGetTeamHandler_Success(t *testing.T) {
-	r, teamStorage, _, _ := buildRouter()
+func TestCreateTeamHandler_InactiveMember(t *testing.T) {
+	r, st := buildRouter(t)
 
-	teamStorage.teamByName = map[string]team.Team{
-		"backend": *team.NewTeam("backend"),
+	do := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/team/add", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		return w
 	}
 
+	w := do(`{"team_name":"frontend","members":[{"user_id":"u1","username":"Alice","is_active":false}]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d, body=%s", w.Code, w.Body.String())
+	}
+	created, err := st.teams.GetByTeamName(context.Background(), "frontend")
+	if err != nil || len(created.Members) != 1 || created.Members[0].IsActive {
+		t.Fatalf("expected an inactive member, got %+v, %v", created, err)
+	}
+
+	w = do(`{"team_name":"design","members":[{"user_id":"u9","username":"Eve"}]}`)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "is_active") {
+		t.Fatalf("expected status 400 for a missing is_active, got %d, body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestGetTeamHandler_Success(t *testing.T) {
+	r, _ := buildRouter(t)
+
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, "/team/get?team_name=backend", nil)
 	r.ServeHTTP(w, req)
@@ -192,7 +236,7 @@ func TestGetTeamHandler_Success(t *testing.T) {
 }
 
 func TestSetUserIsActiveHandler_Success(t *testing.T) {
-	r, _, userStorage, _ := buildRouter()
+	r, st := buildRouter(t)
 
 	body := dto.SetUserActiveRequest{
 		UserID:   "u2",
@@ -208,13 +252,14 @@ func TestSetUserIsActiveHandler_Success(t *testing.T) {
 	if w.Code != http.StatusOK {
 		t.Fatalf("expected status 200, got %d, body=%s", w.Code, w.Body.String())
 	}
-	if userStorage.users["u2"] == nil || userStorage.users["u2"].IsActive != false {
+	if mustGetUser(t, st, "u2").IsActive {
 		t.Fatalf("expected user u2 to be set inactive")
 	}
 }
 
 func TestGetUserReviewsHandler_Success(t *testing.T) {
-	r, _, _, _ := buildRouter()
+	r, st := buildRouter(t)
+	seedPR(t, st, "pr-1", "author", "u2")
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, "/users/getReview?user_id=u2", nil)
@@ -225,18 +270,234 @@ func TestGetUserReviewsHandler_Success(t *testing.T) {
 	}
 }
 
-func TestCreatePullRequestHandler_Success(t *testing.T) {
-	r, teamStorage, userStorage, prRepo := buildRouter()
-
-	teamStorage.teamByName = map[string]team.Team{
-		"backend": {
-			TeamName: "backend",
-			Members: map[uint]*user.User{
-				0: userStorage.users["author"],
-				1: userStorage.users["u2"],
-			},
-		},
+func TestGetUserReviewsHandler_FiltersAndVerdicts(t *testing.T) {
+	r, st := buildRouter(t)
+	seedPR(t, st, "pr-1", "author", "u2")
+	seedPR(t, st, "pr-2", "author", "u2")
+	seedPR(t, st, "pr-3", "author", "u2")
+
+	pr := mustGetPR(t, st, "pr-2")
+	mergedAt := time.Now().UTC()
+	pr.Status = pull_request.MERGED
+	pr.MergedAt = &mergedAt
+	if err := st.prs.Update(context.Background(), pr); err != nil {
+		t.Fatalf("merge pr-2: %v", err)
+	}
+
+	setVerdict := func(body dto.SetVerdictRequest) *httptest.ResponseRecorder {
+		t.Helper()
+		data, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/pullRequest/verdict", bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		return w
+	}
+	if w := setVerdict(dto.SetVerdictRequest{PullRequestID: "pr-3", UserID: "u2", Verdict: "APPROVED"}); w.Code != http.StatusOK {
+		t.Fatalf("verdict: expected status 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+
+	reviews := func(query string) dto.UserReviewsResponse {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/users/getReview?user_id=u2"+query, nil)
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%q: expected status 200, got %d, body=%s", query, w.Code, w.Body.String())
+		}
+		var resp dto.UserReviewsResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		return resp
+	}
+
+	first := reviews("&limit=1")
+	if len(first.PullRequests) != 1 || first.PullRequests[0].PullRequestID != "pr-1" || first.NextCursor == "" {
+		t.Fatalf("expected the first open review, got %+v", first)
+	}
+	if got := first.PullRequests[0]; got.Verdict != "PENDING" || got.AssignedAt.IsZero() || got.AgeHours < 0 {
+		t.Fatalf("unexpected review: %+v", got)
+	}
+	next := reviews("&limit=1&cursor=" + first.NextCursor)
+	if len(next.PullRequests) != 1 || next.PullRequests[0].PullRequestID != "pr-3" || next.NextCursor != "" {
+		t.Fatalf("expected merged PRs to be skipped by default, got %+v", next)
 	}
+	if got := next.PullRequests[0]; got.Verdict != "APPROVED" || got.VerdictAt == nil {
+		t.Fatalf("expected the submitted verdict, got %+v", got)
+	}
+	if all := reviews("&status=ALL"); len(all.PullRequests) != 3 {
+		t.Fatalf("expected every review with status=ALL, got %+v", all)
+	}
+	if merged := reviews("&status=MERGED"); len(merged.PullRequests) != 1 || merged.PullRequests[0].PullRequestID != "pr-2" {
+		t.Fatalf("expected only merged reviews, got %+v", merged)
+	}
+	if none := reviews("&team_name=frontend"); len(none.PullRequests) != 0 {
+		t.Fatalf("expected the team filter to apply, got %+v", none)
+	}
+
+	for _, tc := range []struct {
+		body dto.SetVerdictRequest
+		code int
+	}{
+		{dto.SetVerdictRequest{PullRequestID: "pr-3", UserID: "u2", Verdict: "LGTM"}, http.StatusBadRequest},
+		{dto.SetVerdictRequest{PullRequestID: "pr-3", UserID: "u3", Verdict: "APPROVED"}, http.StatusConflict},
+		{dto.SetVerdictRequest{PullRequestID: "pr-2", UserID: "u2", Verdict: "APPROVED"}, http.StatusConflict},
+	} {
+		if w := setVerdict(tc.body); w.Code != tc.code {
+			t.Fatalf("%+v: expected status %d, got %d, body=%s", tc.body, tc.code, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestGetUserReviewsHandler_IncludeArchived(t *testing.T) {
+	r, st := buildRouter(t)
+	seedPR(t, st, "pr-1", "author", "u2")
+	seedPR(t, st, "pr-2", "author", "u2")
+
+	pr := mustGetPR(t, st, "pr-1")
+	mergedAt := time.Now().UTC().Add(-48 * time.Hour)
+	pr.Status = pull_request.MERGED
+	pr.MergedAt = &mergedAt
+	if err := st.prs.Update(context.Background(), pr); err != nil {
+		t.Fatalf("merge pr-1: %v", err)
+	}
+	if _, err := st.prs.ArchiveMerged(context.Background(), time.Now().UTC().Add(-24*time.Hour), 10); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+
+	for query, want := range map[string][]string{
+		"":                                  {"pr-2"},
+		"&include_archived=true&status=ALL": {"pr-1", "pr-2"},
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/users/getReview?user_id=u2"+query, nil)
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%q: expected status 200, got %d, body=%s", query, w.Code, w.Body.String())
+		}
+		var resp dto.UserReviewsResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		ids := make([]string, 0, len(resp.PullRequests))
+		for _, p := range resp.PullRequests {
+			ids = append(ids, p.PullRequestID)
+		}
+		slices.Sort(ids)
+		if !slices.Equal(ids, want) {
+			t.Fatalf("%q: expected %v, got %v", query, want, ids)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/stats?include_archived=maybe", nil)
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for a malformed include_archived, got %d", w.Code)
+	}
+}
+
+func TestGetPullRequestHandler_Expand(t *testing.T) {
+	r, st := buildRouter(t)
+	seedPR(t, st, "pr-1", "author", "u2")
+	seedPR(t, st, "pr-2", "u3", "u2")
+
+	get := func(query string) (int, map[string]json.RawMessage) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/pullRequest/get?"+query, nil)
+		r.ServeHTTP(w, req)
+
+		var resp map[string]json.RawMessage
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+		}
+		return w.Code, resp
+	}
+
+	code, resp := get("pull_request_id=pr-1")
+	if code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+	var bare dto.PullRequestDetailsDTO
+	if err := json.Unmarshal(resp["pr"], &bare); err != nil {
+		t.Fatalf("decode pr: %v", err)
+	}
+	if bare.PullRequestID != "pr-1" || bare.Author != nil || bare.Reviewers != nil {
+		t.Fatalf("expected ids only without expand, got %+v", bare)
+	}
+
+	code, resp = get("pull_request_id=pr-1&expand=reviewers,author")
+	if code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+	var expanded dto.PullRequestDetailsDTO
+	if err := json.Unmarshal(resp["pr"], &expanded); err != nil {
+		t.Fatalf("decode pr: %v", err)
+	}
+	if expanded.Author == nil || expanded.Author.Username != "Author" || expanded.Author.OpenReviews != 0 {
+		t.Fatalf("unexpected author: %+v", expanded.Author)
+	}
+	if len(expanded.Reviewers) != 1 || expanded.Reviewers[0].Username != "Alice" ||
+		expanded.Reviewers[0].TeamName != "backend" || !expanded.Reviewers[0].IsActive || expanded.Reviewers[0].OpenReviews != 2 {
+		t.Fatalf("unexpected reviewers: %+v", expanded.Reviewers)
+	}
+
+	if code, _ := get("pull_request_id=missing"); code != http.StatusNotFound {
+		t.Fatalf("expected status 404 for an unknown PR, got %d", code)
+	}
+	if code, _ := get("pull_request_id=pr-1&expand=team"); code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for an unknown expand, got %d", code)
+	}
+}
+
+func TestListPullRequestsHandler(t *testing.T) {
+	r, st := buildRouter(t)
+	seedPR(t, st, "pr-1", "author", "u2")
+	seedPR(t, st, "pr-2", "author", "u3")
+	seedPR(t, st, "pr-3", "author", "u2")
+
+	list := func(query string) dto.PullRequestListResponse {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/pullRequest/list?"+query, nil)
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%q: expected status 200, got %d, body=%s", query, w.Code, w.Body.String())
+		}
+		var resp dto.PullRequestListResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		return resp
+	}
+
+	first := list("reviewer_id=u2&sort=created_at&limit=1")
+	if len(first.PullRequests) != 1 || first.PullRequests[0].PullRequestID != "pr-1" || first.NextCursor == "" {
+		t.Fatalf("unexpected first page: %+v", first)
+	}
+	last := list("reviewer_id=u2&sort=created_at&limit=1&cursor=" + first.NextCursor)
+	if len(last.PullRequests) != 1 || last.PullRequests[0].PullRequestID != "pr-3" || last.NextCursor != "" {
+		t.Fatalf("unexpected last page: %+v", last)
+	}
+
+	for _, query := range []string{"status=CLOSED", "cursor=garbage", "limit=0", "created_from=yesterday", "sort=name"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/pullRequest/list?"+query, nil)
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%q: expected status 400, got %d, body=%s", query, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestCreatePullRequestHandler_Success(t *testing.T) {
+	r, st := buildRouter(t)
 
 	body := dto.CreatePullRequestRequest{
 		PullRequestID:   "pr-1",
@@ -253,17 +514,14 @@ func TestCreatePullRequestHandler_Success(t *testing.T) {
 	if w.Code != http.StatusCreated {
 		t.Fatalf("expected status 201, got %d, body=%s", w.Code, w.Body.String())
 	}
-	if prRepo.prByID["pr-1"] == nil {
-		t.Fatalf("expected pr-1 to be created in repo")
+	if pr := mustGetPR(t, st, "pr-1"); len(pr.AssignedReviewers) != 2 || slices.Contains(pr.AssignedReviewers, "author") {
+		t.Fatalf("expected two reviewers other than the author, got %v", pr.AssignedReviewers)
 	}
 }
 
 func TestMergePullRequestHandler_Success(t *testing.T) {
-	r, _, _, prRepo := buildRouter()
-
-	prRepo.prByID = map[string]*pull_request.PR{
-		"pr-1": pull_request.NewPR("pr-1", "Test", "author", pull_request.OPEN),
-	}
+	r, st := buildRouter(t)
+	seedPR(t, st, "pr-1", "author")
 
 	body := dto.MergePullRequestRequest{PullRequestID: "pr-1"}
 	data, _ := json.Marshal(body)
@@ -276,24 +534,40 @@ func TestMergePullRequestHandler_Success(t *testing.T) {
 	if w.Code != http.StatusOK {
 		t.Fatalf("expected status 200, got %d, body=%s", w.Code, w.Body.String())
 	}
+	if pr := mustGetPR(t, st, "pr-1"); pr.Status != pull_request.MERGED {
+		t.Fatalf("expected pr-1 to be merged, got %s", pr.Status)
+	}
 }
 
-func TestReassignPullRequestHandler_Success(t *testing.T) {
-	r, teamStorage, userStorage, prRepo := buildRouter()
-
-	teamStorage.teamByName = map[string]team.Team{
-		"backend": {
-			TeamName: "backend",
-			Members: map[uint]*user.User{
-				0: userStorage.users["author"],
-				1: userStorage.users["u2"],
-			},
-		},
+func TestMergePullRequestHandler_Conflict(t *testing.T) {
+	r, st := buildRouter(t)
+	seedPR(t, st, "pr-1", "author")
+	st.prs.updateErr = pull_request.ErrConflict
+
+	body := dto.MergePullRequestRequest{PullRequestID: "pr-1"}
+	data, _ := json.Marshal(body)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/pullRequest/merge", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d, body=%s", w.Code, w.Body.String())
+	}
+
+	var resp dto.ErrorDTO
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal error response: %v", err)
 	}
+	if resp.Error.Code != "CONFLICT" {
+		t.Fatalf("expected CONFLICT code, got %s", resp.Error.Code)
+	}
+}
 
-	pr := pull_request.NewPR("pr-1", "Test", "author", pull_request.OPEN)
-	pr.AssignedReviewers = []string{"u2"}
-	prRepo.prByID = map[string]*pull_request.PR{"pr-1": pr}
+func TestReassignPullRequestHandler_Success(t *testing.T) {
+	r, st := buildRouter(t)
+	seedPR(t, st, "pr-1", "author", "u2")
 
 	body := dto.ReassignPullRequestRequest{
 		PullRequestID: "pr-1",
@@ -309,4 +583,705 @@ func TestReassignPullRequestHandler_Success(t *testing.T) {
 	if w.Code != http.StatusOK {
 		t.Fatalf("expected status 200, got %d, body=%s", w.Code, w.Body.String())
 	}
+	if got := mustGetPR(t, st, "pr-1").AssignedReviewers; !slices.Equal(got, []string{"u3"}) {
+		t.Fatalf("expected u2 to be replaced by u3, got %v", got)
+	}
+}
+
+func TestInvalidRequestsGetDocumented400(t *testing.T) {
+	r, _ := buildRouter(t)
+
+	cases := map[string]struct {
+		method, path, contentType, body string
+	}{
+		"missing body field":  {http.MethodPost, "/pullRequest/create", "application/json", `{"pull_request_name":"x","author_id":"author"}`},
+		"wrong content type":  {http.MethodPost, "/pullRequest/merge", "text/plain", `{"pull_request_id":"pr-1"}`},
+		"missing query param": {http.MethodGet, "/team/get", "", ""},
+		"wrong field type":    {http.MethodPost, "/users/setIsActive", "application/json", `{"user_id":"u2","is_active":"yes"}`},
+		"missing membership":  {http.MethodPost, "/team/addMember", "application/json", `{"team_name":"backend"}`},
+		"missing assignments": {http.MethodGet, "/pullRequest/assignments", "", ""},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			r.ServeHTTP(w, req)
+
+			var resp dto.ErrorDTO
+			_ = json.Unmarshal(w.Body.Bytes(), &resp)
+			if w.Code != http.StatusBadRequest || resp.Error.Code != "INVALID_REQUEST" {
+				t.Fatalf("expected 400 INVALID_REQUEST, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestDomainErrorsMapToOpenAPICodes(t *testing.T) {
+	r, st := buildRouter(t)
+	seedPR(t, st, "pr-open", "author", "u2", "u3")
+	seedPR(t, st, "pr-merged", "author", "u2")
+	merged := mustGetPR(t, st, "pr-merged")
+	merged.Status = pull_request.MERGED
+	if err := st.prs.Update(context.Background(), merged); err != nil {
+		t.Fatalf("merge pr-merged: %v", err)
+	}
+
+	tests := map[string]struct {
+		method, path, body string
+		status             int
+		code               string
+	}{
+		"pr exists":     {http.MethodPost, "/pullRequest/create", `{"pull_request_id":"pr-open","pull_request_name":"x","author_id":"author"}`, http.StatusConflict, "PR_EXISTS"},
+		"pr merged":     {http.MethodPost, "/pullRequest/reassign", `{"pull_request_id":"pr-merged","old_user_id":"u2"}`, http.StatusConflict, "PR_MERGED"},
+		"not assigned":  {http.MethodPost, "/pullRequest/reassign", `{"pull_request_id":"pr-open","old_user_id":"author"}`, http.StatusConflict, "NOT_ASSIGNED"},
+		"no candidate":  {http.MethodPost, "/pullRequest/reassign", `{"pull_request_id":"pr-open","old_user_id":"u2"}`, http.StatusConflict, "NO_CANDIDATE"},
+		"pr not found":  {http.MethodPost, "/pullRequest/merge", `{"pull_request_id":"missing"}`, http.StatusNotFound, "NOT_FOUND"},
+		"no author":     {http.MethodPost, "/pullRequest/create", `{"pull_request_id":"pr-new","pull_request_name":"x","author_id":"ghost"}`, http.StatusNotFound, "NOT_FOUND"},
+		"team exists":   {http.MethodPost, "/team/add", `{"team_name":"backend","members":[]}`, http.StatusBadRequest, "TEAM_EXISTS"},
+		"no team":       {http.MethodGet, "/team/get?team_name=missing", "", http.StatusNotFound, "NOT_FOUND"},
+		"no such user":  {http.MethodPost, "/users/setIsActive", `{"user_id":"ghost","is_active":true}`, http.StatusNotFound, "NOT_FOUND"},
+		"invalid skill": {http.MethodPost, "/users/setSkills", `{"user_id":"u2","skills":["not a skill!"]}`, http.StatusBadRequest, "INVALID_REQUEST"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tc.method, tc.path, bytes.NewReader([]byte(tc.body)))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+
+			var resp dto.ErrorDTO
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("unmarshal error response: %v", err)
+			}
+			if w.Code != tc.status || resp.Error.Code != tc.code {
+				t.Fatalf("expected %d %s, got %d %s (%s)", tc.status, tc.code, w.Code, resp.Error.Code, resp.Error.Message)
+			}
+		})
+	}
+}
+
+func TestTeamMembershipHandlers(t *testing.T) {
+	r, st := buildRouter(t)
+	if err := st.teams.Create(context.Background(), *team.NewTeam("platform")); err != nil {
+		t.Fatalf("seed team: %v", err)
+	}
+
+	body := dto.TeamMembershipRequest{TeamName: "platform", UserID: "u2"}
+	data, _ := json.Marshal(body)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/team/addMember", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/users/getTeams?user_id=u2", nil)
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+	var resp dto.UserTeamsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !slices.Equal(resp.Teams, []string{"backend", "platform"}) {
+		t.Fatalf("expected teams [backend platform], got %v", resp.Teams)
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/team/removeMember", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/team/removeMember", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d, body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestSetUserSkillsHandler_Success(t *testing.T) {
+	r, st := buildRouter(t)
+
+	body := dto.SetUserSkillsRequest{UserID: "u2", Skills: []string{"Go", "postgres", "go"}}
+	data, _ := json.Marshal(body)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/users/setSkills", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+	if got := mustGetUser(t, st, "u2").Skills; !slices.Equal(got, []string{"go", "postgres"}) {
+		t.Fatalf("expected normalized skills [go postgres], got %v", got)
+	}
+
+	body.Skills = []string{"bad skill"}
+	data, _ = json.Marshal(body)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/users/setSkills", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d, body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestSetUserScheduleHandler(t *testing.T) {
+	r, st := buildRouter(t)
+
+	body := dto.SetUserScheduleRequest{UserID: "u2", TimeZone: "Europe/Moscow", WorkStart: "10:00", WorkEnd: "19:30"}
+	data, _ := json.Marshal(body)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/users/setSchedule", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+	got := mustGetUser(t, st, "u2").Schedule
+	if got.TimeZone != "Europe/Moscow" || got.WorkStart != 600 || got.WorkEnd != 1170 {
+		t.Fatalf("unexpected schedule: %+v", got)
+	}
+
+	body.TimeZone = "Mars/Olympus"
+	data, _ = json.Marshal(body)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/users/setSchedule", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d, body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestOffboardUserHandler(t *testing.T) {
+	r, st := buildRouter(t)
+	seedPR(t, st, "pr-1", "author", "u2")
+	seedPR(t, st, "pr-2", "u2", "u3")
+
+	body := dto.OffboardUserRequest{UserID: "u2", TransferTo: "u3"}
+	data, _ := json.Marshal(body)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/users/offboard", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+
+	if got := mustGetPR(t, st, "pr-1").AssignedReviewers; len(got) != 1 || got[0] != "u3" {
+		t.Fatalf("expected u2 to be replaced by u3 on pr-1, got %v", got)
+	}
+	if pr := mustGetPR(t, st, "pr-2"); pr.AuthorId != "u3" || len(pr.AssignedReviewers) != 1 || pr.AssignedReviewers[0] != "author" {
+		t.Fatalf("expected pr-2 to be transferred to u3 and its review slot handed over, got author=%s reviewers=%v", pr.AuthorId, pr.AssignedReviewers)
+	}
+
+	activate, _ := json.Marshal(dto.SetUserActiveRequest{UserID: "u2", IsActive: true})
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/users/setIsActive", bytes.NewReader(activate))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409 for departed user, got %d, body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestGetPullRequestAssignmentsHandler(t *testing.T) {
+	r, st := buildRouter(t)
+	seedPR(t, st, "pr-1", "author", "u2")
+
+	data, _ := json.Marshal(dto.ReassignPullRequestRequest{PullRequestID: "pr-1", OldUserID: "u2"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/pullRequest/reassign", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(logger.ActorHeader, "lead")
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/pullRequest/assignments?pull_request_id=pr-1", nil)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+
+	var resp dto.ReviewAssignmentsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if len(resp.Assignments) != 2 {
+		t.Fatalf("expected 2 assignments, got %+v", resp.Assignments)
+	}
+	first, second := resp.Assignments[0], resp.Assignments[1]
+	if first.ReviewerID != "u2" || first.UnassignReason != "REASSIGNED" || first.UnassignedBy != "lead" || first.UnassignedAt == nil {
+		t.Fatalf("unexpected closed assignment: %+v", first)
+	}
+	if second.ReviewerID != "u3" || second.AssignedReason != "REASSIGNED" || second.AssignedBy != "lead" || second.UnassignedAt != nil {
+		t.Fatalf("unexpected open assignment: %+v", second)
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/pullRequest/assignments?pull_request_id=missing", nil)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d, body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestOffboardUserHandler_RollsBackOnFailure(t *testing.T) {
+	r, st := buildRouter(t)
+	seedPR(t, st, "pr-1", "author", "u2")
+	st.prs.updateErr = context.DeadlineExceeded
+
+	body := dto.OffboardUserRequest{UserID: "u2"}
+	data, _ := json.Marshal(body)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/users/offboard", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d, body=%s", w.Code, w.Body.String())
+	}
+
+	if u := mustGetUser(t, st, "u2"); u.IsDeparted() || !u.IsActive {
+		t.Fatalf("expected offboarding to be rolled back, got %+v", u)
+	}
+}
+
+func TestGetAuditLogHandler(t *testing.T) {
+	r, _ := buildRouter(t)
+
+	data, _ := json.Marshal(dto.SetUserActiveRequest{UserID: "u2", IsActive: false})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/users/setIsActive", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(logger.ActorHeader, "lead")
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/audit?entity_type=user&actor=lead", nil)
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+
+	var resp dto.AuditLogResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if len(resp.Entries) != 1 {
+		t.Fatalf("expected 1 entry, got %+v", resp.Entries)
+	}
+	e := resp.Entries[0]
+	if e.Action != "user.set_is_active" || e.EntityID != "u2" || e.Actor != "lead" {
+		t.Fatalf("unexpected entry: %+v", e)
+	}
+	var before, after user.User
+	if err := json.Unmarshal(e.Before, &before); err != nil || !before.IsActive {
+		t.Fatalf("expected before state to be active, got %s, %v", e.Before, err)
+	}
+	if err := json.Unmarshal(e.After, &after); err != nil || after.IsActive {
+		t.Fatalf("expected after state to be inactive, got %s, %v", e.After, err)
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/audit?from=yesterday", nil)
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d, body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestAuth_RoutesNeedTokenAndPermission(t *testing.T) {
+	r, st, authSvc := buildAuthRouter(t)
+	seedPR(t, st, "pr-1", "author", "u2")
+
+	other := team.NewTeam("frontend")
+	other.Members[0] = user.NewUser("f1", "Frank", "frontend", true)
+	if err := st.teams.Create(context.Background(), *other); err != nil {
+		t.Fatalf("seed team: %v", err)
+	}
+
+	issue := func(userID string, role auth.Role) string {
+		_, secret, err := authSvc.Issue(context.Background(), auth.IssueParams{UserID: userID, Role: role})
+		if err != nil {
+			t.Fatalf("Issue() error = %v", err)
+		}
+		return secret
+	}
+	admin := issue("root", auth.RoleAdmin)
+	lead := issue("author", auth.RoleTeamLead)
+	member := issue("u2", auth.RoleMember)
+
+	do := func(method, path, secret string, body any) *httptest.ResponseRecorder {
+		var data []byte
+		if body != nil {
+			data, _ = json.Marshal(body)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		// Ignored with authentication on.
+		req.Header.Set(logger.ActorHeader, "spoofed")
+		if secret != "" {
+			req.Header.Set("Authorization", "Bearer "+secret)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		secret string
+		body   any
+		want   int
+	}{
+		{"health is public", http.MethodGet, "/health", "", nil, http.StatusOK},
+		{"no token", http.MethodGet, "/team/get?team_name=backend", "", nil, http.StatusUnauthorized},
+		{"bad token", http.MethodGet, "/team/get?team_name=backend", "prs_nope", nil, http.StatusUnauthorized},
+		{"member reads", http.MethodGet, "/team/get?team_name=backend", member, nil, http.StatusOK},
+		{"member cannot deactivate users", http.MethodPost, "/users/setIsActive", member,
+			dto.SetUserActiveRequest{UserID: "u3"}, http.StatusForbidden},
+		{"member cannot merge", http.MethodPost, "/pullRequest/merge", member,
+			dto.MergePullRequestRequest{PullRequestID: "pr-1"}, http.StatusForbidden},
+		{"member cannot submit others' verdicts", http.MethodPost, "/pullRequest/verdict", member,
+			dto.SetVerdictRequest{PullRequestID: "pr-1", UserID: "u3", Verdict: "APPROVED"}, http.StatusForbidden},
+		{"member submits own verdict", http.MethodPost, "/pullRequest/verdict", member,
+			dto.SetVerdictRequest{PullRequestID: "pr-1", UserID: "u2", Verdict: "APPROVED"}, http.StatusOK},
+		{"lead cannot create teams", http.MethodPost, "/team/add", lead,
+			dto.TeamDTO{TeamName: "payments", Members: []dto.TeamMemberDTO{{UserID: "u3", Username: "Bob", IsActive: false}}}, http.StatusForbidden},
+		{"lead cannot create teams in v2", http.MethodPost, "/v2/teams", lead,
+			dto.TeamDTO{TeamName: "payments", Members: []dto.TeamMemberDTO{}}, http.StatusForbidden},
+		{"lead cannot pull in members of other teams", http.MethodPost, "/team/addMember", lead,
+			dto.TeamMembershipRequest{TeamName: "backend", UserID: "f1"}, http.StatusForbidden},
+		{"lead cannot manage other teams", http.MethodPost, "/users/setIsActive", lead,
+			dto.SetUserActiveRequest{UserID: "f1"}, http.StatusForbidden},
+		{"lead cannot offboard other teams", http.MethodPost, "/users/offboard", lead,
+			dto.OffboardUserRequest{UserID: "f1"}, http.StatusForbidden},
+		{"lead manages own team", http.MethodPost, "/users/setIsActive", lead,
+			dto.SetUserActiveRequest{UserID: "u3", IsActive: true}, http.StatusOK},
+		{"v2 needs a token", http.MethodGet, "/v2/teams", "", nil, http.StatusUnauthorized},
+		{"member cannot patch users in v2", http.MethodPatch, "/v2/users/u3", member,
+			dto.UpdateUserRequest{Skills: []string{"go"}}, http.StatusForbidden},
+		{"lead cannot patch other teams in v2", http.MethodPatch, "/v2/users/f1", lead,
+			dto.UpdateUserRequest{Skills: []string{"go"}}, http.StatusForbidden},
+		{"member cannot merge in v2", http.MethodPost, "/v2/pull-requests/pr-1/merge", member, nil, http.StatusForbidden},
+		{"lead cannot issue tokens", http.MethodGet, "/admin/tokens/list", lead, nil, http.StatusForbidden},
+		{"lead merges in own team", http.MethodPost, "/pullRequest/merge", lead,
+			dto.MergePullRequestRequest{PullRequestID: "pr-1"}, http.StatusOK},
+		{"admin lists tokens", http.MethodGet, "/admin/tokens/list", admin, nil, http.StatusOK},
+		{"admin adds members of any team", http.MethodPost, "/team/addMember", admin,
+			dto.TeamMembershipRequest{TeamName: "backend", UserID: "f1"}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := do(tt.method, tt.path, tt.secret, tt.body); w.Code != tt.want {
+				t.Fatalf("expected status %d, got %d, body=%s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/audit?entity_type=user&entity_id=u3", nil)
+	req.Header.Set("Authorization", "Bearer "+admin)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var resp dto.AuditLogResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if len(resp.Entries) != 1 || resp.Entries[0].Actor != "author" {
+		t.Fatalf("expected the change to be attributed to the token owner, got %+v", resp.Entries)
+	}
+}
+
+func TestAuth_CreatePullRequestChecksTargetTeam(t *testing.T) {
+	r, st, authSvc := buildAuthRouter(t)
+
+	other := team.NewTeam("frontend")
+	other.Members[0] = user.NewUser("f1", "Frank", "frontend", true)
+	if err := st.teams.Create(context.Background(), *other); err != nil {
+		t.Fatalf("seed team: %v", err)
+	}
+
+	issue := func(userID string, role auth.Role) string {
+		_, secret, err := authSvc.Issue(context.Background(), auth.IssueParams{UserID: userID, Role: role})
+		if err != nil {
+			t.Fatalf("Issue() error = %v", err)
+		}
+		return secret
+	}
+	admin := issue("root", auth.RoleAdmin)
+	lead := issue("author", auth.RoleTeamLead)
+
+	do := func(path, secret string, body any) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+secret)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// u3 shares backend with the lead, but frontend is not the lead's team.
+	if w := do("/team/addMember", admin, dto.TeamMembershipRequest{TeamName: "frontend", UserID: "u3"}); w.Code != http.StatusOK {
+		t.Fatalf("add member: expected 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+
+	for _, path := range []string{"/pullRequest/create", "/v2/pull-requests"} {
+		w := do(path, lead, dto.CreatePullRequestRequest{
+			PullRequestID: "pr-foreign", PullRequestName: "Foreign", AuthorID: "u3", TeamName: "frontend",
+		})
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("%s: expected a lead to be kept out of a foreign team, got %d, body=%s", path, w.Code, w.Body.String())
+		}
+	}
+	if _, err := st.prs.GetByID(context.Background(), "pr-foreign"); err == nil {
+		t.Fatalf("expected no PR to be created")
+	}
+
+	w := do("/pullRequest/create", lead, dto.CreatePullRequestRequest{
+		PullRequestID: "pr-own", PullRequestName: "Own", AuthorID: "u3", TeamName: "backend",
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected a lead to create PRs in own team, got %d, body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestV2Routes(t *testing.T) {
+	r, st := buildRouter(t)
+
+	do := func(method, path, body string, want int, out any) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		r.ServeHTTP(w, req)
+
+		if w.Code != want {
+			t.Fatalf("%s %s: expected status %d, got %d, body=%s", method, path, want, w.Code, w.Body.String())
+		}
+		if out != nil {
+			if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+				t.Fatalf("%s %s: decode response: %v", method, path, err)
+			}
+		}
+	}
+
+	var created dto.TeamDTO
+	do(http.MethodPost, "/v2/teams", `{"team_name":"payments","members":[{"user_id":"p1","username":"Pat","is_active":true}]}`, http.StatusCreated, &created)
+	if created.TeamName != "payments" || len(created.Members) != 1 {
+		t.Fatalf("unexpected created team: %+v", created)
+	}
+
+	var teams dto.TeamListResponse
+	do(http.MethodGet, "/v2/teams", "", http.StatusOK, &teams)
+	if len(teams.Teams) != 2 || teams.Teams[0].TeamName != "backend" || teams.Teams[1].TeamName != "payments" {
+		t.Fatalf("unexpected teams: %+v", teams)
+	}
+
+	var u dto.UserDTO
+	do(http.MethodPatch, "/v2/users/u3", `{"skills":["go"],"time_zone":"UTC","work_start":"09:00","work_end":"18:00"}`, http.StatusOK, &u)
+	if !slices.Equal(u.Skills, []string{"go"}) || u.TimeZone != "UTC" || !u.IsActive {
+		t.Fatalf("unexpected updated user: %+v", u)
+	}
+	do(http.MethodGet, "/v2/users/u3", "", http.StatusOK, &u)
+	if !slices.Equal(mustGetUser(t, st, "u3").Skills, []string{"go"}) {
+		t.Fatalf("expected the update to be stored, got %+v", u)
+	}
+
+	var pr dto.PullRequestV2DTO
+	do(http.MethodPost, "/v2/pull-requests", `{"pull_request_id":"pr-1","pull_request_name":"Test","author_id":"author","required_skills":["go"]}`, http.StatusCreated, &pr)
+	if pr.Status != "OPEN" || len(pr.AssignedReviewers) != 2 || pr.CreatedAt == nil {
+		t.Fatalf("unexpected created pr: %+v", pr)
+	}
+
+	// Both other backend members review pr-1, so there is no one to swap in.
+	do(http.MethodPost, "/v2/pull-requests/pr-1/reviewers", `{"old_reviewer_id":"u2"}`, http.StatusConflict, nil)
+
+	seedPR(t, st, "pr-0", "author", "u2")
+	var replaced dto.ReplaceReviewerResponse
+	do(http.MethodPost, "/v2/pull-requests/pr-0/reviewers", `{"old_reviewer_id":"u2"}`, http.StatusOK, &replaced)
+	if replaced.ReplacedBy != "u3" || !slices.Equal(replaced.PullRequest.AssignedReviewers, []string{"u3"}) {
+		t.Fatalf("expected u2 to be replaced by u3, got %+v", replaced)
+	}
+
+	do(http.MethodPost, "/v2/pull-requests/pr-1/merge", "", http.StatusOK, &pr)
+	if pr.Status != "MERGED" || pr.MergedAt == nil {
+		t.Fatalf("unexpected merged pr: %+v", pr)
+	}
+
+	var list dto.PullRequestListV2Response
+	do(http.MethodGet, "/v2/pull-requests?status=MERGED", "", http.StatusOK, &list)
+	if len(list.PullRequests) != 1 || list.PullRequests[0].PullRequestID != "pr-1" {
+		t.Fatalf("unexpected list: %+v", list)
+	}
+
+	// The legacy routes see the same state.
+	var legacy dto.PullRequestListResponse
+	do(http.MethodGet, "/pullRequest/list?status=MERGED", "", http.StatusOK, &legacy)
+	if len(legacy.PullRequests) != 1 || legacy.PullRequests[0].PullRequestID != "pr-1" {
+		t.Fatalf("unexpected legacy list: %+v", legacy)
+	}
+
+	do(http.MethodGet, "/v2/users/nobody", "", http.StatusNotFound, nil)
+	do(http.MethodPost, "/v2/pull-requests/nope/merge", "", http.StatusNotFound, nil)
+	do(http.MethodPost, "/v2/pull-requests/pr-1/reviewers", `{"old_reviewer_id":"u3"}`, http.StatusConflict, nil)
+	do(http.MethodPatch, "/v2/users/u2", `{"skills":"go"}`, http.StatusBadRequest, nil)
+	do(http.MethodPost, "/v2/teams", `{"team_name":"payments","members":[]}`, http.StatusBadRequest, nil)
+}
+
+func TestUpdateUserV2_RejectsPartialInput(t *testing.T) {
+	r, st := buildRouter(t)
+
+	for name, body := range map[string]string{
+		"empty":            `{}`,
+		"partial schedule": `{"time_zone":"UTC","work_start":"09:00"}`,
+		"bad time":         `{"is_active":false,"time_zone":"UTC","work_start":"9am","work_end":"18:00"}`,
+		"bad time zone":    `{"is_active":false,"time_zone":"Mars/Olympus","work_start":"09:00","work_end":"18:00"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPatch, "/v2/users/u2", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d, body=%s", w.Code, w.Body.String())
+			}
+			if !mustGetUser(t, st, "u2").IsActive {
+				t.Fatalf("expected a rejected update to leave u2 untouched")
+			}
+		})
+	}
+}
+
+func TestGitHubWebhook(t *testing.T) {
+	r, st := buildRouter(t)
+
+	deliver := func(event, fixture string, sign func([]byte) string) (int, dto.GitHubWebhookResponse) {
+		t.Helper()
+		body, err := os.ReadFile(filepath.Join("testdata", "github", fixture))
+		if err != nil {
+			t.Fatalf("read fixture: %v", err)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/integrations/github/webhook", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(GitHubEventHeader, event)
+		if sig := sign(body); sig != "" {
+			req.Header.Set(GitHubSignatureHeader, sig)
+		}
+		r.ServeHTTP(w, req)
+
+		var resp dto.GitHubWebhookResponse
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+		}
+		return w.Code, resp
+	}
+	signed := func(body []byte) string {
+		mac := hmac.New(sha256.New, []byte(testWebhookSecret))
+		mac.Write(body)
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	for name, sign := range map[string]func([]byte) string{
+		"unsigned":   func([]byte) string { return "" },
+		"sha1":       func([]byte) string { return "sha1=0123456789abcdef0123456789abcdef01234567" },
+		"wrong key":  func(body []byte) string { return signed(append([]byte("x"), body...)) },
+		"not hex":    func([]byte) string { return "sha256=zz" },
+		"empty hmac": func([]byte) string { return "sha256=" },
+	} {
+		if code, _ := deliver("pull_request", "pull_request_opened.json", sign); code != http.StatusUnauthorized {
+			t.Fatalf("%s: expected status 401, got %d", name, code)
+		}
+	}
+	if _, err := st.prs.GetByID(context.Background(), "acme/api#42"); err == nil {
+		t.Fatalf("expected an unsigned delivery to change nothing")
+	}
+
+	steps := []struct {
+		event, fixture string
+		result, reason string
+	}{
+		{"ping", "ping.json", "ignored", "unsupported event ping"},
+		{"pull_request", "pull_request_opened.json", "created", ""},
+		{"pull_request", "pull_request_opened.json", "ignored", "already tracked"},
+		{"pull_request", "pull_request_synchronize.json", "ignored", "unsupported action synchronize"},
+		{"pull_request", "pull_request_opened_draft.json", "ignored", "draft"},
+		{"pull_request", "pull_request_opened_unmapped.json", "ignored", "no user for GitHub login drive-by-dev"},
+		{"pull_request", "pull_request_ready_for_review.json", "created", ""},
+		{"pull_request", "pull_request_closed_unmerged.json", "ignored", "closed without merge"},
+		{"pull_request", "pull_request_reopened.json", "ignored", "already tracked"},
+		{"pull_request", "pull_request_closed_merged.json", "merged", ""},
+		{"pull_request", "pull_request_closed_merged.json", "merged", ""},
+	}
+	for _, step := range steps {
+		code, resp := deliver(step.event, step.fixture, signed)
+		if code != http.StatusOK || resp.Result != step.result || resp.Reason != step.reason {
+			t.Fatalf("%s: expected %s %q, got %d %+v", step.fixture, step.result, step.reason, code, resp)
+		}
+	}
+
+	merged := mustGetPR(t, st, "acme/api#42")
+	if merged.Status != pull_request.MERGED || merged.AuthorId != "author" || merged.PullRequestName != "Add full-text search" {
+		t.Fatalf("unexpected acme/api#42: %+v", merged)
+	}
+	if len(merged.AssignedReviewers) != 2 {
+		t.Fatalf("expected reviewers to be assigned, got %v", merged.AssignedReviewers)
+	}
+	if pr := mustGetPR(t, st, "acme/api#43"); pr.Status != pull_request.OPEN {
+		t.Fatalf("expected acme/api#43 to stay open, got %s", pr.Status)
+	}
+	if _, err := st.prs.GetByID(context.Background(), "acme/api#44"); err == nil {
+		t.Fatalf("expected the PR of an unmapped login to be ignored")
+	}
+}
+
+func TestParseGitHubLogins(t *testing.T) {
+	logins, err := ParseGitHubLogins([]string{"Octocat=u1", " hubot = u2 "})
+	if err != nil {
+		t.Fatalf("ParseGitHubLogins() error = %v", err)
+	}
+	if logins["octocat"] != "u1" || logins["hubot"] != "u2" || len(logins) != 2 {
+		t.Fatalf("unexpected logins: %v", logins)
+	}
+
+	for _, spec := range []string{"octocat", "=u1", "octocat="} {
+		if _, err := ParseGitHubLogins([]string{spec}); err == nil {
+			t.Fatalf("%q: expected an error", spec)
+		}
+	}
 }
@@ -2,9 +2,11 @@ package http
 
 import (
 	"InternshipTask/internal/app/dto"
+	"InternshipTask/internal/domain/auth"
 	"InternshipTask/internal/domain/pull_request"
-	"errors"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,14 +18,21 @@ func (h *Handler) createPullRequest(c *gin.Context) {
 		return
 	}
 
-	pr, err := h.prService.Create(c.Request.Context(), req.PullRequestID, req.PullRequestName, req.AuthorID)
+	// The PR belongs to TeamName when it is given, and Create checks that the
+	// author is a member of it.
+	if !h.authorize(c, auth.Resource{UserID: req.AuthorID, Team: req.TeamName}) {
+		return
+	}
+
+	pr, err := h.prService.Create(c.Request.Context(), toCreateParams(req))
 	if err != nil {
-		writeError(c, http.StatusInternalServerError, "INTERNAL", err.Error())
+		writeDomainError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"pr": toPullRequestDTO(pr),
+		"pr":             toPullRequestDTO(pr),
+		"skill_fallback": pr.SkillFallback,
 	})
 }
 
@@ -34,13 +43,13 @@ func (h *Handler) mergePullRequest(c *gin.Context) {
 		return
 	}
 
+	if !h.authorizePR(c, req.PullRequestID, "") {
+		return
+	}
+
 	pr, err := h.prService.Merge(c.Request.Context(), req.PullRequestID)
 	if err != nil {
-		if errors.Is(err, pull_request.ErrNotFound) {
-			writeError(c, http.StatusNotFound, "NOT_FOUND", "pr not found")
-			return
-		}
-		writeError(c, http.StatusInternalServerError, "INTERNAL", err.Error())
+		writeDomainError(c, err)
 		return
 	}
 
@@ -56,9 +65,13 @@ func (h *Handler) reassignPullRequest(c *gin.Context) {
 		return
 	}
 
+	if !h.authorizePR(c, req.PullRequestID, req.OldUserID) {
+		return
+	}
+
 	pr, replacedBy, err := h.prService.Reassign(c.Request.Context(), req.PullRequestID, req.OldUserID)
 	if err != nil {
-		writeError(c, http.StatusInternalServerError, "INTERNAL", err.Error())
+		writeDomainError(c, err)
 		return
 	}
 
@@ -68,13 +81,225 @@ func (h *Handler) reassignPullRequest(c *gin.Context) {
 	})
 }
 
+func (h *Handler) getPullRequest(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+	if prID == "" {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", "pull_request_id is required")
+		return
+	}
+
+	var expand pull_request.Expand
+	if v := c.Query("expand"); v != "" {
+		for _, field := range strings.Split(v, ",") {
+			switch strings.TrimSpace(field) {
+			case "author":
+				expand.Author = true
+			case "reviewers":
+				expand.Reviewers = true
+			default:
+				writeError(c, http.StatusBadRequest, "INVALID_REQUEST", "expand must be a list of author, reviewers")
+				return
+			}
+		}
+	}
+
+	d, err := h.prService.GetDetails(c.Request.Context(), prID, expand)
+	if err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	resp := dto.PullRequestDetailsDTO{PullRequestDTO: toPullRequestDTO(d.PR)}
+	if d.Author != nil {
+		author := toPullRequestUserDTO(*d.Author)
+		resp.Author = &author
+	}
+	if d.Reviewers != nil {
+		resp.Reviewers = make([]dto.PullRequestUserDTO, 0, len(d.Reviewers))
+		for _, r := range d.Reviewers {
+			resp.Reviewers = append(resp.Reviewers, toPullRequestUserDTO(r))
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pr": resp,
+	})
+}
+
+func (h *Handler) listPullRequests(c *gin.Context) {
+	f, ok := listFilterQuery(c)
+	if !ok {
+		return
+	}
+
+	page, err := h.prService.List(c.Request.Context(), f)
+	if err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	resp := dto.PullRequestListResponse{PullRequests: make([]dto.PullRequestDTO, 0, len(page.Items))}
+	for i := range page.Items {
+		resp.PullRequests = append(resp.PullRequests, toPullRequestDTO(&page.Items[i]))
+	}
+	if page.Next != nil {
+		resp.NextCursor = page.Next.String()
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// listFilterQuery reads the filters, sort and page of a PR list from the
+// query. On a malformed value it writes a 400 and reports false.
+func listFilterQuery(c *gin.Context) (pull_request.ListFilter, bool) {
+	f := pull_request.ListFilter{
+		Status:     pull_request.PullRequestStatus(c.Query("status")),
+		AuthorID:   c.Query("author_id"),
+		TeamName:   c.Query("team_name"),
+		ReviewerID: c.Query("reviewer_id"),
+		Name:       c.Query("name"),
+		Sort:       pull_request.SortOrder(c.Query("sort")),
+	}
+
+	times := map[string]*time.Time{
+		"created_from": &f.CreatedFrom,
+		"created_to":   &f.CreatedTo,
+		"merged_from":  &f.MergedFrom,
+		"merged_to":    &f.MergedTo,
+	}
+	if !timeQuery(c, times) {
+		return f, false
+	}
+
+	var ok bool
+	if f.Limit, f.After, ok = pageQuery(c); !ok {
+		return f, false
+	}
+
+	if f.IncludeArchived, ok = includeArchived(c); !ok {
+		return f, false
+	}
+
+	return f, true
+}
+
+func (h *Handler) setPullRequestVerdict(c *gin.Context) {
+	var req dto.SetVerdictRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	if !h.authorize(c, auth.Resource{UserID: req.UserID}) {
+		return
+	}
+
+	a, err := h.prService.SetVerdict(c.Request.Context(), req.PullRequestID, req.UserID, pull_request.Verdict(req.Verdict))
+	if err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pull_request_id": req.PullRequestID,
+		"assignment":      toReviewAssignmentDTO(a),
+	})
+}
+
+func (h *Handler) getPullRequestSLA(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+	if prID == "" {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", "pull_request_id is required")
+		return
+	}
+
+	sla, err := h.prService.ReviewSLA(c.Request.Context(), prID)
+	if err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	resp := dto.ReviewSLADTO{
+		PullRequestID: sla.PullRequestID,
+		SLAHours:      sla.Target.Hours(),
+		Reviewers:     make([]dto.ReviewerSLADTO, 0, len(sla.Reviewers)),
+	}
+	for _, r := range sla.Reviewers {
+		resp.Reviewers = append(resp.Reviewers, dto.ReviewerSLADTO{
+			UserID:       r.ReviewerID,
+			TimeZone:     r.TimeZone,
+			WorkingHours: r.WorkingTime.Hours(),
+			Breached:     r.Breached,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) getPullRequestAssignments(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+	if prID == "" {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", "pull_request_id is required")
+		return
+	}
+
+	assignments, err := h.prService.GetAssignments(c.Request.Context(), prID)
+	if err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	resp := dto.ReviewAssignmentsResponse{
+		PullRequestID: prID,
+		Assignments:   make([]dto.ReviewAssignmentDTO, 0, len(assignments)),
+	}
+	for i := range assignments {
+		resp.Assignments = append(resp.Assignments, toReviewAssignmentDTO(&assignments[i]))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func toReviewAssignmentDTO(a *pull_request.Assignment) dto.ReviewAssignmentDTO {
+	return dto.ReviewAssignmentDTO{
+		ReviewerID:     a.ReviewerID,
+		AssignedAt:     a.AssignedAt,
+		AssignedReason: string(a.AssignedReason),
+		AssignedBy:     a.AssignedBy,
+		UnassignedAt:   a.UnassignedAt,
+		UnassignReason: string(a.UnassignReason),
+		UnassignedBy:   a.UnassignedBy,
+		Verdict:        string(a.Verdict),
+		VerdictAt:      a.VerdictAt,
+	}
+}
+
+func toPullRequestUserDTO(p pull_request.Participant) dto.PullRequestUserDTO {
+	return dto.PullRequestUserDTO{
+		UserDTO:     toUserDTO(p.User),
+		OpenReviews: p.OpenReviews,
+	}
+}
+
+func toCreateParams(req dto.CreatePullRequestRequest) pull_request.CreateParams {
+	return pull_request.CreateParams{
+		ID:             req.PullRequestID,
+		Name:           req.PullRequestName,
+		AuthorID:       req.AuthorID,
+		TeamName:       req.TeamName,
+		RequiredSkills: req.RequiredSkills,
+	}
+}
+
 func toPullRequestDTO(pr *pull_request.PR) dto.PullRequestDTO {
 	return dto.PullRequestDTO{
 		PullRequestID:     pr.PullRequestId,
 		PullRequestName:   pr.PullRequestName,
 		AuthorID:          pr.AuthorId,
+		TeamName:          pr.TeamName,
 		Status:            pr.Status.String(),
 		AssignedReviewers: pr.AssignedReviewers,
+		RequiredSkills:    pr.RequiredSkills,
 		CreatedAt:         pr.CreatedAt,
 		MergedAt:          pr.MergedAt,
 	}
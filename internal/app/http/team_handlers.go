@@ -2,6 +2,7 @@ package http
 
 import (
 	"InternshipTask/internal/app/dto"
+	"InternshipTask/internal/domain/auth"
 	"InternshipTask/internal/domain/team"
 	"InternshipTask/internal/domain/user"
 	"net/http"
@@ -16,13 +17,9 @@ func (h *Handler) createTeam(c *gin.Context) {
 		return
 	}
 
-	domainTeam := team.NewTeam(req.TeamName)
-	for i, m := range req.Members {
-		domainTeam.Members[uint(i)] = user.NewUser(m.UserID, m.Username, req.TeamName, m.IsActive)
-	}
-
+	domainTeam := fromTeamDTO(req)
 	if err := h.teamService.Create(c.Request.Context(), *domainTeam); err != nil {
-		writeError(c, http.StatusInternalServerError, "INTERNAL", err.Error())
+		writeDomainError(c, err)
 		return
 	}
 
@@ -40,13 +37,67 @@ func (h *Handler) getTeam(c *gin.Context) {
 
 	t, err := h.teamService.GetByTeamName(c.Request.Context(), teamName)
 	if err != nil {
-		writeError(c, http.StatusInternalServerError, "INTERNAL", err.Error())
+		writeDomainError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, toTeamDTOPtr(&t))
 }
 
+func (h *Handler) addTeamMember(c *gin.Context) {
+	var req dto.TeamMembershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	// Team-scoped permissions on a user follow their memberships, so a lead
+	// may only pull in users who already share one of the lead's teams.
+	if !h.authorize(c, auth.Resource{Team: req.TeamName}) || !h.authorize(c, auth.Resource{UserID: req.UserID}) {
+		return
+	}
+
+	if err := h.teamService.AddMember(c.Request.Context(), req.TeamName, req.UserID); err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"team_name": req.TeamName,
+		"user_id":   req.UserID,
+	})
+}
+
+func (h *Handler) removeTeamMember(c *gin.Context) {
+	var req dto.TeamMembershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	if !h.authorize(c, auth.Resource{Team: req.TeamName}) {
+		return
+	}
+
+	if err := h.teamService.RemoveMember(c.Request.Context(), req.TeamName, req.UserID); err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"team_name": req.TeamName,
+		"user_id":   req.UserID,
+	})
+}
+
+func fromTeamDTO(req dto.TeamDTO) *team.Team {
+	t := team.NewTeam(req.TeamName)
+	for i, m := range req.Members {
+		t.Members[uint(i)] = user.NewUser(m.UserID, m.Username, req.TeamName, m.IsActive)
+	}
+	return t
+}
+
 func toTeamDTO(t *team.Team) dto.TeamDTO {
 	return *toTeamDTOPtr(t)
 }
@@ -2,9 +2,11 @@ package http
 
 import (
 	"InternshipTask/internal/app/dto"
+	"InternshipTask/internal/domain/auth"
+	"InternshipTask/internal/domain/pull_request"
 	"InternshipTask/internal/domain/user"
-	"context"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,9 +18,72 @@ func (h *Handler) setUserIsActive(c *gin.Context) {
 		return
 	}
 
+	if !h.authorize(c, auth.Resource{UserID: req.UserID}) {
+		return
+	}
+
 	u, err := h.userService.SetIsActive(c.Request.Context(), req.UserID, req.IsActive)
 	if err != nil {
-		writeError(c, http.StatusInternalServerError, "INTERNAL", err.Error())
+		writeDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user": toUserDTO(u),
+	})
+}
+
+func (h *Handler) setUserSkills(c *gin.Context) {
+	var req dto.SetUserSkillsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	if !h.authorize(c, auth.Resource{UserID: req.UserID}) {
+		return
+	}
+
+	u, err := h.userService.SetSkills(c.Request.Context(), req.UserID, req.Skills)
+	if err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user": toUserDTO(u),
+	})
+}
+
+func (h *Handler) setUserSchedule(c *gin.Context) {
+	var req dto.SetUserScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	if !h.authorize(c, auth.Resource{UserID: req.UserID}) {
+		return
+	}
+
+	start, err := user.ParseTimeOfDay(req.WorkStart)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+	end, err := user.ParseTimeOfDay(req.WorkEnd)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	u, err := h.userService.SetSchedule(c.Request.Context(), req.UserID, user.Schedule{
+		TimeZone:  req.TimeZone,
+		WorkStart: start,
+		WorkEnd:   end,
+	})
+	if err != nil {
+		writeDomainError(c, err)
 		return
 	}
 
@@ -27,6 +92,41 @@ func (h *Handler) setUserIsActive(c *gin.Context) {
 	})
 }
 
+func (h *Handler) offboardUser(c *gin.Context) {
+	var req dto.OffboardUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	if !h.authorize(c, auth.Resource{UserID: req.UserID}) {
+		return
+	}
+
+	result, err := h.offboardingService.Offboard(c.Request.Context(), req.UserID, req.TransferTo)
+	if err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	resp := dto.OffboardUserResponse{
+		User:        toUserDTO(result.User),
+		Reassigned:  make([]dto.OffboardReassignmentDTO, 0, len(result.Reassigned)),
+		Transferred: result.Transferred,
+	}
+	for _, r := range result.Reassigned {
+		resp.Reassigned = append(resp.Reassigned, dto.OffboardReassignmentDTO{
+			PullRequestID: r.PullRequestID,
+			ReplacedBy:    r.ReplacedBy,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// reviewStatusAll lifts the default OPEN status filter of getUserReviews.
+const reviewStatusAll = "ALL"
+
 func (h *Handler) getUserReviews(c *gin.Context) {
 	userID := c.Query("user_id")
 	if userID == "" {
@@ -34,34 +134,96 @@ func (h *Handler) getUserReviews(c *gin.Context) {
 		return
 	}
 
-	prs, err := h.prService.GetByReviewerID(context.Background(), userID)
+	f := pull_request.ReviewFilter{
+		ReviewerID: userID,
+		Status:     pull_request.OPEN,
+		TeamName:   c.Query("team_name"),
+	}
+	switch v := c.Query("status"); v {
+	case "":
+	case reviewStatusAll:
+		f.Status = ""
+	default:
+		f.Status = pull_request.PullRequestStatus(v)
+	}
+
+	if !timeQuery(c, map[string]*time.Time{"created_from": &f.CreatedFrom, "created_to": &f.CreatedTo}) {
+		return
+	}
+
+	var ok bool
+	if f.Limit, f.After, ok = pageQuery(c); !ok {
+		return
+	}
+	if f.IncludeArchived, ok = includeArchived(c); !ok {
+		return
+	}
+
+	page, err := h.prService.ListReviews(c.Request.Context(), f)
 	if err != nil {
-		writeError(c, http.StatusInternalServerError, "INTERNAL", err.Error())
+		writeDomainError(c, err)
 		return
 	}
 
 	resp := dto.UserReviewsResponse{
 		UserID:       userID,
-		PullRequests: make([]dto.PullRequestShortDTO, 0, len(prs)),
+		PullRequests: make([]dto.UserReviewDTO, 0, len(page.Items)),
 	}
-
-	for _, p := range prs {
-		resp.PullRequests = append(resp.PullRequests, dto.PullRequestShortDTO{
-			PullRequestID:   p.PullRequestId,
-			PullRequestName: p.PullRequestName,
-			AuthorID:        p.AuthorId,
-			Status:          p.Status.String(),
+	for _, r := range page.Items {
+		resp.PullRequests = append(resp.PullRequests, dto.UserReviewDTO{
+			PullRequestShortDTO: dto.PullRequestShortDTO{
+				PullRequestID:   r.PullRequestId,
+				PullRequestName: r.PullRequestName,
+				AuthorID:        r.AuthorId,
+				Status:          r.Status.String(),
+			},
+			TeamName:   r.TeamName,
+			CreatedAt:  r.CreatedAt,
+			AssignedAt: r.AssignedAt,
+			AgeHours:   r.Age.Hours(),
+			Verdict:    string(r.Verdict),
+			VerdictAt:  r.VerdictAt,
 		})
 	}
+	if page.Next != nil {
+		resp.NextCursor = page.Next.String()
+	}
 
 	c.JSON(http.StatusOK, resp)
 }
 
+func (h *Handler) getUserTeams(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", "user_id is required")
+		return
+	}
+
+	teams, err := h.teamService.GetTeamNamesByUserID(c.Request.Context(), userID)
+	if err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.UserTeamsResponse{
+		UserID: userID,
+		Teams:  teams,
+	})
+}
+
 func toUserDTO(u *user.User) dto.UserDTO {
-	return dto.UserDTO{
-		UserID:   u.UserId,
-		Username: u.UserName,
-		TeamName: u.TeamName,
-		IsActive: u.IsActive,
+	result := dto.UserDTO{
+		UserID:     u.UserId,
+		Username:   u.UserName,
+		TeamName:   u.TeamName,
+		IsActive:   u.IsActive,
+		Skills:     u.Skills,
+		DepartedAt: u.DepartedAt,
+	}
+	if !u.Schedule.IsZero() {
+		result.TimeZone = u.Schedule.TimeZone
+		result.WorkStart = user.FormatTimeOfDay(u.Schedule.WorkStart)
+		result.WorkEnd = user.FormatTimeOfDay(u.Schedule.WorkEnd)
 	}
+	return result
 }
@@ -0,0 +1,214 @@
+package http
+
+import (
+	"InternshipTask/internal/app/dto"
+	"InternshipTask/internal/domain/auth"
+	"InternshipTask/internal/domain/pull_request"
+	"InternshipTask/internal/domain/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerV2Routes adds the resource-oriented /v2 API described by
+// openapi-v2.yml. It runs on the same services as the legacy routes.
+func registerV2Routes(r *gin.Engine, h *Handler) {
+	v2 := r.Group("/v2")
+
+	v2.GET("/teams", h.require(auth.PermRead), h.listTeamsV2)
+	v2.POST("/teams", h.require(auth.PermCreateTeam), h.createTeamV2)
+
+	v2.GET("/users/:id", h.require(auth.PermRead), h.getUserV2)
+	v2.PATCH("/users/:id", h.require(auth.PermManageUser), h.updateUserV2)
+
+	v2.GET("/pull-requests", h.require(auth.PermRead), h.listPullRequestsV2)
+	v2.POST("/pull-requests", h.require(auth.PermManagePR), h.createPullRequestV2)
+	v2.POST("/pull-requests/:id/merge", h.require(auth.PermManagePR), h.mergePullRequestV2)
+	v2.POST("/pull-requests/:id/reviewers", h.require(auth.PermReassign), h.replaceReviewerV2)
+}
+
+func (h *Handler) listTeamsV2(c *gin.Context) {
+	teams, err := h.teamService.List(c.Request.Context())
+	if err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	resp := dto.TeamListResponse{Teams: make([]dto.TeamDTO, 0, len(teams))}
+	for i := range teams {
+		resp.Teams = append(resp.Teams, toTeamDTO(&teams[i]))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) createTeamV2(c *gin.Context) {
+	var req dto.TeamDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	t := fromTeamDTO(req)
+	if err := h.teamService.Create(c.Request.Context(), *t); err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toTeamDTO(t))
+}
+
+func (h *Handler) getUserV2(c *gin.Context) {
+	u, err := h.userService.GetByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toUserDTO(u))
+}
+
+func (h *Handler) updateUserV2(c *gin.Context) {
+	var req dto.UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	id := c.Param("id")
+	if !h.authorize(c, auth.Resource{UserID: id}) {
+		return
+	}
+
+	update := user.Update{IsActive: req.IsActive, Skills: req.Skills}
+	switch {
+	case req.TimeZone == nil && req.WorkStart == nil && req.WorkEnd == nil:
+	case req.TimeZone == nil || req.WorkStart == nil || req.WorkEnd == nil:
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", "time_zone, work_start and work_end must be set together")
+		return
+	default:
+		start, err := user.ParseTimeOfDay(*req.WorkStart)
+		if err != nil {
+			writeError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+			return
+		}
+		end, err := user.ParseTimeOfDay(*req.WorkEnd)
+		if err != nil {
+			writeError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+			return
+		}
+		update.Schedule = &user.Schedule{TimeZone: *req.TimeZone, WorkStart: start, WorkEnd: end}
+	}
+	if update.IsActive == nil && update.Skills == nil && update.Schedule == nil {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", "nothing to update")
+		return
+	}
+
+	u, err := h.userService.Update(c.Request.Context(), id, update)
+	if err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toUserDTO(u))
+}
+
+func (h *Handler) listPullRequestsV2(c *gin.Context) {
+	f, ok := listFilterQuery(c)
+	if !ok {
+		return
+	}
+
+	page, err := h.prService.List(c.Request.Context(), f)
+	if err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	resp := dto.PullRequestListV2Response{PullRequests: make([]dto.PullRequestV2DTO, 0, len(page.Items))}
+	for i := range page.Items {
+		resp.PullRequests = append(resp.PullRequests, toPullRequestV2DTO(&page.Items[i]))
+	}
+	if page.Next != nil {
+		resp.NextCursor = page.Next.String()
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) createPullRequestV2(c *gin.Context) {
+	var req dto.CreatePullRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	// The PR belongs to TeamName when it is given, and Create checks that the
+	// author is a member of it.
+	if !h.authorize(c, auth.Resource{UserID: req.AuthorID, Team: req.TeamName}) {
+		return
+	}
+
+	pr, err := h.prService.Create(c.Request.Context(), toCreateParams(req))
+	if err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	resp := toPullRequestV2DTO(pr)
+	resp.SkillFallback = pr.SkillFallback
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *Handler) mergePullRequestV2(c *gin.Context) {
+	id := c.Param("id")
+	if !h.authorizePR(c, id, "") {
+		return
+	}
+
+	pr, err := h.prService.Merge(c.Request.Context(), id)
+	if err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toPullRequestV2DTO(pr))
+}
+
+func (h *Handler) replaceReviewerV2(c *gin.Context) {
+	var req dto.ReplaceReviewerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	id := c.Param("id")
+	if !h.authorizePR(c, id, req.OldReviewerID) {
+		return
+	}
+
+	pr, replacedBy, err := h.prService.Reassign(c.Request.Context(), id, req.OldReviewerID)
+	if err != nil {
+		writeDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ReplaceReviewerResponse{
+		PullRequest: toPullRequestV2DTO(pr),
+		ReplacedBy:  replacedBy,
+	})
+}
+
+func toPullRequestV2DTO(pr *pull_request.PR) dto.PullRequestV2DTO {
+	return dto.PullRequestV2DTO{
+		PullRequestID:     pr.PullRequestId,
+		PullRequestName:   pr.PullRequestName,
+		AuthorID:          pr.AuthorId,
+		TeamName:          pr.TeamName,
+		Status:            pr.Status.String(),
+		AssignedReviewers: pr.AssignedReviewers,
+		RequiredSkills:    pr.RequiredSkills,
+		CreatedAt:         pr.CreatedAt,
+		MergedAt:          pr.MergedAt,
+	}
+}
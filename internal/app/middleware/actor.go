@@ -0,0 +1,20 @@
+package logger
+
+import (
+	"InternshipTask/internal/domain/actor"
+
+	"github.com/gin-gonic/gin"
+)
+
+const ActorHeader = "X-Actor-ID"
+
+// ActorMiddleware puts the caller id from the X-Actor-ID header into the
+// request context so that domain services can attribute their changes.
+func ActorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if id := c.GetHeader(ActorHeader); id != "" {
+			c.Request = c.Request.WithContext(actor.WithID(c.Request.Context(), id))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"InternshipTask/internal/domain/actor"
+	"InternshipTask/internal/domain/auth"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthMiddleware authenticates the bearer token of the Authorization header
+// and makes its owner the principal and the actor of the request. Requests
+// without a token go on unauthenticated, so that routes decide whether they
+// need one; a bad token is rejected right away.
+func AuthMiddleware(svc *auth.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" {
+			c.Next()
+			return
+		}
+
+		secret, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok {
+			abortWithError(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authorization must be a Bearer token")
+			return
+		}
+
+		p, err := svc.Authenticate(c.Request.Context(), strings.TrimSpace(secret))
+		if errors.Is(err, auth.ErrUnauthenticated) {
+			abortWithError(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+			return
+		}
+		if err != nil {
+			abortWithError(c, http.StatusInternalServerError, "INTERNAL", err.Error())
+			return
+		}
+
+		ctx := auth.WithPrincipal(c.Request.Context(), p)
+		c.Request = c.Request.WithContext(actor.WithID(ctx, p.UserID))
+		c.Next()
+	}
+}
@@ -0,0 +1,155 @@
+package logger
+
+import (
+	"InternshipTask/internal/app/dto"
+	"InternshipTask/internal/domain/actor"
+	"InternshipTask/internal/domain/clock"
+	"InternshipTask/internal/domain/idempotency"
+	"InternshipTask/internal/domain/logging"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// IdempotencyMiddleware makes POST requests carrying an Idempotency-Key
+// header safe to retry: the first response for a key is stored for ttl and
+// replayed for later requests with the same key, method, path and body. A
+// key reused for a different request gets 422, and a retry that arrives
+// while the first request is still running gets 409. 5xx responses and
+// responses marked Cache-Control: no-store, e.g. ones carrying secrets, are
+// not stored, so such requests run again on a retry. Keys are scoped to the
+// actor, so one caller cannot replay another's responses.
+func IdempotencyMiddleware(store idempotency.Store, ttl time.Duration, clk clock.Clock) gin.HandlerFunc {
+	if ttl <= 0 {
+		ttl = idempotency.DefaultTTL
+	}
+	if clk == nil {
+		clk = clock.Real{}
+	}
+
+	return func(c *gin.Context) {
+		header := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || header == "" {
+			c.Next()
+			return
+		}
+		if len(header) > maxIdempotencyKeyLength {
+			abortWithError(c, http.StatusBadRequest, "INVALID_REQUEST", "Idempotency-Key is too long")
+			return
+		}
+		// The actor is escaped so that it cannot run into the key.
+		key := url.PathEscape(actor.FromContext(c.Request.Context())) + "/" + header
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			abortWithError(c, http.StatusBadRequest, "INVALID_REQUEST", "cannot read request body")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		fp := fingerprint(c.Request.Method, c.Request.URL.Path, body)
+		now := clk.Now()
+		existing, err := store.Reserve(c.Request.Context(), idempotency.Record{
+			Key:         key,
+			Fingerprint: fp,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+		})
+		if err != nil {
+			abortWithError(c, http.StatusInternalServerError, "INTERNAL", err.Error())
+			return
+		}
+
+		if existing != nil {
+			switch {
+			case existing.Fingerprint != fp:
+				abortWithError(c, http.StatusUnprocessableEntity, "IDEMPOTENCY_KEY_REUSED",
+					"Idempotency-Key was already used for a different request")
+			case existing.Pending():
+				abortWithError(c, http.StatusConflict, "IDEMPOTENCY_IN_PROGRESS",
+					"a request with this Idempotency-Key is still in progress")
+			default:
+				c.Header(IdempotentReplayedHeader, "true")
+				c.Data(existing.Status, "application/json; charset=utf-8", existing.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		w := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		// The outcome is saved in a defer so that a panicking handler, which
+		// RecoveryMiddleware turns into a 500 further up, releases the key too.
+		defer func() {
+			// The client may be gone by now, but the outcome must still be saved.
+			ctx := context.WithoutCancel(c.Request.Context())
+			p := recover()
+			var err error
+			if status := w.Status(); p != nil || status >= http.StatusInternalServerError || w.Header().Get("Cache-Control") == "no-store" {
+				err = store.Release(ctx, key)
+			} else {
+				err = store.Complete(ctx, key, status, w.body.Bytes())
+			}
+			if err != nil {
+				logging.FromContext(ctx).ErrorContext(ctx, "save idempotent response",
+					slog.String("idempotency_key", header),
+					slog.Any("err", err),
+				)
+			}
+			if p != nil {
+				panic(p)
+			}
+		}()
+		c.Next()
+	}
+}
+
+func fingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func abortWithError(c *gin.Context, status int, code, message string) {
+	c.Set(ErrorCodeKey, code)
+	c.AbortWithStatusJSON(status, dto.ErrorDTO{
+		Error: dto.ErrorContent{
+			Code:    code,
+			Message: message,
+		},
+	})
+}
+
+// recordingWriter keeps a copy of the response body for replays.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
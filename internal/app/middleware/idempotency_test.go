@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"InternshipTask/internal/app/dto"
+	"InternshipTask/internal/domain/clock"
+	"InternshipTask/internal/infrastructure/memory"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+	calls := 0
+	status := http.StatusCreated
+
+	r := gin.New()
+	r.Use(IdempotencyMiddleware(memory.NewIdempotencyStorage(memory.NewDB()), time.Hour, clock.Func(func() time.Time { return now })))
+	r.POST("/team/add", func(c *gin.Context) {
+		calls++
+		c.JSON(status, gin.H{"call": calls})
+	})
+
+	do := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/team/add", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	errorCode := func(w *httptest.ResponseRecorder) string {
+		var resp dto.ErrorDTO
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("unmarshal error response: %v", err)
+		}
+		return resp.Error.Code
+	}
+
+	first := do("k1", `{"team_name":"backend"}`)
+	if first.Code != http.StatusCreated || first.Body.String() != `{"call":1}` {
+		t.Fatalf("unexpected first response: %d %s", first.Code, first.Body.String())
+	}
+
+	replay := do("k1", `{"team_name":"backend"}`)
+	if replay.Code != http.StatusCreated || replay.Body.String() != `{"call":1}` || calls != 1 {
+		t.Fatalf("expected the first response to be replayed, got %d %s after %d calls", replay.Code, replay.Body.String(), calls)
+	}
+	if replay.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Fatalf("expected the replay to be marked")
+	}
+
+	if w := do("k1", `{"team_name":"frontend"}`); w.Code != http.StatusUnprocessableEntity || errorCode(w) != "IDEMPOTENCY_KEY_REUSED" {
+		t.Fatalf("expected 422 for a different body, got %d %s", w.Code, w.Body.String())
+	}
+
+	if w := do("", `{"team_name":"backend"}`); w.Code != http.StatusCreated || calls != 2 {
+		t.Fatalf("expected requests without a key to run, got %d after %d calls", w.Code, calls)
+	}
+
+	status = http.StatusInternalServerError
+	do("k2", `{}`)
+	status = http.StatusCreated
+	if w := do("k2", `{}`); w.Code != http.StatusCreated || calls != 4 {
+		t.Fatalf("expected a failed request to run again, got %d after %d calls", w.Code, calls)
+	}
+
+	r.POST("/admin/tokens/create", func(c *gin.Context) {
+		calls++
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusCreated, gin.H{"secret": calls})
+	})
+	for range 2 {
+		req := httptest.NewRequest(http.MethodPost, "/admin/tokens/create", strings.NewReader(`{}`))
+		req.Header.Set(IdempotencyKeyHeader, "k3")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	if calls != 6 {
+		t.Fatalf("expected no-store responses not to be replayed, got %d calls", calls)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if w := do("k1", `{"team_name":"frontend"}`); w.Code != http.StatusCreated || calls != 7 {
+		t.Fatalf("expected an expired key to be usable again, got %d after %d calls", w.Code, calls)
+	}
+}
+
+func TestIdempotencyMiddleware_ReleasesKeyOnPanic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	calls := 0
+	r := gin.New()
+	r.Use(RecoveryMiddleware(), IdempotencyMiddleware(memory.NewIdempotencyStorage(memory.NewDB()), time.Hour, nil))
+	r.POST("/pullRequest/create", func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			panic("boom")
+		}
+		c.JSON(http.StatusCreated, gin.H{"call": calls})
+	})
+
+	do := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", strings.NewReader(`{}`))
+		req.Header.Set(IdempotencyKeyHeader, "k1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do(); w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected the panic to become a 500, got %d %s", w.Code, w.Body.String())
+	}
+	if w := do(); w.Code != http.StatusCreated || w.Body.String() != `{"call":2}` {
+		t.Fatalf("expected the retry to run instead of finding the key in progress, got %d %s", w.Code, w.Body.String())
+	}
+}
@@ -1,13 +1,105 @@
 package logger
 
 import (
-	"fmt"
+	"InternshipTask/internal/domain/actor"
+	"InternshipTask/internal/domain/logging"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
 	"github.com/gin-gonic/gin"
 )
 
-func LoggerMiddleware() gin.HandlerFunc {
+const (
+	RequestIDHeader = "X-Request-ID"
+	// ErrorCodeKey is where an error response leaves its code in the gin
+	// context for the request log.
+	ErrorCodeKey = "error_code"
+
+	maxRequestIDLength = 128
+)
+
+// LoggerMiddleware puts a logger tagged with the request id into the request
+// context and logs every request once it is done. The id is taken from the
+// X-Request-ID header when it looks sane, otherwise generated, and is echoed
+// in the response.
+func LoggerMiddleware(l *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+
+		reqLogger := l.With(slog.String("request_id", id))
+		c.Request = c.Request.WithContext(logging.WithLogger(c.Request.Context(), reqLogger))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("route", c.FullPath()),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("client", c.ClientIP()),
+		}
+		if a := actor.FromContext(c.Request.Context()); a != "" {
+			attrs = append(attrs, slog.String("actor", a))
+		}
+		if code := c.GetString(ErrorCodeKey); code != "" {
+			attrs = append(attrs, slog.String("error_code", code))
+		}
+		reqLogger.LogAttrs(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// RecoveryMiddleware turns a panic into a 500 INTERNAL and logs it with the
+// stack through the request logger.
+func RecoveryMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		fmt.Println("Новый запрос:", c.Request.Method, c.Request.URL.Path)
+		defer func() {
+			if rec := recover(); rec != nil {
+				ctx := c.Request.Context()
+				logging.FromContext(ctx).ErrorContext(ctx, "panic",
+					slog.Any("panic", rec),
+					slog.String("stack", string(debug.Stack())),
+				)
+				abortWithError(c, http.StatusInternalServerError, "INTERNAL", "internal server error")
+			}
+		}()
 		c.Next()
 	}
 }
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
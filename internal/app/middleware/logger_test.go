@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"InternshipTask/internal/domain/logging"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLoggerMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	r := gin.New()
+	r.Use(LoggerMiddleware(slog.New(slog.NewJSONHandler(&buf, nil))), RecoveryMiddleware())
+	r.GET("/team/get", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		logging.FromContext(ctx).InfoContext(ctx, "from handler")
+		abortWithError(c, http.StatusNotFound, "NOT_FOUND", "resource not found")
+	})
+	r.GET("/panic", func(c *gin.Context) { panic("boom") })
+
+	do := func(path, requestID string) (*httptest.ResponseRecorder, []map[string]any) {
+		buf.Reset()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		if requestID != "" {
+			req.Header.Set(RequestIDHeader, requestID)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		var lines []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var m map[string]any
+			if err := json.Unmarshal([]byte(line), &m); err != nil {
+				t.Fatalf("log line is not JSON: %q", line)
+			}
+			lines = append(lines, m)
+		}
+		return w, lines
+	}
+
+	w, lines := do("/team/get?team_name=x", "req-1")
+	if got := w.Header().Get(RequestIDHeader); got != "req-1" {
+		t.Fatalf("expected the request id to be echoed, got %q", got)
+	}
+	if len(lines) != 2 || lines[0]["msg"] != "from handler" || lines[0]["request_id"] != "req-1" {
+		t.Fatalf("expected the handler log to carry the request id, got %v", lines)
+	}
+	entry := lines[1]
+	for key, want := range map[string]any{
+		"msg":        "request",
+		"level":      "WARN",
+		"request_id": "req-1",
+		"route":      "/team/get",
+		"path":       "/team/get",
+		"status":     float64(http.StatusNotFound),
+		"client":     "10.0.0.1",
+		"error_code": "NOT_FOUND",
+	} {
+		if entry[key] != want {
+			t.Fatalf("expected %s=%v, got %v", key, want, entry[key])
+		}
+	}
+	if _, ok := entry["latency_ms"]; !ok {
+		t.Fatal("expected latency_ms")
+	}
+
+	w, lines = do("/panic", "bad id with spaces")
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500 after a panic, got %d", w.Code)
+	}
+	id := w.Header().Get(RequestIDHeader)
+	if len(id) != 32 {
+		t.Fatalf("expected a generated request id, got %q", id)
+	}
+	if len(lines) != 2 || lines[0]["msg"] != "panic" || lines[1]["level"] != "ERROR" ||
+		lines[1]["request_id"] != id || lines[1]["error_code"] != "INTERNAL" {
+		t.Fatalf("unexpected log: %v", lines)
+	}
+}
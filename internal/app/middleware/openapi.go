@@ -0,0 +1,209 @@
+package logger
+
+import (
+	"InternshipTask/internal/domain/logging"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+)
+
+// OpenAPIMode says how strictly OpenAPIMiddleware holds the traffic to the
+// specification.
+type OpenAPIMode string
+
+const (
+	// OpenAPIOff disables the validation.
+	OpenAPIOff OpenAPIMode = "off"
+	// OpenAPIRequests rejects requests that do not match the specification
+	// with 400 INVALID_REQUEST.
+	OpenAPIRequests OpenAPIMode = "requests"
+	// OpenAPIStrict also checks responses and logs the ones that do not
+	// match, without changing them.
+	OpenAPIStrict OpenAPIMode = "strict"
+	// OpenAPITest fails on any mismatch: a response that does not match, or a
+	// response from a route the specification does not describe, is replaced
+	// by 500 INTERNAL naming the violation. Meant for tests.
+	OpenAPITest OpenAPIMode = "test"
+)
+
+// ParseOpenAPIMode parses the mode name as used in the OPENAPI_VALIDATION env.
+func ParseOpenAPIMode(s string) (OpenAPIMode, error) {
+	switch m := OpenAPIMode(s); m {
+	case OpenAPIOff, OpenAPIRequests, OpenAPIStrict, OpenAPITest:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid openapi validation mode %q", s)
+	}
+}
+
+// OpenAPIValidator finds the operation of a request in one or more
+// specifications.
+type OpenAPIValidator struct {
+	routers []routers.Router
+}
+
+// NewOpenAPIValidator loads and validates the specifications. Routes are
+// looked up in them in order.
+func NewOpenAPIValidator(specs ...[]byte) (*OpenAPIValidator, error) {
+	// Keep violations to one line instead of dumping the schema and value.
+	openapi3.SchemaErrorDetailsDisabled = true
+
+	v := &OpenAPIValidator{}
+	for _, spec := range specs {
+		loader := openapi3.NewLoader()
+		doc, err := loader.LoadFromData(spec)
+		if err != nil {
+			return nil, fmt.Errorf("load openapi spec: %w", err)
+		}
+		if err := doc.Validate(loader.Context); err != nil {
+			return nil, fmt.Errorf("validate openapi spec: %w", err)
+		}
+		// The specs describe paths relative to wherever the service runs.
+		doc.Servers = nil
+		router, err := gorillamux.NewRouter(doc)
+		if err != nil {
+			return nil, fmt.Errorf("openapi router: %w", err)
+		}
+		v.routers = append(v.routers, router)
+	}
+	return v, nil
+}
+
+func (v *OpenAPIValidator) findRoute(r *http.Request) (*routers.Route, map[string]string, error) {
+	err := routers.ErrPathNotFound
+	for _, router := range v.routers {
+		route, params, findErr := router.FindRoute(r)
+		if findErr == nil {
+			return route, params, nil
+		}
+		// A path known to some spec with the wrong method is reported as such
+		// even when another spec does not know the path at all.
+		if errors.Is(findErr, routers.ErrMethodNotAllowed) {
+			err = findErr
+		}
+	}
+	return nil, nil, err
+}
+
+// OpenAPIMiddleware checks requests, and in the strict and test modes also
+// responses, against the specifications of v. Authentication is left to
+// AuthMiddleware, so security requirements are not checked here.
+func OpenAPIMiddleware(v *OpenAPIValidator, mode OpenAPIMode) gin.HandlerFunc {
+	options := &openapi3filter.Options{
+		AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+		IncludeResponseStatus: true,
+		MultiError:            true,
+		// The handlers apply their own defaults; the request stays as sent.
+		SkipSettingDefaults: true,
+	}
+
+	return func(c *gin.Context) {
+		if mode == OpenAPIOff {
+			c.Next()
+			return
+		}
+		ctx := c.Request.Context()
+
+		route, params, findErr := v.findRoute(c.Request)
+		var input *openapi3filter.RequestValidationInput
+		if findErr == nil {
+			input = &openapi3filter.RequestValidationInput{
+				Request:    c.Request,
+				PathParams: params,
+				Route:      route,
+				Options:    options,
+			}
+			if err := openapi3filter.ValidateRequest(ctx, input); err != nil {
+				if mode == OpenAPITest && route.Operation.Responses.Status(http.StatusBadRequest) == nil {
+					// The rejection itself would be an undocumented response.
+					reportOpenAPIViolation(c, fmt.Errorf("%s %s: 400 is not documented: %s", c.Request.Method, route.Path, firstViolation(err)))
+					return
+				}
+				abortWithError(c, http.StatusBadRequest, "INVALID_REQUEST", firstViolation(err))
+				return
+			}
+		}
+		if mode == OpenAPIRequests || (findErr != nil && mode != OpenAPITest) {
+			c.Next()
+			return
+		}
+
+		w := &bufferingWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		func() {
+			// A panic is answered by RecoveryMiddleware on the real writer.
+			defer func() { c.Writer = w.ResponseWriter }()
+			c.Next()
+		}()
+
+		var violation error
+		switch {
+		case findErr != nil && c.FullPath() != "":
+			violation = fmt.Errorf("%s %s: %w", c.Request.Method, c.FullPath(), findErr)
+		case findErr == nil:
+			err := openapi3filter.ValidateResponse(ctx, &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: input,
+				Status:                 c.Writer.Status(),
+				Header:                 c.Writer.Header(),
+				Body:                   io.NopCloser(bytes.NewReader(w.body.Bytes())),
+				Options:                options,
+			})
+			if err != nil {
+				violation = fmt.Errorf("%s %s -> %d: %s", c.Request.Method, route.Path, c.Writer.Status(), firstViolation(err))
+			}
+		}
+		if violation != nil {
+			if mode == OpenAPITest {
+				reportOpenAPIViolation(c, violation)
+				return
+			}
+			logging.FromContext(ctx).WarnContext(ctx, "response does not match openapi spec", slog.Any("err", violation))
+		}
+		c.Writer.Write(w.body.Bytes())
+	}
+}
+
+// reportOpenAPIViolation replaces the response with a 500 in the test mode.
+// The status and headers of a response written straight through are already
+// gone, so the violation then only makes it to the log.
+func reportOpenAPIViolation(c *gin.Context, err error) {
+	ctx := c.Request.Context()
+	logging.FromContext(ctx).ErrorContext(ctx, "openapi violation", slog.Any("err", err))
+	if c.Writer.Written() {
+		return
+	}
+	abortWithError(c, http.StatusInternalServerError, "INTERNAL", "openapi violation: "+err.Error())
+}
+
+// firstViolation reports only the first of the errors collected with
+// MultiError, which is enough to fix a request.
+func firstViolation(err error) string {
+	var me openapi3.MultiError
+	if errors.As(err, &me) && len(me) > 0 {
+		err = me[0]
+	}
+	return err.Error()
+}
+
+// bufferingWriter holds the body back until the response is validated.
+type bufferingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferingWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *bufferingWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"InternshipTask/internal/app/dto"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testSpec = `
+openapi: 3.0.3
+info: { title: test, version: "1" }
+paths:
+  /items/add:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [id, active]
+              properties:
+                id: { type: string }
+                active: { type: boolean }
+      responses:
+        '201':
+          description: created
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id]
+                properties:
+                  id: { type: string }
+  /items/get:
+    get:
+      parameters:
+        - { name: id, in: query, required: true, schema: { type: string } }
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id]
+                properties:
+                  id: { type: string }
+`
+
+func TestOpenAPIMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	v, err := NewOpenAPIValidator([]byte(testSpec))
+	if err != nil {
+		t.Fatalf("load spec: %v", err)
+	}
+
+	newRouter := func(mode OpenAPIMode, logs *bytes.Buffer) *gin.Engine {
+		r := gin.New()
+		r.Use(LoggerMiddleware(slog.New(slog.NewJSONHandler(logs, nil))), OpenAPIMiddleware(v, mode))
+		r.POST("/items/add", func(c *gin.Context) {
+			c.JSON(http.StatusCreated, gin.H{"id": "i1"})
+		})
+		// Drifted from the spec: the field is named differently.
+		r.GET("/items/get", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"item_id": c.Query("id")})
+		})
+		r.GET("/undocumented", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{})
+		})
+		return r
+	}
+
+	do := func(r *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	errorCode := func(w *httptest.ResponseRecorder) string {
+		var resp dto.ErrorDTO
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp.Error.Code
+	}
+
+	t.Run("requests", func(t *testing.T) {
+		var logs bytes.Buffer
+		r := newRouter(OpenAPIRequests, &logs)
+
+		if w := do(r, http.MethodPost, "/items/add", `{"id":"i1","active":false}`); w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", w.Code, w.Body)
+		}
+		w := do(r, http.MethodPost, "/items/add", `{"id":"i1"}`)
+		if w.Code != http.StatusBadRequest || errorCode(w) != "INVALID_REQUEST" || !strings.Contains(w.Body.String(), "active") {
+			t.Fatalf("expected 400 INVALID_REQUEST naming the field, got %d: %s", w.Code, w.Body)
+		}
+		if w := do(r, http.MethodGet, "/items/get", ""); w.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for a missing query parameter, got %d", w.Code)
+		}
+		if w := do(r, http.MethodGet, "/items/get?id=i1", ""); w.Code != http.StatusOK {
+			t.Fatalf("responses are not checked in this mode, got %d", w.Code)
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		var logs bytes.Buffer
+		r := newRouter(OpenAPIStrict, &logs)
+
+		w := do(r, http.MethodGet, "/items/get?id=i1", "")
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"item_id":"i1"`) {
+			t.Fatalf("expected the response to pass through, got %d: %s", w.Code, w.Body)
+		}
+		if !strings.Contains(logs.String(), "response does not match openapi spec") {
+			t.Fatalf("expected the violation to be logged, got %s", logs.String())
+		}
+
+		logs.Reset()
+		if w := do(r, http.MethodPost, "/items/add", `{"id":"i1","active":true}`); w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d", w.Code)
+		}
+		if strings.Contains(logs.String(), "does not match") {
+			t.Fatalf("expected no violation, got %s", logs.String())
+		}
+	})
+
+	t.Run("test", func(t *testing.T) {
+		var logs bytes.Buffer
+		r := newRouter(OpenAPITest, &logs)
+
+		w := do(r, http.MethodGet, "/items/get?id=i1", "")
+		if w.Code != http.StatusInternalServerError || errorCode(w) != "INTERNAL" || strings.Contains(w.Body.String(), "item_id") {
+			t.Fatalf("expected the response to be replaced by 500, got %d: %s", w.Code, w.Body)
+		}
+		w = do(r, http.MethodGet, "/undocumented", "")
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("expected 500 for an undocumented route, got %d: %s", w.Code, w.Body)
+		}
+		w = do(r, http.MethodGet, "/items/get", "")
+		if w.Code != http.StatusInternalServerError || !strings.Contains(w.Body.String(), "400 is not documented") {
+			t.Fatalf("expected an undocumented 400 to fail, got %d: %s", w.Code, w.Body)
+		}
+		if w := do(r, http.MethodGet, "/unknown", ""); w.Code != http.StatusNotFound {
+			t.Fatalf("expected 404 for an unknown route, got %d", w.Code)
+		}
+		if w := do(r, http.MethodPost, "/items/add", `{"id":"i1","active":true}`); w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", w.Code, w.Body)
+		}
+	})
+}
@@ -0,0 +1,223 @@
+package logger
+
+import (
+	"InternshipTask/internal/domain/auth"
+	"InternshipTask/internal/domain/clock"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit is a token bucket for the routes under Prefix: a client may make
+// Burst requests at once, and the bucket refills at Rate requests per second.
+type RateLimit struct {
+	Prefix string
+	Rate   float64
+	Burst  int
+}
+
+// ParseRateLimit parses "prefix=rate:burst", e.g. "/pullRequest/create=2:10".
+func ParseRateLimit(spec string) (RateLimit, error) {
+	prefix, limit, ok := strings.Cut(spec, "=")
+	if !ok || !strings.HasPrefix(prefix, "/") {
+		return RateLimit{}, fmt.Errorf("rate limit %q: want prefix=rate:burst", spec)
+	}
+
+	l := RateLimit{Prefix: prefix}
+	var err error
+	if l.Rate, l.Burst, err = parseRate(limit); err != nil {
+		return RateLimit{}, fmt.Errorf("rate limit %q: %w", spec, err)
+	}
+	return l, nil
+}
+
+// ParseAuthFailureLimit parses the "rate:burst" of AuthFailureLimitMiddleware,
+// e.g. "1:10".
+func ParseAuthFailureLimit(spec string) (RateLimit, error) {
+	l := RateLimit{Prefix: "/"}
+	var err error
+	if l.Rate, l.Burst, err = parseRate(spec); err != nil {
+		return RateLimit{}, fmt.Errorf("auth failure limit %q: %w", spec, err)
+	}
+	return l, nil
+}
+
+func parseRate(spec string) (float64, int, error) {
+	rate, burst, ok := strings.Cut(spec, ":")
+	if !ok {
+		return 0, 0, errors.New("want rate:burst")
+	}
+	r, err := strconv.ParseFloat(rate, 64)
+	if err != nil || r <= 0 {
+		return 0, 0, errors.New("rate must be a positive number")
+	}
+	b, err := strconv.Atoi(burst)
+	if err != nil || b < 1 {
+		return 0, 0, errors.New("burst must be a positive integer")
+	}
+	return r, b, nil
+}
+
+// RateLimitMiddleware throttles every client separately: by token when the
+// request is authenticated, otherwise by IP. A request is limited by the
+// group with the longest matching prefix, and routes no group matches are
+// not limited. Throttled requests get 429 with Retry-After.
+func RateLimitMiddleware(limits []RateLimit, clk clock.Clock) gin.HandlerFunc {
+	if clk == nil {
+		clk = clock.Real{}
+	}
+
+	limits = slices.Clone(limits)
+	slices.SortFunc(limits, func(a, b RateLimit) int {
+		return len(b.Prefix) - len(a.Prefix)
+	})
+	l := newLimiter(clk)
+
+	return func(c *gin.Context) {
+		i := slices.IndexFunc(limits, func(rl RateLimit) bool {
+			return strings.HasPrefix(c.Request.URL.Path, rl.Prefix)
+		})
+		if i < 0 {
+			c.Next()
+			return
+		}
+		rl := limits[i]
+
+		client := "ip:" + c.ClientIP()
+		if p := auth.FromContext(c.Request.Context()); p != nil {
+			client = "token:" + p.TokenID
+		}
+
+		ok, remaining, wait := l.take(bucketKey{prefix: rl.Prefix, client: client}, rl)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(rl.Burst))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(seconds(float64(rl.Burst-remaining)/rl.Rate)))
+		if !ok {
+			c.Header("Retry-After", strconv.Itoa(seconds(wait)))
+			abortWithError(c, http.StatusTooManyRequests, "RATE_LIMITED", "too many requests, retry later")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AuthFailureLimitMiddleware throttles requests that carry a token by IP, and
+// must run before AuthMiddleware: a request spends a token of its IP's bucket
+// up front and gets it back unless it was answered 401. This way token
+// guessing is throttled before it reaches authentication, while clients with
+// valid tokens are left to RateLimitMiddleware, except that they share the
+// 429s of their IP while its bucket is empty.
+func AuthFailureLimitMiddleware(rl RateLimit, clk clock.Clock) gin.HandlerFunc {
+	if clk == nil {
+		clk = clock.Real{}
+	}
+	l := newLimiter(clk)
+
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+
+		key := bucketKey{prefix: rl.Prefix, client: "ip:" + c.ClientIP()}
+		ok, _, wait := l.take(key, rl)
+		if !ok {
+			c.Header("Retry-After", strconv.Itoa(seconds(wait)))
+			abortWithError(c, http.StatusTooManyRequests, "RATE_LIMITED", "too many failed authentication attempts, retry later")
+			return
+		}
+
+		c.Next()
+		if c.Writer.Status() != http.StatusUnauthorized {
+			l.refund(key, rl)
+		}
+	}
+}
+
+// seconds rounds a positive number of seconds up for the headers.
+func seconds(s float64) int {
+	return int(math.Ceil(s))
+}
+
+type bucketKey struct {
+	prefix string
+	client string
+}
+
+type bucket struct {
+	tokens float64
+	at     time.Time
+	// full is when the bucket will have refilled completely.
+	full time.Time
+}
+
+type limiter struct {
+	mu        sync.Mutex
+	buckets   map[bucketKey]*bucket
+	clock     clock.Clock
+	lastSweep time.Time
+}
+
+func newLimiter(clk clock.Clock) *limiter {
+	return &limiter{buckets: make(map[bucketKey]*bucket), clock: clk}
+}
+
+// take spends a token of the bucket if there is one. It returns whether it
+// did, how many whole tokens are left and, if it did not, how many seconds
+// until the next token.
+func (l *limiter) take(key bucketKey, rl RateLimit) (bool, int, float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(rl.Burst), at: now}
+		l.buckets[key] = b
+	}
+	b.tokens = min(float64(rl.Burst), b.tokens+now.Sub(b.at).Seconds()*rl.Rate)
+	b.at = now
+
+	if b.tokens < 1 {
+		return false, 0, (1 - b.tokens) / rl.Rate
+	}
+	b.tokens--
+	b.full = now.Add(time.Duration((float64(rl.Burst) - b.tokens) / rl.Rate * float64(time.Second)))
+	return true, int(b.tokens), 0
+}
+
+// refund gives back a token spent by take.
+func (l *limiter) refund(key bucketKey, rl RateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b, ok := l.buckets[key]; ok {
+		b.tokens = min(float64(rl.Burst), b.tokens+1)
+	}
+}
+
+// sweep forgets buckets that have refilled, at most once a minute, so that
+// one-off clients do not pile up. A forgotten bucket comes back full anyway.
+func (l *limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if !now.Before(b.full) {
+			delete(l.buckets, key)
+		}
+	}
+}
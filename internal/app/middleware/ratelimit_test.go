@@ -0,0 +1,160 @@
+package logger
+
+import (
+	"InternshipTask/internal/app/dto"
+	"InternshipTask/internal/domain/auth"
+	"InternshipTask/internal/domain/clock"
+	"InternshipTask/internal/infrastructure/memory"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	l, err := ParseRateLimit("/pullRequest/create=0.5:10")
+	if err != nil || l != (RateLimit{Prefix: "/pullRequest/create", Rate: 0.5, Burst: 10}) {
+		t.Fatalf("unexpected limit: %+v, %v", l, err)
+	}
+
+	for _, spec := range []string{"", "/=1", "x=1:1", "/=0:1", "/=1:0", "/=a:1"} {
+		if _, err := ParseRateLimit(spec); err == nil {
+			t.Fatalf("expected an error for %q", spec)
+		}
+	}
+
+	if l, err := ParseAuthFailureLimit("1:10"); err != nil || l != (RateLimit{Prefix: "/", Rate: 1, Burst: 10}) {
+		t.Fatalf("unexpected auth failure limit: %+v, %v", l, err)
+	}
+	if _, err := ParseAuthFailureLimit("/=1:10"); err == nil {
+		t.Fatalf("expected an error for a prefix")
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if id := c.GetHeader("X-Token"); id != "" {
+			c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), &auth.Principal{TokenID: id}))
+		}
+	})
+	r.Use(RateLimitMiddleware([]RateLimit{
+		{Prefix: "/", Rate: 10, Burst: 10},
+		{Prefix: "/pullRequest/create", Rate: 0.5, Burst: 2},
+	}, clock.Func(func() time.Time { return now })))
+	r.POST("/pullRequest/create", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	r.GET("/team/get", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	do := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		if token != "" {
+			req.Header.Set("X-Token", token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := range 2 {
+		w := do(http.MethodPost, "/pullRequest/create", "t1")
+		if w.Code != http.StatusCreated {
+			t.Fatalf("request %d: expected 201, got %d", i, w.Code)
+		}
+		if got := w.Header().Get("X-RateLimit-Remaining"); got != []string{"1", "0"}[i] {
+			t.Fatalf("request %d: unexpected remaining %q", i, got)
+		}
+	}
+
+	w := do(http.MethodPost, "/pullRequest/create", "t1")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") != "2" || w.Header().Get("X-RateLimit-Limit") != "2" || w.Header().Get("X-RateLimit-Reset") != "4" {
+		t.Fatalf("unexpected headers: %v", w.Header())
+	}
+	var resp dto.ErrorDTO
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Error.Code != "RATE_LIMITED" {
+		t.Fatalf("expected a RATE_LIMITED error, got %s, %v", w.Body.String(), err)
+	}
+
+	if w := do(http.MethodGet, "/team/get", "t1"); w.Code != http.StatusOK {
+		t.Fatalf("expected other route groups to have their own bucket, got %d", w.Code)
+	}
+	if w := do(http.MethodPost, "/pullRequest/create", "t2"); w.Code != http.StatusCreated {
+		t.Fatalf("expected other tokens to have their own bucket, got %d", w.Code)
+	}
+	if w := do(http.MethodPost, "/pullRequest/create", ""); w.Code != http.StatusCreated {
+		t.Fatalf("expected anonymous clients to be limited by IP, got %d", w.Code)
+	}
+
+	now = now.Add(2 * time.Second)
+	if w := do(http.MethodPost, "/pullRequest/create", "t1"); w.Code != http.StatusCreated {
+		t.Fatalf("expected the bucket to refill, got %d", w.Code)
+	}
+}
+
+func TestAuthFailureLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	svc := auth.NewService(memory.NewTokenStorage(memory.NewDB()), nil)
+	if err := svc.EnsureToken(ctx, auth.IssueParams{UserID: "admin", Role: auth.RoleAdmin, Secret: "good"}); err != nil {
+		t.Fatalf("EnsureToken() error = %v", err)
+	}
+
+	now := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+	r := gin.New()
+	r.Use(
+		AuthFailureLimitMiddleware(RateLimit{Prefix: "/", Rate: 1, Burst: 3}, clock.Func(func() time.Time { return now })),
+		AuthMiddleware(svc),
+	)
+	r.GET("/team/get", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	do := func(ip, secret string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/team/get", nil)
+		req.RemoteAddr = ip + ":1234"
+		if secret != "" {
+			req.Header.Set("Authorization", "Bearer "+secret)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := range 3 {
+		if w := do("10.0.0.1", "guess"); w.Code != http.StatusUnauthorized {
+			t.Fatalf("request %d: expected 401, got %d", i, w.Code)
+		}
+	}
+	for range 10 {
+		w := do("10.0.0.1", "guess")
+		if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "1" {
+			t.Fatalf("expected bad tokens to be throttled, got %d %v", w.Code, w.Header())
+		}
+	}
+
+	for i := range 10 {
+		if w := do("10.0.0.2", "good"); w.Code != http.StatusOK {
+			t.Fatalf("request %d: expected valid tokens not to use up the bucket, got %d", i, w.Code)
+		}
+	}
+	if w := do("10.0.0.2", ""); w.Code != http.StatusOK {
+		t.Fatalf("expected requests without a token to pass, got %d", w.Code)
+	}
+	if w := do("10.0.0.2", "guess"); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected other IPs to have their own bucket, got %d", w.Code)
+	}
+
+	now = now.Add(time.Second)
+	if w := do("10.0.0.1", "guess"); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected the bucket to refill, got %d", w.Code)
+	}
+}
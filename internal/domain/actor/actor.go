@@ -0,0 +1,16 @@
+package actor
+
+import "context"
+
+type ctxKey struct{}
+
+// WithID returns a ctx that carries the id of whoever initiated the request.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the actor id stored by WithID or "" when it is unknown.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
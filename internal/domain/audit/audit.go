@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type EntityType string
+
+const (
+	EntityTeam        EntityType = "team"
+	EntityUser        EntityType = "user"
+	EntityPullRequest EntityType = "pull_request"
+	EntityToken       EntityType = "token"
+)
+
+type Action string
+
+const (
+	ActionTeamCreate        Action = "team.create"
+	ActionTeamAddMember     Action = "team.add_member"
+	ActionTeamRemoveMember  Action = "team.remove_member"
+	ActionUserSetIsActive   Action = "user.set_is_active"
+	ActionUserSetSkills     Action = "user.set_skills"
+	ActionUserSetSchedule   Action = "user.set_schedule"
+	ActionUserDepart        Action = "user.depart"
+	ActionPRCreate          Action = "pull_request.create"
+	ActionPRMerge           Action = "pull_request.merge"
+	ActionPRReassign        Action = "pull_request.reassign"
+	ActionPRReleaseReviewer Action = "pull_request.release_reviewer"
+	ActionPRTransfer        Action = "pull_request.transfer_authorship"
+	ActionPRVerdict         Action = "pull_request.verdict"
+	ActionTokenIssue        Action = "token.issue"
+	ActionTokenRevoke       Action = "token.revoke"
+)
+
+// Entry is one mutation of an entity. Before is JSON null when the entity was
+// created by the mutation; Actor is empty when it is unknown.
+type Entry struct {
+	ID         int64
+	At         time.Time
+	Actor      string
+	Action     Action
+	EntityType EntityType
+	EntityID   string
+	Before     json.RawMessage
+	After      json.RawMessage
+}
+
+// Filter narrows List down; zero fields do not filter. From is inclusive and
+// To is exclusive.
+type Filter struct {
+	EntityType EntityType
+	EntityID   string
+	Actor      string
+	From       time.Time
+	To         time.Time
+	Limit      int
+}
@@ -0,0 +1,96 @@
+package audit
+
+import (
+	"InternshipTask/internal/domain/actor"
+	"InternshipTask/internal/domain/clock"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+type Storager interface {
+	Append(ctx context.Context, e *Entry) error
+	// List returns matching entries, newest first.
+	List(ctx context.Context, f Filter) ([]Entry, error)
+}
+
+// Recorder is what the domain services write the audit log through. Record
+// must be called with the ctx of the mutation's unit of work, so the entry is
+// stored or rolled back together with the change.
+type Recorder interface {
+	Record(ctx context.Context, action Action, entityType EntityType, entityID string, before, after any) error
+}
+
+// Nop discards all entries.
+type Nop struct{}
+
+func (Nop) Record(context.Context, Action, EntityType, string, any, any) error {
+	return nil
+}
+
+type Service struct {
+	storage Storager
+	clock   clock.Clock
+}
+
+type Option func(*Service)
+
+func WithClock(c clock.Clock) Option {
+	return func(s *Service) {
+		s.clock = c
+	}
+}
+
+func NewService(storage Storager, opts ...Option) *Service {
+	s := &Service{
+		storage: storage,
+		clock:   clock.Real{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+var _ Recorder = (*Service)(nil)
+
+// Record stores before and after as JSON, attributed to the actor in ctx.
+func (s *Service) Record(ctx context.Context, action Action, entityType EntityType, entityID string, before, after any) error {
+	beforeJSON, err := json.Marshal(before)
+	if err != nil {
+		return fmt.Errorf("marshal audit before: %w", err)
+	}
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		return fmt.Errorf("marshal audit after: %w", err)
+	}
+
+	e := &Entry{
+		At:         s.clock.Now(),
+		Actor:      actor.FromContext(ctx),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     beforeJSON,
+		After:      afterJSON,
+	}
+	if err := s.storage.Append(ctx, e); err != nil {
+		return fmt.Errorf("append audit entry: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) List(ctx context.Context, f Filter) ([]Entry, error) {
+	if f.Limit <= 0 {
+		f.Limit = DefaultLimit
+	}
+	f.Limit = min(f.Limit, MaxLimit)
+
+	return s.storage.List(ctx, f)
+}
@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"InternshipTask/internal/domain/actor"
+	"InternshipTask/internal/domain/clock"
+	"context"
+	"testing"
+	"time"
+)
+
+type stubStorage struct {
+	entries []Entry
+	filter  Filter
+}
+
+func (s *stubStorage) Append(_ context.Context, e *Entry) error {
+	e.ID = int64(len(s.entries) + 1)
+	s.entries = append(s.entries, *e)
+	return nil
+}
+
+func (s *stubStorage) List(_ context.Context, f Filter) ([]Entry, error) {
+	s.filter = f
+	return s.entries, nil
+}
+
+func TestService_RecordUsesActorAndClock(t *testing.T) {
+	now := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+	storage := &stubStorage{}
+	svc := NewService(storage, WithClock(clock.Fixed(now)))
+
+	ctx := actor.WithID(context.Background(), "lead")
+	before := map[string]bool{"is_active": true}
+	if err := svc.Record(ctx, ActionUserSetIsActive, EntityUser, "u1", before, map[string]bool{"is_active": false}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := svc.Record(context.Background(), ActionPRCreate, EntityPullRequest, "pr-1", nil, map[string]string{"id": "pr-1"}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	if len(storage.entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(storage.entries))
+	}
+	e := storage.entries[0]
+	if e.Actor != "lead" || !e.At.Equal(now) || e.EntityType != EntityUser || e.EntityID != "u1" {
+		t.Fatalf("unexpected entry: %+v", e)
+	}
+	if string(e.Before) != `{"is_active":true}` || string(e.After) != `{"is_active":false}` {
+		t.Fatalf("unexpected states: %s -> %s", e.Before, e.After)
+	}
+	if created := storage.entries[1]; created.Actor != "" || string(created.Before) != "null" {
+		t.Fatalf("expected an anonymous entry with a null before state, got %+v", created)
+	}
+}
+
+func TestService_ListClampsLimit(t *testing.T) {
+	storage := &stubStorage{}
+	svc := NewService(storage)
+
+	for limit, want := range map[int]int{0: DefaultLimit, 5: 5, MaxLimit + 1: MaxLimit} {
+		if _, err := svc.List(context.Background(), Filter{Limit: limit}); err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if storage.filter.Limit != want {
+			t.Fatalf("limit %d: expected %d, got %d", limit, want, storage.filter.Limit)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Package auth authenticates API callers by bearer tokens and decides what
+// their role allows them to do.
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrUnauthenticated = errors.New("missing, invalid or expired token")
+	ErrForbidden       = errors.New("not allowed")
+	ErrTokenNotFound   = errors.New("token not found")
+	ErrInvalidToken    = errors.New("invalid token parameters")
+)
+
+type Role string
+
+const (
+	RoleAdmin    Role = "admin"
+	RoleTeamLead Role = "team-lead"
+	RoleMember   Role = "member"
+)
+
+func (r Role) Valid() bool {
+	return r == RoleAdmin || grants[r] != nil
+}
+
+// Permission is what a route needs. Whether a role has it may further depend
+// on the resource, see scope.
+type Permission string
+
+const (
+	PermRead         Permission = "read"
+	PermManageTeam   Permission = "team.manage"
+	PermCreateTeam   Permission = "team.create"
+	PermManageUser   Permission = "user.manage"
+	PermManagePR     Permission = "pull_request.manage"
+	PermReassign     Permission = "pull_request.reassign"
+	PermVerdict      Permission = "pull_request.verdict"
+	PermManageTokens Permission = "token.manage"
+)
+
+// scope says on which resources a granted permission holds.
+type scope uint8
+
+const (
+	// scopeAny holds for every resource.
+	scopeAny scope = 1 << iota
+	// scopeSelf holds for resources of the caller's own user.
+	scopeSelf
+	// scopeTeam holds for resources of the teams the caller is a member of.
+	scopeTeam
+)
+
+// grants lists the permissions of every role but admin, which has them all.
+// What no role is granted is admin-only: PermManageTokens, and PermCreateTeam
+// because creating a team upserts its members, whatever teams they are in.
+var grants = map[Role]map[Permission]scope{
+	RoleTeamLead: {
+		PermRead:       scopeAny,
+		PermManageTeam: scopeTeam,
+		PermManageUser: scopeTeam,
+		PermManagePR:   scopeTeam,
+		PermReassign:   scopeTeam | scopeSelf,
+		PermVerdict:    scopeSelf,
+	},
+	RoleMember: {
+		PermRead:     scopeAny,
+		PermReassign: scopeSelf,
+		PermVerdict:  scopeSelf,
+	},
+}
+
+// Allows reports whether r has perm for at least some resources.
+func (r Role) Allows(perm Permission) bool {
+	if r == RoleAdmin {
+		return true
+	}
+	_, ok := grants[r][perm]
+	return ok
+}
+
+// Token is an API token. Only the SHA-256 of its secret is stored, which is
+// enough for random secrets of this length.
+type Token struct {
+	ID          string     `json:"token_id"`
+	UserID      string     `json:"user_id"`
+	Role        Role       `json:"role"`
+	Description string     `json:"description"`
+	Hash        string     `json:"-"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+}
+
+func (t *Token) Active(now time.Time) bool {
+	return t.RevokedAt == nil && (t.ExpiresAt == nil || now.Before(*t.ExpiresAt))
+}
+
+type Storager interface {
+	Create(ctx context.Context, t *Token) error
+	// GetByHash fails with ErrTokenNotFound for an unknown hash.
+	GetByHash(ctx context.Context, hash string) (*Token, error)
+	// List returns all tokens, oldest first.
+	List(ctx context.Context) ([]Token, error)
+	// Revoke returns the revoked token and fails with ErrTokenNotFound for
+	// an unknown id. Revoking a revoked token keeps its first RevokedAt.
+	Revoke(ctx context.Context, id string, at time.Time) (*Token, error)
+}
+
+// Principal is the authenticated caller.
+type Principal struct {
+	TokenID string
+	UserID  string
+	Role    Role
+}
+
+// Resource is what a request acts on: the user it belongs to and its team.
+// With an empty Team the resource belongs to all teams of UserID.
+type Resource struct {
+	UserID string
+	Team   string
+}
+
+type ctxKey struct{}
+
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, ctxKey{}, p)
+}
+
+// FromContext returns the principal stored by WithPrincipal or nil.
+func FromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(ctxKey{}).(*Principal)
+	return p
+}
@@ -0,0 +1,243 @@
+package auth
+
+import (
+	"InternshipTask/internal/domain/audit"
+	"InternshipTask/internal/domain/clock"
+	"InternshipTask/internal/domain/txn"
+	"InternshipTask/internal/domain/user"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// secretPrefix makes tokens easy to recognize, e.g. by secret scanners.
+const secretPrefix = "prs_"
+
+// TeamLookup tells which teams a user is a member of.
+type TeamLookup interface {
+	GetTeamNamesByUserID(ctx context.Context, userID string) ([]string, error)
+}
+
+type Service struct {
+	storage Storager
+	teams   TeamLookup
+	clock   clock.Clock
+	tx      txn.Manager
+	audit   audit.Recorder
+}
+
+type Option func(*Service)
+
+func WithClock(c clock.Clock) Option {
+	return func(s *Service) {
+		s.clock = c
+	}
+}
+
+func WithTxManager(m txn.Manager) Option {
+	return func(s *Service) {
+		s.tx = m
+	}
+}
+
+// WithAuditLog records every issued and revoked token in r.
+func WithAuditLog(r audit.Recorder) Option {
+	return func(s *Service) {
+		s.audit = r
+	}
+}
+
+func NewService(storage Storager, teams TeamLookup, opts ...Option) *Service {
+	s := &Service{
+		storage: storage,
+		teams:   teams,
+		clock:   clock.Real{},
+		tx:      txn.Nop{},
+		audit:   audit.Nop{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+type IssueParams struct {
+	UserID      string
+	Role        Role
+	Description string
+	// TTL limits the lifetime of the token; zero means it never expires.
+	TTL time.Duration
+	// Secret is used instead of a random one, e.g. for the bootstrap token.
+	Secret string
+}
+
+// Issue creates a token and returns it with its secret, which is not stored
+// and cannot be recovered later.
+func (s *Service) Issue(ctx context.Context, p IssueParams) (*Token, string, error) {
+	if p.UserID == "" {
+		return nil, "", fmt.Errorf("%w: user_id is required", ErrInvalidToken)
+	}
+	if !p.Role.Valid() {
+		return nil, "", fmt.Errorf("%w: unknown role %q", ErrInvalidToken, p.Role)
+	}
+
+	secret := p.Secret
+	if secret == "" {
+		secret = secretPrefix + randomString(32)
+	}
+
+	now := s.clock.Now()
+	t := &Token{
+		ID:          randomHex(8),
+		UserID:      p.UserID,
+		Role:        p.Role,
+		Description: p.Description,
+		Hash:        hashSecret(secret),
+		CreatedAt:   now,
+	}
+	if p.TTL > 0 {
+		expiresAt := now.Add(p.TTL)
+		t.ExpiresAt = &expiresAt
+	}
+
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		if err := s.storage.Create(ctx, t); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, audit.ActionTokenIssue, audit.EntityToken, t.ID, nil, t)
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("issue token: %w", err)
+	}
+
+	return t, secret, nil
+}
+
+// EnsureToken issues a token with the given secret unless one exists, so
+// that a configured bootstrap token survives restarts.
+func (s *Service) EnsureToken(ctx context.Context, p IssueParams) error {
+	_, err := s.storage.GetByHash(ctx, hashSecret(p.Secret))
+	if !errors.Is(err, ErrTokenNotFound) {
+		return err
+	}
+	_, _, err = s.Issue(ctx, p)
+	return err
+}
+
+// Authenticate returns the principal of an active token with secret.
+func (s *Service) Authenticate(ctx context.Context, secret string) (*Principal, error) {
+	t, err := s.storage.GetByHash(ctx, hashSecret(secret))
+	if errors.Is(err, ErrTokenNotFound) {
+		return nil, ErrUnauthenticated
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get token: %w", err)
+	}
+	if !t.Active(s.clock.Now()) {
+		return nil, ErrUnauthenticated
+	}
+
+	return &Principal{TokenID: t.ID, UserID: t.UserID, Role: t.Role}, nil
+}
+
+func (s *Service) List(ctx context.Context) ([]Token, error) {
+	return s.storage.List(ctx)
+}
+
+// Revoke disables a token for good.
+func (s *Service) Revoke(ctx context.Context, id string) (*Token, error) {
+	var t *Token
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if t, err = s.storage.Revoke(ctx, id, s.clock.Now()); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, audit.ActionTokenRevoke, audit.EntityToken, id, nil, t)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Authorize fails with ErrForbidden unless p may use perm on res.
+func (s *Service) Authorize(ctx context.Context, p *Principal, perm Permission, res Resource) error {
+	if p.Role == RoleAdmin {
+		return nil
+	}
+
+	sc := grants[p.Role][perm]
+	switch {
+	case sc&scopeAny != 0:
+		return nil
+	case sc&scopeSelf != 0 && res.UserID != "" && res.UserID == p.UserID:
+		return nil
+	case sc&scopeTeam != 0:
+		ok, err := s.sharesTeam(ctx, p.UserID, res)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s may not use %s here", ErrForbidden, p.Role, perm)
+}
+
+func (s *Service) sharesTeam(ctx context.Context, userID string, res Resource) (bool, error) {
+	own, err := s.teamsOf(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	if res.Team != "" {
+		return slices.Contains(own, res.Team), nil
+	}
+	if res.UserID == "" {
+		return false, nil
+	}
+
+	theirs, err := s.teamsOf(ctx, res.UserID)
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(theirs, func(team string) bool {
+		return slices.Contains(own, team)
+	}), nil
+}
+
+// teamsOf treats an unknown user as a member of no team.
+func (s *Service) teamsOf(ctx context.Context, userID string) ([]string, error) {
+	teams, err := s.teams.GetTeamNamesByUserID(ctx, userID)
+	if errors.Is(err, user.ErrUserNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get teams of %s: %w", userID, err)
+	}
+	return teams, nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"InternshipTask/internal/domain/clock"
+	"InternshipTask/internal/domain/user"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type stubStorage struct {
+	tokens []Token
+}
+
+func (s *stubStorage) Create(_ context.Context, t *Token) error {
+	s.tokens = append(s.tokens, *t)
+	return nil
+}
+
+func (s *stubStorage) GetByHash(_ context.Context, hash string) (*Token, error) {
+	for _, t := range s.tokens {
+		if t.Hash == hash {
+			return &t, nil
+		}
+	}
+	return nil, ErrTokenNotFound
+}
+
+func (s *stubStorage) List(_ context.Context) ([]Token, error) {
+	return s.tokens, nil
+}
+
+func (s *stubStorage) Revoke(_ context.Context, id string, at time.Time) (*Token, error) {
+	for i := range s.tokens {
+		if s.tokens[i].ID == id {
+			s.tokens[i].RevokedAt = &at
+			return &s.tokens[i], nil
+		}
+	}
+	return nil, ErrTokenNotFound
+}
+
+type stubTeams map[string][]string
+
+func (s stubTeams) GetTeamNamesByUserID(_ context.Context, userID string) ([]string, error) {
+	teams, ok := s[userID]
+	if !ok {
+		return nil, user.ErrUserNotFound
+	}
+	return teams, nil
+}
+
+func TestService_IssueAndAuthenticate(t *testing.T) {
+	now := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+	storage := &stubStorage{}
+	svc := NewService(storage, stubTeams{}, WithClock(clock.Func(func() time.Time { return now })))
+	ctx := context.Background()
+
+	tok, secret, err := svc.Issue(ctx, IssueParams{UserID: "u1", Role: RoleMember, TTL: time.Hour})
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	if !strings.HasPrefix(secret, secretPrefix) || strings.Contains(storage.tokens[0].Hash, secret) || storage.tokens[0].Hash == "" {
+		t.Fatalf("expected only a hash of the secret to be stored, got %+v", storage.tokens[0])
+	}
+
+	p, err := svc.Authenticate(ctx, secret)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if p.TokenID != tok.ID || p.UserID != "u1" || p.Role != RoleMember {
+		t.Fatalf("unexpected principal: %+v", p)
+	}
+
+	if _, err := svc.Authenticate(ctx, secret+"x"); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("expected ErrUnauthenticated for a wrong secret, got %v", err)
+	}
+
+	now = now.Add(time.Hour)
+	if _, err := svc.Authenticate(ctx, secret); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("expected ErrUnauthenticated for an expired token, got %v", err)
+	}
+
+	_, secret, err = svc.Issue(ctx, IssueParams{UserID: "u1", Role: RoleTeamLead})
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	if _, err := svc.Revoke(ctx, storage.tokens[1].ID); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, secret); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("expected ErrUnauthenticated for a revoked token, got %v", err)
+	}
+
+	if _, _, err := svc.Issue(ctx, IssueParams{UserID: "u1", Role: "owner"}); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken for an unknown role, got %v", err)
+	}
+}
+
+func TestService_EnsureTokenIsIdempotent(t *testing.T) {
+	storage := &stubStorage{}
+	svc := NewService(storage, stubTeams{})
+	params := IssueParams{UserID: "admin", Role: RoleAdmin, Secret: "bootstrap"}
+
+	for range 2 {
+		if err := svc.EnsureToken(context.Background(), params); err != nil {
+			t.Fatalf("EnsureToken() error = %v", err)
+		}
+	}
+	if len(storage.tokens) != 1 {
+		t.Fatalf("expected one token, got %d", len(storage.tokens))
+	}
+	if _, err := svc.Authenticate(context.Background(), "bootstrap"); err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+}
+
+func TestService_Authorize(t *testing.T) {
+	svc := NewService(&stubStorage{}, stubTeams{
+		"lead":  {"backend"},
+		"u1":    {"backend"},
+		"u2":    {"frontend"},
+		"multi": {"frontend", "backend"},
+	})
+
+	admin := &Principal{UserID: "root", Role: RoleAdmin}
+	lead := &Principal{UserID: "lead", Role: RoleTeamLead}
+	member := &Principal{UserID: "u1", Role: RoleMember}
+
+	tests := []struct {
+		name string
+		p    *Principal
+		perm Permission
+		res  Resource
+		ok   bool
+	}{
+		{"admin may do anything", admin, PermManageTokens, Resource{}, true},
+		{"member reads", member, PermRead, Resource{Team: "frontend"}, true},
+		{"member reassigns self", member, PermReassign, Resource{UserID: "u1", Team: "backend"}, true},
+		{"member cannot reassign others", member, PermReassign, Resource{UserID: "u2", Team: "backend"}, false},
+		{"member submits own verdict", member, PermVerdict, Resource{UserID: "u1"}, true},
+		{"member cannot manage users", member, PermManageUser, Resource{UserID: "u1"}, false},
+		{"lead manages own team", lead, PermManageTeam, Resource{Team: "backend"}, true},
+		{"lead cannot manage other teams", lead, PermManageTeam, Resource{Team: "frontend"}, false},
+		{"lead cannot create teams", lead, PermCreateTeam, Resource{Team: "payments"}, false},
+		{"admin creates teams", admin, PermCreateTeam, Resource{Team: "payments"}, true},
+		{"lead manages users of own team", lead, PermManageUser, Resource{UserID: "u1"}, true},
+		{"lead manages users sharing a team", lead, PermManageUser, Resource{UserID: "multi"}, true},
+		{"lead cannot manage other users", lead, PermManageUser, Resource{UserID: "u2"}, false},
+		{"lead cannot manage unknown users", lead, PermManageUser, Resource{UserID: "ghost"}, false},
+		{"lead reassigns in own team", lead, PermReassign, Resource{UserID: "u2", Team: "backend"}, true},
+		{"lead cannot reassign in other teams", lead, PermReassign, Resource{UserID: "u2", Team: "frontend"}, false},
+		{"lead cannot submit others' verdicts", lead, PermVerdict, Resource{UserID: "u1"}, false},
+		{"lead cannot manage tokens", lead, PermManageTokens, Resource{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.Authorize(context.Background(), tt.p, tt.perm, tt.res)
+			if tt.ok && err != nil {
+				t.Fatalf("expected access, got %v", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrForbidden) {
+				t.Fatalf("expected ErrForbidden, got %v", err)
+			}
+		})
+	}
+}
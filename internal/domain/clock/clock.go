@@ -0,0 +1,24 @@
+package clock
+
+import "time"
+
+type Clock interface {
+	Now() time.Time
+}
+
+type Real struct{}
+
+func (Real) Now() time.Time {
+	return time.Now().UTC()
+}
+
+// Func adapts a plain function to Clock, which is handy for fixed clocks in tests.
+type Func func() time.Time
+
+func (f Func) Now() time.Time {
+	return f()
+}
+
+func Fixed(t time.Time) Func {
+	return func() time.Time { return t }
+}
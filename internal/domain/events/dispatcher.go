@@ -0,0 +1,174 @@
+package events
+
+import (
+	"InternshipTask/internal/domain/clock"
+	"InternshipTask/internal/domain/logging"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const (
+	DefaultPollInterval = time.Second
+	DefaultBatchSize    = 100
+	DefaultBaseBackoff  = time.Second
+	DefaultMaxBackoff   = 5 * time.Minute
+	// DefaultMaxAttempts gives an event about an hour and a half of retries
+	// with the default backoff.
+	DefaultMaxAttempts = 25
+)
+
+// Sink receives delivered events. Delivery is at-least-once, so a sink can see
+// the same event ID more than once and should deduplicate by it.
+type Sink interface {
+	Deliver(ctx context.Context, e Event) error
+}
+
+// Dispatcher polls the outbox and hands pending events to every sink. An
+// event counts as delivered only when all sinks accepted it; otherwise it is
+// retried with exponential backoff and later events of the same aggregate wait
+// behind it. After maxAttempts failures the event is parked, and the events
+// behind it go on without it.
+//
+// Ordering relies on a single dispatcher per database.
+type Dispatcher struct {
+	outbox       Outbox
+	sinks        []Sink
+	clock        clock.Clock
+	pollInterval time.Duration
+	batchSize    int
+	baseBackoff  time.Duration
+	maxBackoff   time.Duration
+	maxAttempts  int
+}
+
+type DispatcherOption func(*Dispatcher)
+
+func WithDispatcherClock(c clock.Clock) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.clock = c
+	}
+}
+
+func WithPollInterval(interval time.Duration) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.pollInterval = interval
+	}
+}
+
+func WithBatchSize(n int) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.batchSize = n
+	}
+}
+
+func WithBackoff(base, max time.Duration) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.baseBackoff = base
+		d.maxBackoff = max
+	}
+}
+
+// WithMaxAttempts sets how many failed deliveries park an event. Zero retries
+// forever.
+func WithMaxAttempts(n int) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.maxAttempts = n
+	}
+}
+
+func NewDispatcher(outbox Outbox, sinks []Sink, opts ...DispatcherOption) *Dispatcher {
+	d := &Dispatcher{
+		outbox:       outbox,
+		sinks:        sinks,
+		clock:        clock.Real{},
+		pollInterval: DefaultPollInterval,
+		batchSize:    DefaultBatchSize,
+		baseBackoff:  DefaultBaseBackoff,
+		maxBackoff:   DefaultMaxBackoff,
+		maxAttempts:  DefaultMaxAttempts,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Run dispatches until ctx is cancelled. A pass that delivered something is
+// followed by the next one right away, since each pass takes only one event
+// per aggregate.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		n, err := d.DispatchOnce(ctx)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			logging.FromContext(ctx).ErrorContext(ctx, "outbox dispatch", slog.Any("err", err))
+		}
+		if n > 0 && err == nil && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchOnce tries the oldest due event of every aggregate, up to batchSize
+// of them, and returns how many were delivered.
+func (d *Dispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	now := d.clock.Now()
+	pending, err := d.outbox.Pending(ctx, now, d.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("load pending events: %w", err)
+	}
+
+	delivered := 0
+	for _, e := range pending {
+		if err := d.deliver(ctx, e); err != nil {
+			e.Attempts++
+			e.LastError = err.Error()
+			e.NextAttemptAt = now.Add(d.backoff(e.Attempts))
+			if d.maxAttempts > 0 && e.Attempts >= d.maxAttempts {
+				e.ParkedAt = &now
+				logging.FromContext(ctx).WarnContext(ctx, "outbox event parked",
+					slog.Int64("event_id", e.ID), slog.String("event_type", string(e.Type)),
+					slog.Int("attempts", e.Attempts), slog.String("err", e.LastError))
+			}
+			if err := d.outbox.MarkFailed(ctx, e); err != nil {
+				return delivered, fmt.Errorf("mark event %d failed: %w", e.ID, err)
+			}
+			continue
+		}
+
+		if err := d.outbox.MarkDelivered(ctx, e.ID); err != nil {
+			return delivered, fmt.Errorf("mark event %d delivered: %w", e.ID, err)
+		}
+		delivered++
+	}
+
+	return delivered, nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, e Event) error {
+	for _, sink := range d.sinks {
+		if err := sink.Deliver(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	backoff := d.baseBackoff
+	for i := 1; i < attempts && backoff < d.maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, d.maxBackoff)
+}
@@ -0,0 +1,215 @@
+package events
+
+import (
+	"InternshipTask/internal/domain/clock"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+type stubOutbox struct {
+	events []Event
+}
+
+func (o *stubOutbox) Append(_ context.Context, e *Event) error {
+	e.ID = int64(len(o.events) + 1)
+	o.events = append(o.events, *e)
+	return nil
+}
+
+func (o *stubOutbox) Pending(_ context.Context, now time.Time, limit int) ([]Event, error) {
+	var result []Event
+	heads := make(map[string]bool)
+	for _, e := range o.events {
+		key := e.AggregateType + "/" + e.AggregateID
+		if e.ParkedAt != nil || heads[key] {
+			continue
+		}
+		heads[key] = true
+		if !e.NextAttemptAt.After(now) && len(result) < limit {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+func (o *stubOutbox) MarkDelivered(_ context.Context, id int64) error {
+	o.events = slices.DeleteFunc(o.events, func(e Event) bool { return e.ID == id })
+	return nil
+}
+
+func (o *stubOutbox) MarkFailed(_ context.Context, e Event) error {
+	for i := range o.events {
+		if o.events[i].ID == e.ID {
+			o.events[i] = e
+		}
+	}
+	return nil
+}
+
+type stubSink struct {
+	delivered []int64
+	failIDs   map[int64]bool
+}
+
+func (s *stubSink) Deliver(_ context.Context, e Event) error {
+	if s.failIDs[e.ID] {
+		return errors.New("sink unavailable")
+	}
+	s.delivered = append(s.delivered, e.ID)
+	return nil
+}
+
+func TestPublisher_AppendsMarshaledEvent(t *testing.T) {
+	now := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+	outbox := &stubOutbox{}
+	p := NewPublisher(outbox, clock.Fixed(now))
+
+	payload := ReviewerAssignedPayload{PullRequestID: "pr-1", ReviewerID: "u2", Reason: "CREATED"}
+	if err := p.Publish(context.Background(), PullRequestReviewerAssigned, AggregatePullRequest, "pr-1", payload); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	if len(outbox.events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(outbox.events))
+	}
+	e := outbox.events[0]
+	if e.Type != PullRequestReviewerAssigned || e.AggregateType != AggregatePullRequest || e.AggregateID != "pr-1" ||
+		!e.OccurredAt.Equal(now) || !e.NextAttemptAt.Equal(now) {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+	var got ReviewerAssignedPayload
+	if err := json.Unmarshal(e.Payload, &got); err != nil || got != payload {
+		t.Fatalf("expected payload %+v, got %s (%v)", payload, e.Payload, err)
+	}
+}
+
+func TestDispatcher_FailureHoldsBackLaterEventsOfSameAggregate(t *testing.T) {
+	now := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+	outbox := &stubOutbox{}
+	p := NewPublisher(outbox, clock.Fixed(now))
+	ctx := context.Background()
+	for _, agg := range []struct{ typ, id string }{
+		{AggregatePullRequest, "pr-1"},
+		{AggregateUser, "u1"},
+		{AggregatePullRequest, "pr-1"},
+	} {
+		if err := p.Publish(ctx, PullRequestMerged, agg.typ, agg.id, struct{}{}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	sink := &stubSink{failIDs: map[int64]bool{1: true}}
+	d := NewDispatcher(outbox, []Sink{sink},
+		WithDispatcherClock(clock.Func(func() time.Time { return now })),
+		WithBackoff(time.Second, time.Minute),
+	)
+
+	if n, err := d.DispatchOnce(ctx); err != nil || n != 1 {
+		t.Fatalf("DispatchOnce() = %d, %v; want 1 delivered", n, err)
+	}
+	if !slices.Equal(sink.delivered, []int64{2}) {
+		t.Fatalf("expected only the other aggregate to be delivered, got %v", sink.delivered)
+	}
+	failed := outbox.events[0]
+	if failed.Attempts != 1 || failed.LastError != "sink unavailable" || !failed.NextAttemptAt.Equal(now.Add(time.Second)) {
+		t.Fatalf("expected the failure to be recorded with backoff, got %+v", failed)
+	}
+
+	delete(sink.failIDs, 1)
+	if n, err := d.DispatchOnce(ctx); err != nil || n != 0 {
+		t.Fatalf("expected nothing before the backoff elapses, got %d, %v", n, err)
+	}
+
+	now = now.Add(time.Second)
+	for range 2 {
+		if n, err := d.DispatchOnce(ctx); err != nil || n != 1 {
+			t.Fatalf("DispatchOnce() = %d, %v; want 1 delivered", n, err)
+		}
+	}
+	if !slices.Equal(sink.delivered, []int64{2, 1, 3}) {
+		t.Fatalf("expected pr-1 events in order after the retry, got %v", sink.delivered)
+	}
+	if len(outbox.events) != 0 {
+		t.Fatalf("expected an empty outbox, got %+v", outbox.events)
+	}
+}
+
+func TestDispatcher_FailingAggregateDoesNotStarveOthers(t *testing.T) {
+	now := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+	outbox := &stubOutbox{}
+	p := NewPublisher(outbox, clock.Fixed(now))
+	ctx := context.Background()
+	for _, id := range []string{"pr-1", "pr-1", "pr-1", "pr-2"} {
+		if err := p.Publish(ctx, PullRequestMerged, AggregatePullRequest, id, struct{}{}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	sink := &stubSink{failIDs: map[int64]bool{1: true, 2: true, 3: true}}
+	d := NewDispatcher(outbox, []Sink{sink},
+		WithDispatcherClock(clock.Func(func() time.Time { return now })),
+		WithBatchSize(3),
+	)
+
+	for range 2 {
+		if _, err := d.DispatchOnce(ctx); err != nil {
+			t.Fatalf("DispatchOnce() error = %v", err)
+		}
+		now = now.Add(time.Hour)
+	}
+	if !slices.Equal(sink.delivered, []int64{4}) {
+		t.Fatalf("expected pr-2 to be delivered past a full batch of pr-1 events, got %v", sink.delivered)
+	}
+	if failed := outbox.events[0]; failed.ID != 1 || failed.Attempts != 2 {
+		t.Fatalf("expected only the head of pr-1 to be tried, got %+v", outbox.events)
+	}
+}
+
+func TestDispatcher_ParksEventAfterMaxAttempts(t *testing.T) {
+	now := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+	outbox := &stubOutbox{}
+	p := NewPublisher(outbox, clock.Fixed(now))
+	ctx := context.Background()
+	for range 2 {
+		if err := p.Publish(ctx, PullRequestMerged, AggregatePullRequest, "pr-1", struct{}{}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	sink := &stubSink{failIDs: map[int64]bool{1: true}}
+	d := NewDispatcher(outbox, []Sink{sink},
+		WithDispatcherClock(clock.Func(func() time.Time { return now })),
+		WithMaxAttempts(2),
+	)
+
+	for _, want := range []int{0, 0, 1} {
+		if n, err := d.DispatchOnce(ctx); err != nil || n != want {
+			t.Fatalf("DispatchOnce() = %d, %v; want %d delivered", n, err, want)
+		}
+		now = now.Add(time.Hour)
+	}
+	if !slices.Equal(sink.delivered, []int64{2}) {
+		t.Fatalf("expected the next event to go past the parked one, got %v", sink.delivered)
+	}
+	if len(outbox.events) != 1 {
+		t.Fatalf("expected the parked event to stay in the outbox, got %+v", outbox.events)
+	}
+	if parked := outbox.events[0]; parked.ID != 1 || parked.Attempts != 2 || parked.ParkedAt == nil || parked.LastError != "sink unavailable" {
+		t.Fatalf("expected event 1 to be parked after 2 attempts, got %+v", parked)
+	}
+}
+
+func TestDispatcher_BackoffDoublesUpToMax(t *testing.T) {
+	d := NewDispatcher(&stubOutbox{}, nil, WithBackoff(time.Second, 10*time.Second))
+
+	want := map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 4: 8 * time.Second, 5: 10 * time.Second, 50: 10 * time.Second}
+	for attempts, backoff := range want {
+		if got := d.backoff(attempts); got != backoff {
+			t.Fatalf("backoff(%d) = %s, want %s", attempts, got, backoff)
+		}
+	}
+}
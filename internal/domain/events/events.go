@@ -0,0 +1,58 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Type string
+
+const (
+	PullRequestCreated          Type = "pull_request.created"
+	PullRequestReviewerAssigned Type = "pull_request.reviewer_assigned"
+	PullRequestReassigned       Type = "pull_request.reassigned"
+	PullRequestMerged           Type = "pull_request.merged"
+	UserActivityChanged         Type = "user.activity_changed"
+)
+
+const (
+	AggregatePullRequest = "pull_request"
+	AggregateUser        = "user"
+)
+
+// Event is a domain event waiting in the outbox. Events of one aggregate are
+// delivered in ID order, skipping parked ones.
+type Event struct {
+	ID            int64
+	Type          Type
+	AggregateType string
+	AggregateID   string
+	Payload       json.RawMessage
+	OccurredAt    time.Time
+	// Attempts counts failed deliveries; the next one is not tried before
+	// NextAttemptAt.
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	// ParkedAt is set once the event ran out of attempts. A parked event is
+	// no longer delivered and stays in the outbox for an operator to inspect.
+	ParkedAt *time.Time
+}
+
+type ReviewerAssignedPayload struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReviewerID    string `json:"reviewer_id"`
+	Reason        string `json:"reason"`
+}
+
+type ReassignedPayload struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldReviewerID string `json:"old_reviewer_id"`
+	NewReviewerID string `json:"new_reviewer_id"`
+}
+
+type ActivityChangedPayload struct {
+	UserID   string `json:"user_id"`
+	IsActive bool   `json:"is_active"`
+	Departed bool   `json:"departed"`
+}
@@ -0,0 +1,70 @@
+package events
+
+import (
+	"InternshipTask/internal/domain/clock"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Outbox stores events until the Dispatcher has delivered them.
+type Outbox interface {
+	Append(ctx context.Context, e *Event) error
+	// Pending returns up to limit events, oldest first: for every aggregate
+	// its oldest event that is not parked, if that event is due at now.
+	Pending(ctx context.Context, now time.Time, limit int) ([]Event, error)
+	MarkDelivered(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, e Event) error
+}
+
+// Publisher is what the domain services emit events through. Publish must be
+// called with the ctx of the state change's unit of work, so the event is
+// stored if and only if the change is committed.
+type Publisher interface {
+	Publish(ctx context.Context, eventType Type, aggregateType, aggregateID string, payload any) error
+}
+
+// Nop drops all events.
+type Nop struct{}
+
+func (Nop) Publish(context.Context, Type, string, string, any) error {
+	return nil
+}
+
+// OutboxPublisher writes events to an Outbox.
+type OutboxPublisher struct {
+	outbox Outbox
+	clock  clock.Clock
+}
+
+func NewPublisher(outbox Outbox, c clock.Clock) *OutboxPublisher {
+	if c == nil {
+		c = clock.Real{}
+	}
+	return &OutboxPublisher{outbox: outbox, clock: c}
+}
+
+var _ Publisher = (*OutboxPublisher)(nil)
+
+func (p *OutboxPublisher) Publish(ctx context.Context, eventType Type, aggregateType, aggregateID string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal %s payload: %w", eventType, err)
+	}
+
+	now := p.clock.Now()
+	e := &Event{
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       data,
+		OccurredAt:    now,
+		NextAttemptAt: now,
+	}
+	if err := p.outbox.Append(ctx, e); err != nil {
+		return fmt.Errorf("append %s to outbox: %w", eventType, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+// Package idempotency remembers the response to the first request made with
+// an Idempotency-Key, so that retries of that request get the same response
+// instead of being executed again.
+package idempotency
+
+import (
+	"InternshipTask/internal/domain/clock"
+	"InternshipTask/internal/domain/logging"
+	"context"
+	"log/slog"
+	"time"
+)
+
+const (
+	DefaultTTL            = 24 * time.Hour
+	DefaultExpiryInterval = time.Hour
+)
+
+// Record is what is stored for a key. Status is zero while the first request
+// is still being processed.
+type Record struct {
+	Key string
+	// Fingerprint identifies the request, so that a key reused for a
+	// different request can be told apart from a retry.
+	Fingerprint string
+	Status      int
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+func (r *Record) Pending() bool {
+	return r.Status == 0
+}
+
+type Store interface {
+	// Reserve stores rec as a pending record and returns nil, unless an
+	// unexpired record for rec.Key exists; that record is returned instead
+	// and nothing is stored.
+	Reserve(ctx context.Context, rec Record) (*Record, error)
+	// Complete stores the response of the request that reserved key.
+	Complete(ctx context.Context, key string, status int, body []byte) error
+	// Release forgets key, e.g. after a failure worth retrying.
+	Release(ctx context.Context, key string) error
+	// DeleteExpired removes the records expired at now and returns how many
+	// there were.
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
+}
+
+// RunExpiry deletes expired records every interval until ctx is done.
+func RunExpiry(ctx context.Context, s Store, c clock.Clock, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultExpiryInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.DeleteExpired(ctx, c.Now()); err != nil {
+				logging.FromContext(ctx).ErrorContext(ctx, "delete expired idempotency keys", slog.Any("err", err))
+			}
+		}
+	}
+}
@@ -0,0 +1,23 @@
+// Package logging carries the request's logger in a context, so that what
+// services and storages log can be correlated with the request.
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+type ctxKey struct{}
+
+// WithLogger returns a ctx that carries l.
+func WithLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns the logger stored by WithLogger or slog.Default().
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
@@ -0,0 +1,142 @@
+package offboarding
+
+import (
+	"InternshipTask/internal/domain/pull_request"
+	"InternshipTask/internal/domain/txn"
+	"InternshipTask/internal/domain/user"
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidTransferTarget = errors.New("authorship can only be transferred to another non-departed user")
+)
+
+type UserService interface {
+	GetByID(ctx context.Context, id string) (*user.User, error)
+	MarkDeparted(ctx context.Context, id string) (*user.User, error)
+}
+
+type PullRequestService interface {
+	GetByReviewerID(ctx context.Context, userID string, includeArchived bool) ([]pull_request.PullRequestShort, error)
+	GetOpenByAuthorID(ctx context.Context, authorID string) ([]pull_request.PullRequestShort, error)
+	ReleaseReviewer(ctx context.Context, prID, oldUserID string) (*pull_request.PR, string, error)
+	TransferAuthorship(ctx context.Context, prID, newAuthorID string) (*pull_request.PR, error)
+}
+
+type Reassignment struct {
+	PullRequestID string
+	ReplacedBy    string
+}
+
+type Result struct {
+	User        *user.User
+	Reassigned  []Reassignment
+	Transferred []string
+}
+
+type Service struct {
+	users UserService
+	prs   PullRequestService
+	tx    txn.Manager
+}
+
+type Option func(*Service)
+
+// WithTxManager makes the whole offboarding a single unit of work. The user and
+// PR services must share the same manager so their calls join it.
+func WithTxManager(m txn.Manager) Option {
+	return func(s *Service) {
+		s.tx = m
+	}
+}
+
+func NewService(users UserService, prs PullRequestService, opts ...Option) *Service {
+	s := &Service{
+		users: users,
+		prs:   prs,
+		tx:    txn.Nop{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Offboard marks the user as departed, frees all their open review slots and,
+// when transferTo is set, hands their open PRs over to that colleague. Either
+// all of it happens or nothing does.
+func (s *Service) Offboard(ctx context.Context, userID, transferTo string) (*Result, error) {
+	var result *Result
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = s.offboard(ctx, userID, transferTo)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *Service) offboard(ctx context.Context, userID, transferTo string) (*Result, error) {
+	if transferTo != "" {
+		if transferTo == userID {
+			return nil, ErrInvalidTransferTarget
+		}
+		target, err := s.users.GetByID(ctx, transferTo)
+		if err != nil {
+			return nil, fmt.Errorf("get transfer target: %w", err)
+		}
+		if target.IsDeparted() {
+			return nil, ErrInvalidTransferTarget
+		}
+	}
+
+	u, err := s.users.MarkDeparted(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		User:        u,
+		Reassigned:  make([]Reassignment, 0),
+		Transferred: make([]string, 0),
+	}
+
+	reviews, err := s.prs.GetByReviewerID(ctx, userID, false)
+	if err != nil {
+		return nil, fmt.Errorf("get reviews: %w", err)
+	}
+	for _, pr := range reviews {
+		if pr.Status != pull_request.OPEN {
+			continue
+		}
+		_, replacedBy, err := s.prs.ReleaseReviewer(ctx, pr.PullRequestId, userID)
+		if err != nil {
+			return nil, fmt.Errorf("release review %s: %w", pr.PullRequestId, err)
+		}
+		result.Reassigned = append(result.Reassigned, Reassignment{
+			PullRequestID: pr.PullRequestId,
+			ReplacedBy:    replacedBy,
+		})
+	}
+
+	if transferTo == "" {
+		return result, nil
+	}
+
+	authored, err := s.prs.GetOpenByAuthorID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get authored prs: %w", err)
+	}
+	for _, pr := range authored {
+		if _, err := s.prs.TransferAuthorship(ctx, pr.PullRequestId, transferTo); err != nil {
+			return nil, fmt.Errorf("transfer authorship of %s: %w", pr.PullRequestId, err)
+		}
+		result.Transferred = append(result.Transferred, pr.PullRequestId)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,159 @@
+package offboarding
+
+import (
+	"InternshipTask/internal/domain/pull_request"
+	"InternshipTask/internal/domain/txn"
+	"InternshipTask/internal/domain/user"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type stubUserService struct {
+	users    map[string]*user.User
+	departed []string
+}
+
+func (s *stubUserService) GetByID(_ context.Context, id string) (*user.User, error) {
+	if u, ok := s.users[id]; ok {
+		return u, nil
+	}
+	return nil, user.ErrUserNotFound
+}
+
+func (s *stubUserService) MarkDeparted(ctx context.Context, id string) (*user.User, error) {
+	u, ok := s.users[id]
+	if !ok {
+		return nil, user.ErrUserNotFound
+	}
+	before := *u
+	now := time.Now()
+	u.IsActive = false
+	u.DepartedAt = &now
+	s.departed = append(s.departed, id)
+	txn.OnRollback(ctx, func() {
+		*u = before
+		s.departed = s.departed[:len(s.departed)-1]
+	})
+	return u, nil
+}
+
+type stubPRService struct {
+	reviews     []pull_request.PullRequestShort
+	authored    []pull_request.PullRequestShort
+	replacement map[string]string
+	released    []string
+	transferred map[string]string
+	transferErr error
+}
+
+func (s *stubPRService) GetByReviewerID(_ context.Context, _ string, _ bool) ([]pull_request.PullRequestShort, error) {
+	return s.reviews, nil
+}
+
+func (s *stubPRService) GetOpenByAuthorID(_ context.Context, _ string) ([]pull_request.PullRequestShort, error) {
+	return s.authored, nil
+}
+
+func (s *stubPRService) ReleaseReviewer(_ context.Context, prID, _ string) (*pull_request.PR, string, error) {
+	s.released = append(s.released, prID)
+	return &pull_request.PR{PullRequestId: prID}, s.replacement[prID], nil
+}
+
+func (s *stubPRService) TransferAuthorship(_ context.Context, prID, newAuthorID string) (*pull_request.PR, error) {
+	if s.transferErr != nil {
+		return nil, s.transferErr
+	}
+	if s.transferred == nil {
+		s.transferred = make(map[string]string)
+	}
+	s.transferred[prID] = newAuthorID
+	return &pull_request.PR{PullRequestId: prID, AuthorId: newAuthorID}, nil
+}
+
+func TestService_OffboardReleasesOpenReviewsAndTransfersAuthorship(t *testing.T) {
+	users := &stubUserService{
+		users: map[string]*user.User{
+			"u1": {UserId: "u1", IsActive: true},
+			"u2": {UserId: "u2", IsActive: true},
+		},
+	}
+	prs := &stubPRService{
+		reviews: []pull_request.PullRequestShort{
+			*pull_request.NewPullRequestShort("pr-1", "Open", "u3", pull_request.OPEN),
+			*pull_request.NewPullRequestShort("pr-2", "Merged", "u3", pull_request.MERGED),
+			*pull_request.NewPullRequestShort("pr-3", "Open", "u3", pull_request.OPEN),
+		},
+		authored: []pull_request.PullRequestShort{
+			*pull_request.NewPullRequestShort("pr-4", "Mine", "u1", pull_request.OPEN),
+		},
+		replacement: map[string]string{"pr-1": "u4"},
+	}
+
+	svc := NewService(users, prs)
+
+	result, err := svc.Offboard(context.Background(), "u1", "u2")
+	if err != nil {
+		t.Fatalf("Offboard() error = %v", err)
+	}
+
+	if !result.User.IsDeparted() || result.User.IsActive {
+		t.Fatalf("expected user to be departed and inactive")
+	}
+	if len(prs.released) != 2 || prs.released[0] != "pr-1" || prs.released[1] != "pr-3" {
+		t.Fatalf("expected open reviews to be released, got %v", prs.released)
+	}
+	if result.Reassigned[0].ReplacedBy != "u4" || result.Reassigned[1].ReplacedBy != "" {
+		t.Fatalf("unexpected reassignments: %+v", result.Reassigned)
+	}
+	if prs.transferred["pr-4"] != "u2" || len(result.Transferred) != 1 {
+		t.Fatalf("expected pr-4 to be transferred to u2, got %v", prs.transferred)
+	}
+}
+
+func TestService_OffboardRejectsInvalidTransferTarget(t *testing.T) {
+	departedAt := time.Now()
+	users := &stubUserService{
+		users: map[string]*user.User{
+			"u1": {UserId: "u1", IsActive: true},
+			"u2": {UserId: "u2", DepartedAt: &departedAt},
+		},
+	}
+	svc := NewService(users, &stubPRService{})
+
+	for _, target := range []string{"u1", "u2"} {
+		if _, err := svc.Offboard(context.Background(), "u1", target); !errors.Is(err, ErrInvalidTransferTarget) {
+			t.Fatalf("expected ErrInvalidTransferTarget for %s, got %v", target, err)
+		}
+	}
+	if len(users.departed) != 0 {
+		t.Fatalf("user must not be marked departed when validation fails")
+	}
+}
+
+func TestService_OffboardRollsBackWhenTransferFails(t *testing.T) {
+	users := &stubUserService{
+		users: map[string]*user.User{
+			"u1": {UserId: "u1", IsActive: true},
+			"u2": {UserId: "u2", IsActive: true},
+		},
+	}
+	errTransfer := errors.New("transfer failed")
+	prs := &stubPRService{
+		authored: []pull_request.PullRequestShort{
+			*pull_request.NewPullRequestShort("pr-4", "Mine", "u1", pull_request.OPEN),
+		},
+		transferErr: errTransfer,
+	}
+	svc := NewService(users, prs, WithTxManager(txn.NewMemory()))
+
+	if _, err := svc.Offboard(context.Background(), "u1", "u2"); !errors.Is(err, errTransfer) {
+		t.Fatalf("expected transfer error, got %v", err)
+	}
+
+	u := users.users["u1"]
+	if u.IsDeparted() || !u.IsActive || len(users.departed) != 0 {
+		t.Fatalf("expected departure to be rolled back, got %+v", u)
+	}
+}
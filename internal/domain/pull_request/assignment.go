@@ -0,0 +1,46 @@
+package pull_request
+
+import "time"
+
+type AssignmentReason string
+
+const (
+	ReasonCreated            AssignmentReason = "CREATED"
+	ReasonReassigned         AssignmentReason = "REASSIGNED"
+	ReasonReleased           AssignmentReason = "RELEASED"
+	ReasonAuthorshipTransfer AssignmentReason = "AUTHORSHIP_TRANSFERRED"
+)
+
+// Verdict is the outcome of a review as submitted by the reviewer.
+type Verdict string
+
+const (
+	VerdictPending          Verdict = "PENDING"
+	VerdictApproved         Verdict = "APPROVED"
+	VerdictChangesRequested Verdict = "CHANGES_REQUESTED"
+	VerdictCommented        Verdict = "COMMENTED"
+)
+
+// Assignment is one stint of a reviewer on a PR. UnassignedAt is nil while
+// the reviewer is still assigned, and VerdictAt is nil while the verdict is
+// pending.
+type Assignment struct {
+	ReviewerID     string
+	AssignedAt     time.Time
+	AssignedReason AssignmentReason
+	AssignedBy     string
+	UnassignedAt   *time.Time
+	UnassignReason AssignmentReason
+	UnassignedBy   string
+	Verdict        Verdict
+	VerdictAt      *time.Time
+}
+
+// ReviewerChange explains the latest change of AssignedReviewers. Storages
+// record it in the assignment history of the reviewers added or removed by
+// Create and Update.
+type ReviewerChange struct {
+	Reason AssignmentReason
+	Actor  string
+	At     time.Time
+}
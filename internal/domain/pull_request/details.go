@@ -0,0 +1,80 @@
+package pull_request
+
+import (
+	"InternshipTask/internal/domain/user"
+	"context"
+	"fmt"
+)
+
+// Expand selects which related users GetDetails loads along with the PR.
+type Expand struct {
+	Author    bool
+	Reviewers bool
+}
+
+// Participant is a user related to a PR together with their current load:
+// the number of OPEN PRs they review.
+type Participant struct {
+	User        *user.User
+	OpenReviews int64
+}
+
+// Details is a PR with the related users requested by Expand. Author and
+// Reviewers are left empty when not requested.
+type Details struct {
+	PR        *PR
+	Author    *Participant
+	Reviewers []Participant
+}
+
+func (s *Service) GetDetails(ctx context.Context, id string, expand Expand) (*Details, error) {
+	pr, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Details{PR: pr}
+	if !expand.Author && !expand.Reviewers {
+		return d, nil
+	}
+
+	var ids []string
+	if expand.Author {
+		ids = append(ids, pr.AuthorId)
+	}
+	if expand.Reviewers {
+		ids = append(ids, pr.AssignedReviewers...)
+	}
+	load, err := s.repo.GetOpenReviewCounts(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("get open review counts: %w", err)
+	}
+
+	participant := func(userID string) (Participant, error) {
+		u, err := s.userReader.GetByID(ctx, userID)
+		if err != nil {
+			return Participant{}, fmt.Errorf("get user %s: %w", userID, err)
+		}
+		return Participant{User: u, OpenReviews: load[userID]}, nil
+	}
+
+	if expand.Author {
+		author, err := participant(pr.AuthorId)
+		if err != nil {
+			return nil, err
+		}
+		d.Author = &author
+	}
+	if expand.Reviewers {
+		d.Reviewers = make([]Participant, 0, len(pr.AssignedReviewers))
+		for _, reviewerID := range pr.AssignedReviewers {
+			reviewer, err := participant(reviewerID)
+			if err != nil {
+				return nil, err
+			}
+			d.Reviewers = append(d.Reviewers, reviewer)
+		}
+	}
+
+	return d, nil
+}
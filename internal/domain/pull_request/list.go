@@ -0,0 +1,128 @@
+package pull_request
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 500
+)
+
+var (
+	ErrInvalidFilter = errors.New("invalid pull request filter")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// SortOrder is the order of List. Both orders break ties by PR id, so every
+// PR has a stable position to resume from.
+type SortOrder string
+
+const (
+	SortCreatedAsc  SortOrder = "created_at"
+	SortCreatedDesc SortOrder = "-created_at"
+)
+
+// ListFilter narrows List down; zero fields do not filter. The From bounds
+// are inclusive and the To bounds exclusive. ReviewerID matches PRs the user
+// currently reviews, and Name is a case-insensitive substring of the PR name.
+type ListFilter struct {
+	Status          PullRequestStatus
+	AuthorID        string
+	TeamName        string
+	ReviewerID      string
+	CreatedFrom     time.Time
+	CreatedTo       time.Time
+	MergedFrom      time.Time
+	MergedTo        time.Time
+	Name            string
+	IncludeArchived bool
+	Sort            SortOrder
+	// After is the position of the last PR of the previous page.
+	After *Cursor
+	Limit int
+}
+
+// Cursor is a position in a List result: the sort key of the last PR seen.
+type Cursor struct {
+	Sort      SortOrder `json:"s"`
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id"`
+}
+
+func cursorOf(sort SortOrder, pr *PR) Cursor {
+	c := Cursor{Sort: sort, ID: pr.PullRequestId}
+	if pr.CreatedAt != nil {
+		c.CreatedAt = *pr.CreatedAt
+	}
+	return c
+}
+
+// String encodes the cursor as an opaque URL-safe token.
+func (c Cursor) String() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func ParseCursor(s string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" || !c.Sort.valid() {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+func (o SortOrder) valid() bool {
+	return o == SortCreatedAsc || o == SortCreatedDesc
+}
+
+// Page is one page of a List result. Next is nil on the last page.
+type Page struct {
+	Items []PR
+	Next  *Cursor
+}
+
+// List returns one page of the PRs matching f.
+func (s *Service) List(ctx context.Context, f ListFilter) (Page, error) {
+	if f.Status != "" && f.Status != OPEN && f.Status != MERGED {
+		return Page{}, fmt.Errorf("%w: unknown status %q", ErrInvalidFilter, f.Status)
+	}
+	if f.Sort == "" {
+		f.Sort = SortCreatedDesc
+	}
+	if !f.Sort.valid() {
+		return Page{}, fmt.Errorf("%w: unknown sort %q", ErrInvalidFilter, f.Sort)
+	}
+	if f.After != nil && f.After.Sort != f.Sort {
+		return Page{}, fmt.Errorf("%w: issued for sort %q", ErrInvalidCursor, f.After.Sort)
+	}
+	if f.Limit <= 0 {
+		f.Limit = DefaultListLimit
+	}
+	f.Limit = min(f.Limit, MaxListLimit)
+
+	// One extra row tells whether there is a next page.
+	limit := f.Limit
+	f.Limit++
+	prs, err := s.repo.List(ctx, f)
+	if err != nil {
+		return Page{}, fmt.Errorf("list pull requests: %w", err)
+	}
+
+	page := Page{Items: prs}
+	if len(prs) > limit {
+		page.Items = prs[:limit]
+		next := cursorOf(f.Sort, &page.Items[limit-1])
+		page.Next = &next
+	}
+	return page, nil
+}
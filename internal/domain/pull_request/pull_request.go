@@ -1,6 +1,9 @@
 package pull_request
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 type PullRequestStatus string
 
@@ -14,13 +17,20 @@ func (s PullRequestStatus) String() string {
 }
 
 type PR struct {
-	PullRequestId     string `gorm:"primary_key"`
-	PullRequestName   string
-	AuthorId          string
-	Status            PullRequestStatus
-	AssignedReviewers []string
-	CreatedAt         *time.Time
-	MergedAt          *time.Time
+	PullRequestId     string            `json:"pull_request_id" gorm:"primary_key"`
+	PullRequestName   string            `json:"pull_request_name"`
+	AuthorId          string            `json:"author_id"`
+	TeamName          string            `json:"team_name"`
+	Status            PullRequestStatus `json:"status"`
+	AssignedReviewers []string          `json:"assigned_reviewers"`
+	RequiredSkills    []string          `json:"required_skills"`
+	SkillFallback     bool              `json:"-" gorm:"-"`
+	ReviewerChange    ReviewerChange    `json:"-" gorm:"-"`
+	CreatedAt         *time.Time        `json:"created_at,omitempty"`
+	MergedAt          *time.Time        `json:"merged_at,omitempty"`
+	// Version is bumped by every successful Update; an Update carrying a stale
+	// version fails with ErrConflict.
+	Version int64 `json:"version"`
 }
 
 func NewPR(id string, name string, authorId string, status PullRequestStatus) *PR {
@@ -33,6 +43,15 @@ func NewPR(id string, name string, authorId string, status PullRequestStatus) *P
 		AuthorId:          authorId,
 		Status:            status,
 		AssignedReviewers: make([]string, 0),
+		RequiredSkills:    make([]string, 0),
 		CreatedAt:         &now,
 	}
 }
+
+// clone returns a deep copy of pr, e.g. to keep its state before a change.
+func (pr *PR) clone() *PR {
+	c := *pr
+	c.AssignedReviewers = slices.Clone(pr.AssignedReviewers)
+	c.RequiredSkills = slices.Clone(pr.RequiredSkills)
+	return &c
+}
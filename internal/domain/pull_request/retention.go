@@ -0,0 +1,71 @@
+package pull_request
+
+import (
+	"InternshipTask/internal/domain/logging"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const (
+	DefaultRetentionInterval = time.Hour
+	retentionBatchSize       = 500
+)
+
+// Retention periodically archives PRs that were merged more than maxAge ago.
+type Retention struct {
+	svc      *Service
+	maxAge   time.Duration
+	interval time.Duration
+}
+
+func NewRetention(svc *Service, maxAge, interval time.Duration) *Retention {
+	if interval <= 0 {
+		interval = DefaultRetentionInterval
+	}
+	return &Retention{svc: svc, maxAge: maxAge, interval: interval}
+}
+
+// Run archives until ctx is cancelled.
+func (r *Retention) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RunOnce(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			logging.FromContext(ctx).ErrorContext(ctx, "archive merged pull requests", slog.Any("err", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce archives every PR that is due and returns how many were archived.
+// Each batch is its own unit of work, so a failure keeps the batches already
+// archived.
+func (r *Retention) RunOnce(ctx context.Context) (int, error) {
+	cutoff := r.svc.clock.Now().Add(-r.maxAge)
+
+	total := 0
+	for {
+		var n int
+		err := r.svc.tx.Do(ctx, func(ctx context.Context) error {
+			var err error
+			n, err = r.svc.repo.ArchiveMerged(ctx, cutoff, retentionBatchSize)
+			return err
+		})
+		if err != nil {
+			return total, fmt.Errorf("archive merged before %s: %w", cutoff.Format(time.RFC3339), err)
+		}
+		total += n
+		if n < retentionBatchSize {
+			return total, nil
+		}
+	}
+}
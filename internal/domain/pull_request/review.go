@@ -0,0 +1,146 @@
+package pull_request
+
+import (
+	"InternshipTask/internal/domain/audit"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidVerdict = errors.New("invalid verdict")
+
+// ReviewFilter narrows ListReviews down to the current reviews of
+// ReviewerID; the other zero fields do not filter. TeamName is the team the
+// PR was raised in, and the Created bounds are inclusive and exclusive.
+type ReviewFilter struct {
+	ReviewerID      string
+	Status          PullRequestStatus
+	TeamName        string
+	CreatedFrom     time.Time
+	CreatedTo       time.Time
+	IncludeArchived bool
+	// After is the position of the last review of the previous page.
+	After *Cursor
+	Limit int
+}
+
+// Review is a PR as seen by one of its current reviewers.
+type Review struct {
+	PullRequestShort
+	TeamName   string
+	CreatedAt  *time.Time
+	MergedAt   *time.Time
+	AssignedAt time.Time
+	Verdict    Verdict
+	VerdictAt  *time.Time
+	// Age is how long the reviewer has had the PR: until the merge for a
+	// merged PR and until now otherwise. It is set by the service.
+	Age time.Duration
+}
+
+// ReviewPage is one page of a ListReviews result, oldest PR first. Next is nil
+// on the last page.
+type ReviewPage struct {
+	Items []Review
+	Next  *Cursor
+}
+
+// ListReviews returns one page of the PRs f.ReviewerID currently reviews.
+func (s *Service) ListReviews(ctx context.Context, f ReviewFilter) (ReviewPage, error) {
+	if f.Status != "" && f.Status != OPEN && f.Status != MERGED {
+		return ReviewPage{}, fmt.Errorf("%w: unknown status %q", ErrInvalidFilter, f.Status)
+	}
+	if f.After != nil && f.After.Sort != SortCreatedAsc {
+		return ReviewPage{}, fmt.Errorf("%w: issued for sort %q", ErrInvalidCursor, f.After.Sort)
+	}
+	if f.Limit <= 0 {
+		f.Limit = DefaultListLimit
+	}
+	f.Limit = min(f.Limit, MaxListLimit)
+
+	limit := f.Limit
+	f.Limit++
+	reviews, err := s.repo.ListReviews(ctx, f)
+	if err != nil {
+		return ReviewPage{}, fmt.Errorf("list reviews: %w", err)
+	}
+
+	now := s.clock.Now()
+	for i := range reviews {
+		r := &reviews[i]
+		end := now
+		if r.MergedAt != nil {
+			end = *r.MergedAt
+		}
+		r.Age = max(end.Sub(r.AssignedAt), 0)
+	}
+
+	page := ReviewPage{Items: reviews}
+	if len(reviews) > limit {
+		page.Items = reviews[:limit]
+		last := page.Items[limit-1]
+		next := Cursor{Sort: SortCreatedAsc, ID: last.PullRequestId}
+		if last.CreatedAt != nil {
+			next.CreatedAt = *last.CreatedAt
+		}
+		page.Next = &next
+	}
+	return page, nil
+}
+
+// SetVerdict records the verdict of reviewerID, who must currently review the
+// open PR, and returns the updated assignment.
+func (s *Service) SetVerdict(ctx context.Context, prID, reviewerID string, verdict Verdict) (*Assignment, error) {
+	switch verdict {
+	case VerdictApproved, VerdictChangesRequested, VerdictCommented:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidVerdict, verdict)
+	}
+
+	var updated *Assignment
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		var err error
+		updated, err = s.setVerdict(ctx, prID, reviewerID, verdict)
+		return err
+	})
+	return updated, err
+}
+
+func (s *Service) setVerdict(ctx context.Context, prID, reviewerID string, verdict Verdict) (*Assignment, error) {
+	pr, err := s.repo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if pr.Status == MERGED {
+		return nil, ErrPRMerged
+	}
+
+	history, err := s.repo.GetAssignments(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("get assignments: %w", err)
+	}
+	var before *Assignment
+	for i := range history {
+		if history[i].ReviewerID == reviewerID && history[i].UnassignedAt == nil {
+			before = &history[i]
+		}
+	}
+	if before == nil {
+		return nil, ErrNotAssigned
+	}
+
+	at := s.clock.Now()
+	if err := s.repo.SetVerdict(ctx, prID, reviewerID, verdict, at); err != nil {
+		return nil, fmt.Errorf("set verdict: %w", err)
+	}
+
+	after := *before
+	after.Verdict = verdict
+	after.VerdictAt = &at
+	if err := s.audit.Record(ctx, audit.ActionPRVerdict, audit.EntityPullRequest, prID, before, &after); err != nil {
+		return nil, err
+	}
+
+	return &after, nil
+}
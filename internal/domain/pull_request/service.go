@@ -1,12 +1,19 @@
 package pull_request
 
 import (
+	"InternshipTask/internal/domain/actor"
+	"InternshipTask/internal/domain/audit"
+	"InternshipTask/internal/domain/clock"
+	"InternshipTask/internal/domain/events"
+	"InternshipTask/internal/domain/logging"
 	"InternshipTask/internal/domain/team"
+	"InternshipTask/internal/domain/txn"
 	"InternshipTask/internal/domain/user"
 	"context"
 	"errors"
 	"fmt"
-	"math/rand"
+	"log/slog"
+	"slices"
 	"time"
 )
 
@@ -16,14 +23,36 @@ var (
 	ErrNotAssigned = errors.New("reviewer not assigned")
 	ErrNoCandidate = errors.New("no active replacement user")
 	ErrNotFound    = errors.New("pr not found")
+	ErrConflict    = errors.New("pr was modified concurrently")
+
+	ErrAuthorNotInTeam = errors.New("author is not a member of the team")
 )
 
 type Repository interface {
 	Create(ctx context.Context, pr *PR) error
 	GetByID(ctx context.Context, id string) (*PR, error)
 	Update(ctx context.Context, pr *PR) error
-	GetByReviewerID(ctx context.Context, reviewerID string) ([]PullRequestShort, error)
-	GetReviewerStats(ctx context.Context) (map[string]int64, error)
+	GetByReviewerID(ctx context.Context, reviewerID string, includeArchived bool) ([]PullRequestShort, error)
+	GetOpenByAuthorID(ctx context.Context, authorID string) ([]PullRequestShort, error)
+	GetReviewerStats(ctx context.Context, includeArchived bool) (map[string]int64, error)
+	// GetOpenReviewCounts returns how many OPEN PRs each of the reviewers
+	// currently reviews. Reviewers without any are missing from the map.
+	GetOpenReviewCounts(ctx context.Context, reviewerIDs []string) (map[string]int64, error)
+	GetAssignments(ctx context.Context, prID string) ([]Assignment, error)
+	// ArchiveMerged moves up to limit PRs merged before mergedBefore, oldest
+	// first, together with their assignments into the archive and returns how
+	// many were moved. Archived PRs are invisible to the other methods unless
+	// includeArchived is set, and their ids cannot be reused.
+	ArchiveMerged(ctx context.Context, mergedBefore time.Time, limit int) (int, error)
+	// ListReviews returns up to f.Limit current reviews of f.ReviewerID,
+	// oldest PR first, starting right after f.After.
+	ListReviews(ctx context.Context, f ReviewFilter) ([]Review, error)
+	// SetVerdict sets the verdict on the open assignment of reviewerID and
+	// fails with ErrNotAssigned when there is none.
+	SetVerdict(ctx context.Context, prID, reviewerID string, verdict Verdict, at time.Time) error
+	// List returns up to f.Limit PRs matching f in f.Sort order, starting
+	// right after f.After.
+	List(ctx context.Context, f ListFilter) ([]PR, error)
 }
 
 type UserReader interface {
@@ -32,54 +61,195 @@ type UserReader interface {
 
 type TeamReader interface {
 	GetByTeamName(ctx context.Context, teamName string) (team.Team, error)
+	GetTeamNamesByUserID(ctx context.Context, userID string) ([]string, error)
+}
+
+type CreateParams struct {
+	ID             string
+	Name           string
+	AuthorID       string
+	TeamName       string
+	RequiredSkills []string
 }
 
+const (
+	defaultReviewSLA  = 24 * time.Hour
+	defaultMaxRetries = 3
+)
+
 type Service struct {
 	repo       Repository
 	userReader UserReader
 	teamReader TeamReader
-	rand       *rand.Rand
+	rand       *lockedRand
+	clock      clock.Clock
+	reviewSLA  time.Duration
+	tx         txn.Manager
+	maxRetries int
+	audit      audit.Recorder
+	events     events.Publisher
+}
+
+type Option func(*Service)
+
+func WithClock(c clock.Clock) Option {
+	return func(s *Service) {
+		s.clock = c
+	}
+}
+
+// WithReviewSLA sets how many working hours a reviewer has before the review is considered overdue.
+func WithReviewSLA(d time.Duration) Option {
+	return func(s *Service) {
+		if d > 0 {
+			s.reviewSLA = d
+		}
+	}
+}
+
+// WithTxManager makes every read-modify-write of a PR run as one unit of work.
+func WithTxManager(m txn.Manager) Option {
+	return func(s *Service) {
+		s.tx = m
+	}
 }
 
-func NewService(repo Repository, ur UserReader, tr TeamReader) *Service {
-	return &Service{
+// WithMaxRetries sets how many times an operation is retried after losing an
+// optimistic concurrency race before ErrConflict is returned to the caller.
+func WithMaxRetries(n int) Option {
+	return func(s *Service) {
+		if n >= 0 {
+			s.maxRetries = n
+		}
+	}
+}
+
+// WithAuditLog records every change of a PR in r.
+func WithAuditLog(r audit.Recorder) Option {
+	return func(s *Service) {
+		s.audit = r
+	}
+}
+
+// WithEvents publishes PR lifecycle events through p in the same unit of work
+// as the change that caused them.
+func WithEvents(p events.Publisher) Option {
+	return func(s *Service) {
+		s.events = p
+	}
+}
+
+func NewService(repo Repository, ur UserReader, tr TeamReader, opts ...Option) *Service {
+	s := &Service{
 		repo:       repo,
 		userReader: ur,
 		teamReader: tr,
-		rand:       rand.New(rand.NewSource(time.Now().UnixNano())),
+		rand:       newLockedRand(time.Now().UnixNano()),
+		clock:      clock.Real{},
+		reviewSLA:  defaultReviewSLA,
+		tx:         txn.Nop{},
+		maxRetries: defaultMaxRetries,
+		audit:      audit.Nop{},
+		events:     events.Nop{},
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
-func (s *Service) Create(ctx context.Context, id, name, authorID string) (*PR, error) {
-	if _, err := s.repo.GetByID(ctx, id); err == nil {
+func (s *Service) Create(ctx context.Context, p CreateParams) (*PR, error) {
+	var pr *PR
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		var err error
+		pr, err = s.create(ctx, p)
+		return err
+	})
+	return pr, err
+}
+
+// withRetry runs fn as a unit of work and starts it over from a fresh read
+// when it loses an optimistic concurrency race, at most maxRetries times.
+func (s *Service) withRetry(ctx context.Context, fn func(ctx context.Context) error) error {
+	var err error
+	for attempt := 0; attempt <= s.maxRetries; attempt++ {
+		err = s.tx.Do(ctx, fn)
+		if !errors.Is(err, ErrConflict) {
+			return err
+		}
+		logging.FromContext(ctx).DebugContext(ctx, "retry after concurrent update", slog.Int("attempt", attempt+1))
+	}
+	return err
+}
+
+func (s *Service) create(ctx context.Context, p CreateParams) (*PR, error) {
+	if _, err := s.repo.GetByID(ctx, p.ID); err == nil {
 		return nil, ErrPRExists
 	} else if !errors.Is(err, ErrNotFound) && err != nil {
 		return nil, fmt.Errorf("get pr by id: %w", err)
 	}
 
-	author, err := s.userReader.GetByID(ctx, authorID)
+	requiredSkills, err := user.NormalizeSkills(p.RequiredSkills)
+	if err != nil {
+		return nil, err
+	}
+
+	author, err := s.userReader.GetByID(ctx, p.AuthorID)
 	if err != nil {
 		return nil, fmt.Errorf("get author: %w", err)
 	}
 
-	t, err := s.teamReader.GetByTeamName(ctx, author.TeamName)
+	teamName := p.TeamName
+	if teamName == "" {
+		teamName = author.TeamName
+	} else if teamName != author.TeamName {
+		authorTeams, err := s.teamReader.GetTeamNamesByUserID(ctx, p.AuthorID)
+		if err != nil {
+			return nil, fmt.Errorf("get author teams: %w", err)
+		}
+		if !slices.Contains(authorTeams, teamName) {
+			return nil, ErrAuthorNotInTeam
+		}
+	}
+
+	t, err := s.teamReader.GetByTeamName(ctx, teamName)
 	if err != nil {
 		return nil, fmt.Errorf("get team: %w", err)
 	}
 
-	reviewers := s.pickReviewersFromTeam(&t, authorID)
+	now := s.clock.Now()
+	reviewers, matched := s.pickReviewersFromTeam(&t, p.AuthorID, requiredSkills, now)
 
-	pr := NewPR(id, name, authorID, OPEN)
+	pr := NewPR(p.ID, p.Name, p.AuthorID, OPEN)
+	pr.CreatedAt = &now
+	pr.TeamName = teamName
 	pr.AssignedReviewers = reviewers
+	pr.RequiredSkills = requiredSkills
+	pr.SkillFallback = len(requiredSkills) > 0 && !matched
+	pr.ReviewerChange = ReviewerChange{Reason: ReasonCreated, Actor: actor.FromContext(ctx), At: now}
 
 	if err := s.repo.Create(ctx, pr); err != nil {
 		return nil, fmt.Errorf("create pr: %w", err)
 	}
 
+	if err := s.record(ctx, audit.ActionPRCreate, nil, pr); err != nil {
+		return nil, err
+	}
+
 	return pr, nil
 }
 
 func (s *Service) Merge(ctx context.Context, id string) (*PR, error) {
+	var pr *PR
+	err := s.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		pr, err = s.merge(ctx, id)
+		return err
+	})
+	return pr, err
+}
+
+func (s *Service) merge(ctx context.Context, id string) (*PR, error) {
 	pr, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -89,7 +259,8 @@ func (s *Service) Merge(ctx context.Context, id string) (*PR, error) {
 		return pr, nil
 	}
 
-	now := time.Now().UTC()
+	before := pr.clone()
+	now := s.clock.Now()
 	pr.Status = MERGED
 	pr.MergedAt = &now
 
@@ -97,110 +268,357 @@ func (s *Service) Merge(ctx context.Context, id string) (*PR, error) {
 		return nil, fmt.Errorf("update pr on merge: %w", err)
 	}
 
+	if err := s.record(ctx, audit.ActionPRMerge, before, pr); err != nil {
+		return nil, err
+	}
+
 	return pr, nil
 }
 
 func (s *Service) Reassign(ctx context.Context, prID, oldUserID string) (*PR, string, error) {
+	var (
+		pr        *PR
+		candidate string
+	)
+	err := s.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		pr, candidate, err = s.reassign(ctx, prID, oldUserID)
+		return err
+	})
+	return pr, candidate, err
+}
+
+func (s *Service) reassign(ctx context.Context, prID, oldUserID string) (*PR, string, error) {
+	pr, err := s.repo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	before := pr.clone()
+	candidate, err := s.replaceReviewer(ctx, pr, oldUserID)
+	if err != nil {
+		return nil, "", err
+	}
+	pr.ReviewerChange = s.reviewerChange(ctx, ReasonReassigned)
+
+	if err := s.repo.Update(ctx, pr); err != nil {
+		return nil, "", fmt.Errorf("update pr on reassign: %w", err)
+	}
+
+	if err := s.record(ctx, audit.ActionPRReassign, before, pr); err != nil {
+		return nil, "", err
+	}
+
+	reassigned := events.ReassignedPayload{PullRequestID: pr.PullRequestId, OldReviewerID: oldUserID, NewReviewerID: candidate}
+	if err := s.publish(ctx, events.PullRequestReassigned, pr.PullRequestId, reassigned); err != nil {
+		return nil, "", err
+	}
+
+	return pr, candidate, nil
+}
+
+// ReleaseReviewer frees oldUserID's review slot on an open PR: the slot goes to
+// a replacement when one is available and is dropped otherwise. The returned
+// id is empty when the slot was dropped.
+func (s *Service) ReleaseReviewer(ctx context.Context, prID, oldUserID string) (*PR, string, error) {
+	var (
+		pr        *PR
+		candidate string
+	)
+	err := s.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		pr, candidate, err = s.releaseReviewer(ctx, prID, oldUserID)
+		return err
+	})
+	return pr, candidate, err
+}
+
+func (s *Service) releaseReviewer(ctx context.Context, prID, oldUserID string) (*PR, string, error) {
 	pr, err := s.repo.GetByID(ctx, prID)
 	if err != nil {
 		return nil, "", err
 	}
 
+	before := pr.clone()
+	candidate, err := s.replaceReviewer(ctx, pr, oldUserID)
+	if errors.Is(err, ErrNoCandidate) {
+		pr.AssignedReviewers = slices.DeleteFunc(pr.AssignedReviewers, func(id string) bool {
+			return id == oldUserID
+		})
+	} else if err != nil {
+		return nil, "", err
+	}
+	pr.ReviewerChange = s.reviewerChange(ctx, ReasonReleased)
+
+	if err := s.repo.Update(ctx, pr); err != nil {
+		return nil, "", fmt.Errorf("update pr on release: %w", err)
+	}
+
+	if err := s.record(ctx, audit.ActionPRReleaseReviewer, before, pr); err != nil {
+		return nil, "", err
+	}
+
+	return pr, candidate, nil
+}
+
+// TransferAuthorship hands an open PR over to newAuthorID. If the new author
+// was reviewing the PR, their review slot is released.
+func (s *Service) TransferAuthorship(ctx context.Context, prID, newAuthorID string) (*PR, error) {
+	var pr *PR
+	err := s.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		pr, err = s.transferAuthorship(ctx, prID, newAuthorID)
+		return err
+	})
+	return pr, err
+}
+
+func (s *Service) transferAuthorship(ctx context.Context, prID, newAuthorID string) (*PR, error) {
+	pr, err := s.repo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
 	if pr.Status == MERGED {
-		return nil, "", ErrPRMerged
+		return nil, ErrPRMerged
 	}
 
-	idx := -1
-	for i, rid := range pr.AssignedReviewers {
-		if rid == oldUserID {
-			idx = i
-			break
+	before := pr.clone()
+	if slices.Contains(pr.AssignedReviewers, newAuthorID) {
+		if _, err := s.replaceReviewer(ctx, pr, newAuthorID); errors.Is(err, ErrNoCandidate) {
+			pr.AssignedReviewers = slices.DeleteFunc(pr.AssignedReviewers, func(id string) bool {
+				return id == newAuthorID
+			})
+		} else if err != nil {
+			return nil, err
 		}
 	}
+
+	pr.AuthorId = newAuthorID
+	pr.ReviewerChange = s.reviewerChange(ctx, ReasonAuthorshipTransfer)
+
+	if err := s.repo.Update(ctx, pr); err != nil {
+		return nil, fmt.Errorf("update pr on authorship transfer: %w", err)
+	}
+
+	if err := s.record(ctx, audit.ActionPRTransfer, before, pr); err != nil {
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+func (s *Service) GetOpenByAuthorID(ctx context.Context, authorID string) ([]PullRequestShort, error) {
+	return s.repo.GetOpenByAuthorID(ctx, authorID)
+}
+
+// replaceReviewer swaps oldUserID for a replacement in pr.AssignedReviewers
+// without persisting the change.
+func (s *Service) replaceReviewer(ctx context.Context, pr *PR, oldUserID string) (string, error) {
+	if pr.Status == MERGED {
+		return "", ErrPRMerged
+	}
+
+	idx := slices.Index(pr.AssignedReviewers, oldUserID)
 	if idx == -1 {
-		return nil, "", ErrNotAssigned
+		return "", ErrNotAssigned
 	}
 
 	oldUser, err := s.userReader.GetByID(ctx, oldUserID)
 	if err != nil {
-		return nil, "", fmt.Errorf("get old reviewer: %w", err)
+		return "", fmt.Errorf("get old reviewer: %w", err)
 	}
 
-	t, err := s.teamReader.GetByTeamName(ctx, oldUser.TeamName)
+	teams, err := s.replacementTeams(ctx, oldUser, pr)
 	if err != nil {
-		return nil, "", fmt.Errorf("get team for old reviewer: %w", err)
+		return "", err
 	}
 
-	candidate, ok := s.pickReplacementFromTeam(&t, oldUserID, pr.AssignedReviewers)
+	candidate, ok := s.pickReplacementFromTeams(teams, pr, oldUserID, s.clock.Now())
 	if !ok {
-		return nil, "", ErrNoCandidate
+		return "", ErrNoCandidate
 	}
 
 	pr.AssignedReviewers[idx] = candidate
 
-	if err := s.repo.Update(ctx, pr); err != nil {
-		return nil, "", fmt.Errorf("update pr on reassign: %w", err)
+	return candidate, nil
+}
+
+// record writes a change of a PR to the audit log and publishes the events it
+// implies. before is nil for a new PR.
+func (s *Service) record(ctx context.Context, action audit.Action, before, after *PR) error {
+	if err := s.audit.Record(ctx, action, audit.EntityPullRequest, after.PullRequestId, before, after); err != nil {
+		return err
 	}
 
-	return pr, candidate, nil
+	var previous []string
+	if before == nil {
+		if err := s.publish(ctx, events.PullRequestCreated, after.PullRequestId, after); err != nil {
+			return err
+		}
+	} else {
+		previous = before.AssignedReviewers
+		if before.Status != MERGED && after.Status == MERGED {
+			if err := s.publish(ctx, events.PullRequestMerged, after.PullRequestId, after); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, id := range after.AssignedReviewers {
+		if slices.Contains(previous, id) {
+			continue
+		}
+		assigned := events.ReviewerAssignedPayload{
+			PullRequestID: after.PullRequestId,
+			ReviewerID:    id,
+			Reason:        string(after.ReviewerChange.Reason),
+		}
+		if err := s.publish(ctx, events.PullRequestReviewerAssigned, after.PullRequestId, assigned); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) publish(ctx context.Context, eventType events.Type, prID string, payload any) error {
+	return s.events.Publish(ctx, eventType, events.AggregatePullRequest, prID, payload)
+}
+
+func (s *Service) reviewerChange(ctx context.Context, reason AssignmentReason) ReviewerChange {
+	return ReviewerChange{Reason: reason, Actor: actor.FromContext(ctx), At: s.clock.Now()}
+}
+
+// GetAssignments returns the full reviewer history of a PR, oldest first.
+func (s *Service) GetAssignments(ctx context.Context, prID string) ([]Assignment, error) {
+	if _, err := s.repo.GetByID(ctx, prID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetAssignments(ctx, prID)
 }
 
-func (s *Service) GetByReviewerID(ctx context.Context, userID string) ([]PullRequestShort, error) {
-	return s.repo.GetByReviewerID(ctx, userID)
+func (s *Service) GetByReviewerID(ctx context.Context, userID string, includeArchived bool) ([]PullRequestShort, error) {
+	return s.repo.GetByReviewerID(ctx, userID, includeArchived)
 }
 
-func (s *Service) ReviewerStats(ctx context.Context) (map[string]int64, error) {
-	return s.repo.GetReviewerStats(ctx)
+func (s *Service) ReviewerStats(ctx context.Context, includeArchived bool) (map[string]int64, error) {
+	return s.repo.GetReviewerStats(ctx, includeArchived)
 }
 
-func (s *Service) pickReviewersFromTeam(t *team.Team, authorID string) []string {
-	candidates := make([]string, 0, len(t.Members))
+// pickReviewersFromTeam picks up to two active reviewers, preferring those
+// whose skills overlap the required ones and then those inside their working
+// hours at now. The second result reports whether at least one picked
+// reviewer has a required skill.
+func (s *Service) pickReviewersFromTeam(t *team.Team, authorID string, requiredSkills []string, now time.Time) ([]string, bool) {
+	candidates := make([]*user.User, 0, len(t.Members))
 
 	for _, u := range t.Members {
 		if u == nil {
 			continue
 		}
-		if !u.IsActive {
+		if !u.CanReview() {
 			continue
 		}
 		if u.UserId == authorID {
 			continue
 		}
-		candidates = append(candidates, u.UserId)
+		candidates = append(candidates, u)
 	}
 
 	s.rand.Shuffle(len(candidates), func(i, j int) {
 		candidates[i], candidates[j] = candidates[j], candidates[i]
 	})
 
+	slices.SortStableFunc(candidates, func(a, b *user.User) int {
+		return rankCandidate(b, requiredSkills, now) - rankCandidate(a, requiredSkills, now)
+	})
+
 	if len(candidates) > 2 {
 		candidates = candidates[:2]
 	}
 
-	return candidates
+	reviewers := make([]string, 0, len(candidates))
+	matched := false
+	for _, u := range candidates {
+		reviewers = append(reviewers, u.UserId)
+		if skillOverlap(u, requiredSkills) > 0 {
+			matched = true
+		}
+	}
+
+	return reviewers, matched
 }
 
-func (s *Service) pickReplacementFromTeam(t *team.Team, oldUserID string, currentReviewers []string) (string, bool) {
-	currentSet := make(map[string]struct{}, len(currentReviewers))
-	for _, id := range currentReviewers {
-		currentSet[id] = struct{}{}
+// replacementTeams returns the teams the outgoing reviewer shares with the
+// author of pr, falling back to the team the reviewers of pr were drawn from
+// when they share none.
+func (s *Service) replacementTeams(ctx context.Context, oldUser *user.User, pr *PR) ([]team.Team, error) {
+	reviewerTeams, err := s.teamReader.GetTeamNamesByUserID(ctx, oldUser.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("get old reviewer teams: %w", err)
 	}
 
-	candidates := make([]string, 0, len(t.Members))
-	for _, u := range t.Members {
-		if u == nil {
-			continue
-		}
-		if !u.IsActive {
-			continue
+	authorTeams, err := s.teamReader.GetTeamNamesByUserID(ctx, pr.AuthorId)
+	if err != nil {
+		return nil, fmt.Errorf("get author teams: %w", err)
+	}
+
+	names := make([]string, 0, len(reviewerTeams))
+	for _, name := range reviewerTeams {
+		if slices.Contains(authorTeams, name) {
+			names = append(names, name)
 		}
-		if u.UserId == oldUserID {
-			continue
+	}
+	if len(names) == 0 && pr.TeamName != "" {
+		names = append(names, pr.TeamName)
+	}
+
+	teams := make([]team.Team, 0, len(names))
+	for _, name := range names {
+		t, err := s.teamReader.GetByTeamName(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("get team %s: %w", name, err)
 		}
-		if _, used := currentSet[u.UserId]; used {
-			continue
+		teams = append(teams, t)
+	}
+
+	return teams, nil
+}
+
+func (s *Service) pickReplacementFromTeams(teams []team.Team, pr *PR, oldUserID string, now time.Time) (string, bool) {
+	seen := make(map[string]struct{}, len(pr.AssignedReviewers)+2)
+	for _, id := range pr.AssignedReviewers {
+		seen[id] = struct{}{}
+	}
+	seen[pr.AuthorId] = struct{}{}
+	seen[oldUserID] = struct{}{}
+
+	candidates := make([]string, 0)
+	bestRank := 0
+	for _, t := range teams {
+		for _, u := range t.Members {
+			if u == nil {
+				continue
+			}
+			if !u.CanReview() {
+				continue
+			}
+			if _, used := seen[u.UserId]; used {
+				continue
+			}
+			seen[u.UserId] = struct{}{}
+
+			rank := rankCandidate(u, pr.RequiredSkills, now)
+			if rank > bestRank {
+				bestRank = rank
+				candidates = candidates[:0]
+			}
+			if rank == bestRank {
+				candidates = append(candidates, u.UserId)
+			}
 		}
-		candidates = append(candidates, u.UserId)
 	}
 
 	if len(candidates) == 0 {
@@ -210,3 +628,23 @@ func (s *Service) pickReplacementFromTeam(t *team.Team, oldUserID string, curren
 	idx := s.rand.Intn(len(candidates))
 	return candidates[idx], true
 }
+
+// rankCandidate orders candidates by skill overlap first and by being inside
+// working hours second.
+func rankCandidate(u *user.User, requiredSkills []string, now time.Time) int {
+	rank := 2 * skillOverlap(u, requiredSkills)
+	if u.Schedule.IsWorkingTime(now) {
+		rank++
+	}
+	return rank
+}
+
+func skillOverlap(u *user.User, skills []string) int {
+	n := 0
+	for _, skill := range skills {
+		if u.HasSkill(skill) {
+			n++
+		}
+	}
+	return n
+}
@@ -1,53 +1,211 @@
 package pull_request
 
 import (
+	"InternshipTask/internal/domain/actor"
+	"InternshipTask/internal/domain/audit"
+	"InternshipTask/internal/domain/clock"
+	"InternshipTask/internal/domain/events"
 	"InternshipTask/internal/domain/team"
 	"InternshipTask/internal/domain/user"
 	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 type stubPRRepo struct {
+	mu             sync.Mutex
 	prsByID        map[string]*PR
 	created        *PR
 	updated        *PR
 	reviewerStats  map[string]int64
 	getByReviewerR []PullRequestShort
+	conflicts      int
+	assignments    map[string][]Assignment
+	archiveBatches []int
+	archiveCutoffs []time.Time
+	listFilters    []ListFilter
+	reviews        []Review
+}
+
+func (r *stubPRRepo) ArchiveMerged(_ context.Context, mergedBefore time.Time, limit int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.archiveCutoffs = append(r.archiveCutoffs, mergedBefore)
+	if len(r.archiveBatches) == 0 {
+		return 0, nil
+	}
+	n := min(r.archiveBatches[0], limit)
+	r.archiveBatches = r.archiveBatches[1:]
+	return n, nil
+}
+
+// List honours only the sort, cursor and limit of f.
+func (r *stubPRRepo) List(_ context.Context, f ListFilter) ([]PR, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listFilters = append(r.listFilters, f)
+	ids := slices.Sorted(maps.Keys(r.prsByID))
+	if f.Sort == SortCreatedDesc {
+		slices.Reverse(ids)
+	}
+	result := make([]PR, 0)
+	for _, id := range ids {
+		if f.After != nil && (f.Sort == SortCreatedAsc && id <= f.After.ID || f.Sort == SortCreatedDesc && id >= f.After.ID) {
+			continue
+		}
+		if len(result) == f.Limit {
+			break
+		}
+		result = append(result, *clonePR(r.prsByID[id]))
+	}
+	return result, nil
+}
+
+func (r *stubPRRepo) GetAssignments(_ context.Context, prID string) ([]Assignment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.assignments[prID], nil
 }
 
 func (r *stubPRRepo) Create(_ context.Context, pr *PR) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.created = pr
 	if r.prsByID == nil {
 		r.prsByID = make(map[string]*PR)
 	}
-	r.prsByID[pr.PullRequestId] = pr
+	r.prsByID[pr.PullRequestId] = clonePR(pr)
+	r.recordReviewerChange(pr)
 	return nil
 }
 
 func (r *stubPRRepo) GetByID(_ context.Context, id string) (*PR, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	if pr, ok := r.prsByID[id]; ok {
-		return pr, nil
+		return clonePR(pr), nil
 	}
 	return nil, ErrNotFound
 }
 
 func (r *stubPRRepo) Update(_ context.Context, pr *PR) error {
-	r.updated = pr
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	if r.prsByID == nil {
 		r.prsByID = make(map[string]*PR)
 	}
-	r.prsByID[pr.PullRequestId] = pr
+	if r.conflicts > 0 {
+		r.conflicts--
+		return ErrConflict
+	}
+	if stored, ok := r.prsByID[pr.PullRequestId]; ok && stored.Version != pr.Version {
+		return ErrConflict
+	}
+	pr.Version++
+	r.updated = pr
+	r.prsByID[pr.PullRequestId] = clonePR(pr)
+	r.recordReviewerChange(pr)
 	return nil
 }
 
-func (r *stubPRRepo) GetByReviewerID(_ context.Context, _ string) ([]PullRequestShort, error) {
+// recordReviewerChange keeps the assignment history like a real storage.
+func (r *stubPRRepo) recordReviewerChange(pr *PR) {
+	if r.assignments == nil {
+		r.assignments = make(map[string][]Assignment)
+	}
+	history := r.assignments[pr.PullRequestId]
+	change := pr.ReviewerChange
+	var active []string
+	for i := range history {
+		a := &history[i]
+		switch {
+		case a.UnassignedAt != nil:
+		case slices.Contains(pr.AssignedReviewers, a.ReviewerID):
+			active = append(active, a.ReviewerID)
+		default:
+			a.UnassignedAt = &change.At
+			a.UnassignReason = change.Reason
+		}
+	}
+	for _, reviewerID := range pr.AssignedReviewers {
+		if !slices.Contains(active, reviewerID) {
+			history = append(history, Assignment{ReviewerID: reviewerID, AssignedAt: change.At, AssignedReason: change.Reason})
+		}
+	}
+	r.assignments[pr.PullRequestId] = history
+}
+
+// clonePR mimics a real storage, which never hands out shared pointers.
+func clonePR(pr *PR) *PR {
+	c := *pr
+	c.AssignedReviewers = slices.Clone(pr.AssignedReviewers)
+	c.RequiredSkills = slices.Clone(pr.RequiredSkills)
+	return &c
+}
+
+func (r *stubPRRepo) GetByReviewerID(_ context.Context, _ string, _ bool) ([]PullRequestShort, error) {
 	return r.getByReviewerR, nil
 }
 
-func (r *stubPRRepo) GetReviewerStats(_ context.Context) (map[string]int64, error) {
+func (r *stubPRRepo) GetOpenByAuthorID(_ context.Context, authorID string) ([]PullRequestShort, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []PullRequestShort
+	for _, pr := range r.prsByID {
+		if pr.AuthorId == authorID && pr.Status == OPEN {
+			result = append(result, *NewPullRequestShort(pr.PullRequestId, pr.PullRequestName, pr.AuthorId, pr.Status))
+		}
+	}
+	return result, nil
+}
+
+func (r *stubPRRepo) GetReviewerStats(_ context.Context, _ bool) (map[string]int64, error) {
 	return r.reviewerStats, nil
 }
 
+func (r *stubPRRepo) GetOpenReviewCounts(_ context.Context, reviewerIDs []string) (map[string]int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := make(map[string]int64)
+	for _, pr := range r.prsByID {
+		for _, reviewerID := range pr.AssignedReviewers {
+			if pr.Status == OPEN && slices.Contains(reviewerIDs, reviewerID) {
+				counts[reviewerID]++
+			}
+		}
+	}
+	return counts, nil
+}
+
+func (r *stubPRRepo) ListReviews(_ context.Context, f ReviewFilter) ([]Review, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := slices.Clone(r.reviews)
+	if f.Limit > 0 && len(result) > f.Limit {
+		result = result[:f.Limit]
+	}
+	return result, nil
+}
+
+func (r *stubPRRepo) SetVerdict(_ context.Context, prID, reviewerID string, verdict Verdict, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, a := range r.assignments[prID] {
+		if a.ReviewerID == reviewerID && a.UnassignedAt == nil {
+			r.assignments[prID][i].Verdict = verdict
+			r.assignments[prID][i].VerdictAt = &at
+			return nil
+		}
+	}
+	return ErrNotAssigned
+}
+
 type stubUserReader struct {
 	users map[string]*user.User
 }
@@ -70,6 +228,19 @@ func (s *stubTeamReader) GetByTeamName(_ context.Context, name string) (team.Tea
 	return team.Team{}, team.ErrTeamNotFound
 }
 
+func (s *stubTeamReader) GetTeamNamesByUserID(_ context.Context, userID string) ([]string, error) {
+	names := make([]string, 0)
+	for name, t := range s.teams {
+		for _, u := range t.Members {
+			if u != nil && u.UserId == userID {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	return names, nil
+}
+
 func TestService_CreateAssignsUpToTwoReviewers(t *testing.T) {
 	repo := &stubPRRepo{}
 	userR := &stubUserReader{
@@ -94,7 +265,7 @@ func TestService_CreateAssignsUpToTwoReviewers(t *testing.T) {
 
 	svc := NewService(repo, userR, teamR)
 
-	pr, err := svc.Create(context.Background(), "pr-1", "Test PR", "u1")
+	pr, err := svc.Create(context.Background(), CreateParams{ID: "pr-1", Name: "Test PR", AuthorID: "u1"})
 	if err != nil {
 		t.Fatalf("Create() error = %v", err)
 	}
@@ -112,6 +283,355 @@ func TestService_CreateAssignsUpToTwoReviewers(t *testing.T) {
 	}
 }
 
+func TestService_CreateUsesGivenTeam(t *testing.T) {
+	repo := &stubPRRepo{}
+	userR := &stubUserReader{
+		users: map[string]*user.User{
+			"u1": {UserId: "u1", UserName: "Author", TeamName: "backend", IsActive: true},
+			"u2": {UserId: "u2", UserName: "Alice", TeamName: "backend", IsActive: true},
+			"u3": {UserId: "u3", UserName: "Bob", TeamName: "platform", IsActive: true},
+		},
+	}
+	teamR := &stubTeamReader{
+		teams: map[string]team.Team{
+			"backend": {
+				TeamName: "backend",
+				Members:  map[uint]*user.User{0: userR.users["u1"], 1: userR.users["u2"]},
+			},
+			"platform": {
+				TeamName: "platform",
+				Members:  map[uint]*user.User{0: userR.users["u1"], 1: userR.users["u3"]},
+			},
+		},
+	}
+
+	svc := NewService(repo, userR, teamR)
+
+	pr, err := svc.Create(context.Background(), CreateParams{ID: "pr-1", Name: "Test PR", AuthorID: "u1", TeamName: "platform"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if pr.TeamName != "platform" {
+		t.Fatalf("expected team platform, got %s", pr.TeamName)
+	}
+	if len(pr.AssignedReviewers) != 1 || pr.AssignedReviewers[0] != "u3" {
+		t.Fatalf("expected reviewers [u3], got %v", pr.AssignedReviewers)
+	}
+
+	if _, err := svc.Create(context.Background(), CreateParams{ID: "pr-2", Name: "Test PR", AuthorID: "u2", TeamName: "platform"}); !errors.Is(err, ErrAuthorNotInTeam) {
+		t.Fatalf("expected ErrAuthorNotInTeam, got %v", err)
+	}
+}
+
+func TestService_CreatePrefersSkilledReviewers(t *testing.T) {
+	users := map[string]*user.User{
+		"u1": {UserId: "u1", TeamName: "backend", IsActive: true},
+		"u2": {UserId: "u2", TeamName: "backend", IsActive: true, Skills: []string{"frontend-react"}},
+		"u3": {UserId: "u3", TeamName: "backend", IsActive: true, Skills: []string{"go", "postgres"}},
+		"u4": {UserId: "u4", TeamName: "backend", IsActive: true},
+	}
+	teamR := &stubTeamReader{
+		teams: map[string]team.Team{
+			"backend": {
+				TeamName: "backend",
+				Members:  map[uint]*user.User{0: users["u1"], 1: users["u2"], 2: users["u3"], 3: users["u4"]},
+			},
+		},
+	}
+
+	svc := NewService(&stubPRRepo{}, &stubUserReader{users: users}, teamR)
+
+	for i := 0; i < 20; i++ {
+		pr, err := svc.Create(context.Background(), CreateParams{
+			ID:             fmt.Sprintf("pr-%d", i),
+			Name:           "Test PR",
+			AuthorID:       "u1",
+			RequiredSkills: []string{"Postgres"},
+		})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if pr.SkillFallback {
+			t.Fatalf("expected skill match, got fallback")
+		}
+		if !slices.Contains(pr.AssignedReviewers, "u3") {
+			t.Fatalf("expected skilled reviewer u3 to be assigned, got %v", pr.AssignedReviewers)
+		}
+	}
+
+	pr, err := svc.Create(context.Background(), CreateParams{
+		ID:             "pr-fallback",
+		Name:           "Test PR",
+		AuthorID:       "u1",
+		RequiredSkills: []string{"rust"},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if !pr.SkillFallback || len(pr.AssignedReviewers) != 2 {
+		t.Fatalf("expected fallback with 2 reviewers, got fallback=%v reviewers=%v", pr.SkillFallback, pr.AssignedReviewers)
+	}
+}
+
+func TestService_CreatePrefersReviewersInWorkingHours(t *testing.T) {
+	// Monday 06:00 UTC: 09:00 in Moscow, 02:00 in New York.
+	now := time.Date(2026, time.October, 19, 6, 0, 0, 0, time.UTC)
+	moscow := user.Schedule{TimeZone: "Europe/Moscow", WorkStart: 9 * 60, WorkEnd: 18 * 60}
+	newYork := user.Schedule{TimeZone: "America/New_York", WorkStart: 9 * 60, WorkEnd: 18 * 60}
+
+	users := map[string]*user.User{
+		"author": {UserId: "author", TeamName: "backend", IsActive: true, Schedule: moscow},
+		"awake":  {UserId: "awake", TeamName: "backend", IsActive: true, Schedule: moscow},
+		"night1": {UserId: "night1", TeamName: "backend", IsActive: true, Schedule: newYork},
+		"night2": {UserId: "night2", TeamName: "backend", IsActive: true, Schedule: newYork},
+	}
+	teamR := &stubTeamReader{
+		teams: map[string]team.Team{
+			"backend": {
+				TeamName: "backend",
+				Members:  map[uint]*user.User{0: users["author"], 1: users["awake"], 2: users["night1"], 3: users["night2"]},
+			},
+		},
+	}
+
+	svc := NewService(&stubPRRepo{}, &stubUserReader{users: users}, teamR, WithClock(clock.Fixed(now)))
+
+	for i := 0; i < 20; i++ {
+		pr, err := svc.Create(context.Background(), CreateParams{ID: fmt.Sprintf("pr-%d", i), Name: "Test", AuthorID: "author"})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if !slices.Contains(pr.AssignedReviewers, "awake") {
+			t.Fatalf("expected reviewer inside working hours to be assigned, got %v", pr.AssignedReviewers)
+		}
+		if !pr.CreatedAt.Equal(now) {
+			t.Fatalf("expected createdAt from clock, got %v", pr.CreatedAt)
+		}
+	}
+}
+
+func TestService_ReviewSLACountsWorkingHoursOnly(t *testing.T) {
+	moscow, _ := time.LoadLocation("Europe/Moscow")
+	created := time.Date(2026, time.October, 16, 16, 0, 0, 0, moscow)
+	now := time.Date(2026, time.October, 19, 10, 0, 0, 0, moscow)
+
+	pr := NewPR("pr-1", "Test", "author", OPEN)
+	pr.CreatedAt = &created
+	pr.AssignedReviewers = []string{"u2"}
+
+	userR := &stubUserReader{
+		users: map[string]*user.User{
+			"u2": {UserId: "u2", IsActive: true, Schedule: user.Schedule{TimeZone: "Europe/Moscow", WorkStart: 9 * 60, WorkEnd: 18 * 60}},
+		},
+	}
+	svc := NewService(&stubPRRepo{prsByID: map[string]*PR{"pr-1": pr}}, userR, &stubTeamReader{},
+		WithClock(clock.Fixed(now)),
+		WithReviewSLA(2*time.Hour),
+	)
+
+	sla, err := svc.ReviewSLA(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("ReviewSLA() error = %v", err)
+	}
+	if len(sla.Reviewers) != 1 || sla.Reviewers[0].WorkingTime != 3*time.Hour || !sla.Reviewers[0].Breached {
+		t.Fatalf("unexpected sla: %+v", sla.Reviewers)
+	}
+}
+
+func TestService_ReviewSLAStartsAtAssignment(t *testing.T) {
+	utc := user.Schedule{TimeZone: "UTC", WorkStart: 9 * 60, WorkEnd: 18 * 60}
+	users := map[string]*user.User{
+		"author": {UserId: "author", TeamName: "backend", IsActive: true, Schedule: utc},
+		"u2":     {UserId: "u2", TeamName: "backend", IsActive: true, Schedule: utc},
+		"u3":     {UserId: "u3", TeamName: "backend", IsActive: true, Schedule: utc},
+	}
+	teamR := &stubTeamReader{teams: map[string]team.Team{
+		"backend": {TeamName: "backend", Members: map[uint]*user.User{0: users["author"], 1: users["u2"], 2: users["u3"]}},
+	}}
+	repo := &stubPRRepo{}
+	now := time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC)
+	svc := NewService(repo, &stubUserReader{users: users}, teamR,
+		WithClock(clock.Func(func() time.Time { return now })),
+		WithReviewSLA(4*time.Hour),
+	)
+
+	// Pin the reviewer: u3 is away, so only u2 can be picked.
+	users["u3"].IsActive = false
+	if _, err := svc.Create(context.Background(), CreateParams{ID: "pr-1", Name: "Test", AuthorID: "author"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	users["u3"].IsActive = true
+
+	now = now.Add(5 * time.Hour)
+	if _, replacedBy, err := svc.Reassign(context.Background(), "pr-1", "u2"); err != nil || replacedBy != "u3" {
+		t.Fatalf("Reassign() = %s, %v", replacedBy, err)
+	}
+	now = now.Add(time.Hour)
+
+	sla, err := svc.ReviewSLA(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("ReviewSLA() error = %v", err)
+	}
+	if len(sla.Reviewers) != 1 || sla.Reviewers[0].ReviewerID != "u3" || sla.Reviewers[0].WorkingTime != time.Hour || sla.Reviewers[0].Breached {
+		t.Fatalf("expected u3 to be charged from the reassign only, got %+v", sla.Reviewers)
+	}
+}
+
+func TestService_ReassignUsesTeamsSharedWithAuthor(t *testing.T) {
+	users := map[string]*user.User{
+		"author": {UserId: "author", TeamName: "backend", IsActive: true},
+		"old":    {UserId: "old", TeamName: "platform", IsActive: true},
+		"peer":   {UserId: "peer", TeamName: "backend", IsActive: true},
+		"other":  {UserId: "other", TeamName: "platform", IsActive: true},
+	}
+	pr := NewPR("pr-1", "Test", "author", OPEN)
+	pr.AssignedReviewers = []string{"old"}
+	repo := &stubPRRepo{prsByID: map[string]*PR{"pr-1": pr}}
+	teamR := &stubTeamReader{
+		teams: map[string]team.Team{
+			"backend": {
+				TeamName: "backend",
+				Members:  map[uint]*user.User{0: users["author"], 1: users["old"], 2: users["peer"]},
+			},
+			"platform": {
+				TeamName: "platform",
+				Members:  map[uint]*user.User{0: users["old"], 1: users["other"]},
+			},
+		},
+	}
+
+	svc := NewService(repo, &stubUserReader{users: users}, teamR)
+
+	got, replacedBy, err := svc.Reassign(context.Background(), "pr-1", "old")
+	if err != nil {
+		t.Fatalf("Reassign() error = %v", err)
+	}
+	if replacedBy != "peer" {
+		t.Fatalf("expected replacement from shared team backend, got %s", replacedBy)
+	}
+	if len(got.AssignedReviewers) != 1 || got.AssignedReviewers[0] != "peer" {
+		t.Fatalf("unexpected reviewers: %v", got.AssignedReviewers)
+	}
+}
+
+func TestService_ReassignFallsBackToPRTeam(t *testing.T) {
+	users := map[string]*user.User{
+		"author": {UserId: "author", TeamName: "backend", IsActive: true},
+		"old":    {UserId: "old", TeamName: "platform", IsActive: true},
+		"peer":   {UserId: "peer", TeamName: "backend", IsActive: true},
+		"other":  {UserId: "other", TeamName: "platform", IsActive: true},
+	}
+	// old was drawn from backend and has since left it: they share no team
+	// with the author any more.
+	pr := NewPR("pr-1", "Test", "author", OPEN)
+	pr.TeamName = "backend"
+	pr.AssignedReviewers = []string{"old"}
+	repo := &stubPRRepo{prsByID: map[string]*PR{"pr-1": pr}}
+	teamR := &stubTeamReader{
+		teams: map[string]team.Team{
+			"backend": {
+				TeamName: "backend",
+				Members:  map[uint]*user.User{0: users["author"], 1: users["peer"]},
+			},
+			"platform": {
+				TeamName: "platform",
+				Members:  map[uint]*user.User{0: users["old"], 1: users["other"]},
+			},
+		},
+	}
+
+	svc := NewService(repo, &stubUserReader{users: users}, teamR)
+
+	_, replacedBy, err := svc.Reassign(context.Background(), "pr-1", "old")
+	if err != nil {
+		t.Fatalf("Reassign() error = %v", err)
+	}
+	if replacedBy != "peer" {
+		t.Fatalf("expected replacement from the PR team backend, got %s", replacedBy)
+	}
+}
+
+type stubPublisher struct {
+	published []events.Type
+	payloads  []any
+}
+
+func (p *stubPublisher) Publish(_ context.Context, eventType events.Type, _, _ string, payload any) error {
+	p.published = append(p.published, eventType)
+	p.payloads = append(p.payloads, payload)
+	return nil
+}
+
+func TestService_ReassignPublishesEvents(t *testing.T) {
+	users := map[string]*user.User{
+		"author": {UserId: "author", TeamName: "backend", IsActive: true},
+		"old":    {UserId: "old", TeamName: "backend", IsActive: true},
+		"peer":   {UserId: "peer", TeamName: "backend", IsActive: true},
+	}
+	pr := NewPR("pr-1", "Test", "author", OPEN)
+	pr.AssignedReviewers = []string{"old"}
+	repo := &stubPRRepo{prsByID: map[string]*PR{"pr-1": pr}}
+	teamR := &stubTeamReader{
+		teams: map[string]team.Team{
+			"backend": {
+				TeamName: "backend",
+				Members:  map[uint]*user.User{0: users["author"], 1: users["old"], 2: users["peer"]},
+			},
+		},
+	}
+	pub := &stubPublisher{}
+	svc := NewService(repo, &stubUserReader{users: users}, teamR, WithEvents(pub))
+
+	if _, _, err := svc.Reassign(context.Background(), "pr-1", "old"); err != nil {
+		t.Fatalf("Reassign() error = %v", err)
+	}
+	if _, err := svc.Merge(context.Background(), "pr-1"); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	want := []events.Type{events.PullRequestReviewerAssigned, events.PullRequestReassigned, events.PullRequestMerged}
+	if !slices.Equal(pub.published, want) {
+		t.Fatalf("expected events %v, got %v", want, pub.published)
+	}
+	assigned := events.ReviewerAssignedPayload{PullRequestID: "pr-1", ReviewerID: "peer", Reason: string(ReasonReassigned)}
+	if pub.payloads[0] != assigned {
+		t.Fatalf("expected payload %+v, got %+v", assigned, pub.payloads[0])
+	}
+	reassigned := events.ReassignedPayload{PullRequestID: "pr-1", OldReviewerID: "old", NewReviewerID: "peer"}
+	if pub.payloads[1] != reassigned {
+		t.Fatalf("expected payload %+v, got %+v", reassigned, pub.payloads[1])
+	}
+}
+
+func TestService_ReleaseReviewerDropsSlotWithoutCandidate(t *testing.T) {
+	departed := time.Now()
+	users := map[string]*user.User{
+		"author": {UserId: "author", TeamName: "backend", IsActive: true},
+		"old":    {UserId: "old", TeamName: "backend", IsActive: false, DepartedAt: &departed},
+		"gone":   {UserId: "gone", TeamName: "backend", IsActive: true, DepartedAt: &departed},
+	}
+	pr := NewPR("pr-1", "Test", "author", OPEN)
+	pr.AssignedReviewers = []string{"old"}
+	teamR := &stubTeamReader{
+		teams: map[string]team.Team{
+			"backend": {
+				TeamName: "backend",
+				Members:  map[uint]*user.User{0: users["author"], 1: users["old"], 2: users["gone"]},
+			},
+		},
+	}
+
+	svc := NewService(&stubPRRepo{prsByID: map[string]*PR{"pr-1": pr}}, &stubUserReader{users: users}, teamR)
+
+	got, replacedBy, err := svc.ReleaseReviewer(context.Background(), "pr-1", "old")
+	if err != nil {
+		t.Fatalf("ReleaseReviewer() error = %v", err)
+	}
+	if replacedBy != "" || len(got.AssignedReviewers) != 0 {
+		t.Fatalf("expected slot to be dropped, got replacedBy=%q reviewers=%v", replacedBy, got.AssignedReviewers)
+	}
+}
+
 func TestService_MergeIsIdempotent(t *testing.T) {
 	repo := &stubPRRepo{
 		prsByID: map[string]*PR{
@@ -144,6 +664,61 @@ func TestService_MergeIsIdempotent(t *testing.T) {
 	}
 }
 
+type recordedChange struct {
+	action        audit.Action
+	before, after any
+}
+
+type stubRecorder struct {
+	changes []recordedChange
+}
+
+func (r *stubRecorder) Record(_ context.Context, action audit.Action, _ audit.EntityType, _ string, before, after any) error {
+	r.changes = append(r.changes, recordedChange{action: action, before: before, after: after})
+	return nil
+}
+
+func TestService_MergeRecordsAuditEntryOnce(t *testing.T) {
+	repo := &stubPRRepo{
+		prsByID: map[string]*PR{
+			"pr-1": NewPR("pr-1", "Test", "u1", OPEN),
+		},
+	}
+	rec := &stubRecorder{}
+	svc := NewService(repo, &stubUserReader{}, &stubTeamReader{}, WithAuditLog(rec))
+
+	for range 2 {
+		if _, err := svc.Merge(context.Background(), "pr-1"); err != nil {
+			t.Fatalf("Merge() error = %v", err)
+		}
+	}
+
+	if len(rec.changes) != 1 || rec.changes[0].action != audit.ActionPRMerge {
+		t.Fatalf("expected a single merge entry, got %+v", rec.changes)
+	}
+	before, after := rec.changes[0].before.(*PR), rec.changes[0].after.(*PR)
+	if before.Status != OPEN || after.Status != MERGED {
+		t.Fatalf("expected OPEN -> MERGED, got %s -> %s", before.Status, after.Status)
+	}
+}
+
+func TestRetention_ArchivesInBatchesUntilDone(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := &stubPRRepo{archiveBatches: []int{retentionBatchSize, retentionBatchSize, 7}}
+	svc := NewService(repo, &stubUserReader{}, &stubTeamReader{}, WithClock(clock.Fixed(now)))
+
+	n, err := NewRetention(svc, 30*24*time.Hour, 0).RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if n != 2*retentionBatchSize+7 {
+		t.Fatalf("expected %d archived, got %d", 2*retentionBatchSize+7, n)
+	}
+	if len(repo.archiveCutoffs) != 3 || !repo.archiveCutoffs[0].Equal(now.Add(-30*24*time.Hour)) {
+		t.Fatalf("expected 3 batches with a 30 day cutoff, got %v", repo.archiveCutoffs)
+	}
+}
+
 func TestService_ReviewerStats(t *testing.T) {
 	repo := &stubPRRepo{
 		reviewerStats: map[string]int64{
@@ -156,7 +731,7 @@ func TestService_ReviewerStats(t *testing.T) {
 
 	svc := NewService(repo, userR, teamR)
 
-	stats, err := svc.ReviewerStats(context.Background())
+	stats, err := svc.ReviewerStats(context.Background(), false)
 	if err != nil {
 		t.Fatalf("ReviewerStats() error = %v", err)
 	}
@@ -164,3 +739,315 @@ func TestService_ReviewerStats(t *testing.T) {
 		t.Fatalf("unexpected stats: %+v", stats)
 	}
 }
+
+func TestService_ConcurrentCreateReassignMerge(t *testing.T) {
+	users := make(map[string]*user.User)
+	members := make(map[uint]*user.User)
+	for i := 0; i < 6; i++ {
+		id := fmt.Sprintf("u%d", i)
+		users[id] = &user.User{UserId: id, TeamName: "backend", IsActive: true}
+		members[uint(i)] = users[id]
+	}
+	teamR := &stubTeamReader{teams: map[string]team.Team{"backend": {TeamName: "backend", Members: members}}}
+	repo := &stubPRRepo{}
+	svc := NewService(repo, &stubUserReader{users: users}, teamR)
+
+	shared, err := svc.Create(context.Background(), CreateParams{ID: "pr-shared", Name: "Shared", AuthorID: "u0"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	const workers = 32
+	var (
+		wg           sync.WaitGroup
+		sharedMerged atomic.Bool
+	)
+	errs := make(chan error, workers*4)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+			ctx := context.Background()
+			id := fmt.Sprintf("pr-%d", i)
+
+			pr, err := svc.Create(ctx, CreateParams{ID: id, Name: "Load", AuthorID: fmt.Sprintf("u%d", i%6)})
+			if err != nil {
+				errs <- fmt.Errorf("create %s: %w", id, err)
+				return
+			}
+			if len(pr.AssignedReviewers) > 0 {
+				if _, _, err := svc.Reassign(ctx, id, pr.AssignedReviewers[0]); err != nil && !errors.Is(err, ErrNoCandidate) {
+					errs <- fmt.Errorf("reassign %s: %w", id, err)
+					return
+				}
+			}
+			if _, err := svc.Merge(ctx, id); err != nil {
+				errs <- fmt.Errorf("merge %s: %w", id, err)
+			}
+		}(i)
+
+		go func(i int) {
+			defer wg.Done()
+			ctx := context.Background()
+
+			if i%8 == 7 {
+				_, err := svc.Merge(ctx, shared.PullRequestId)
+				if err == nil {
+					sharedMerged.Store(true)
+				} else if !errors.Is(err, ErrConflict) {
+					errs <- fmt.Errorf("merge shared: %w", err)
+				}
+				return
+			}
+			_, _, err := svc.Reassign(ctx, shared.PullRequestId, shared.AssignedReviewers[i%len(shared.AssignedReviewers)])
+			if err != nil && !errors.Is(err, ErrPRMerged) && !errors.Is(err, ErrNotAssigned) && !errors.Is(err, ErrNoCandidate) && !errors.Is(err, ErrConflict) {
+				errs <- fmt.Errorf("reassign shared: %w", err)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	for i := 0; i < workers; i++ {
+		pr, err := repo.GetByID(context.Background(), fmt.Sprintf("pr-%d", i))
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		if pr.Status != MERGED {
+			t.Fatalf("expected %s to be merged, got %s", pr.PullRequestId, pr.Status)
+		}
+		if slices.Contains(pr.AssignedReviewers, pr.AuthorId) {
+			t.Fatalf("author assigned as reviewer on %s", pr.PullRequestId)
+		}
+	}
+
+	got, err := repo.GetByID(context.Background(), shared.PullRequestId)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if sharedMerged.Load() && got.Status != MERGED {
+		t.Fatalf("a concurrent reassign undid the merge of %s", shared.PullRequestId)
+	}
+}
+
+func TestService_ReassignRetriesOnConflict(t *testing.T) {
+	users := map[string]*user.User{
+		"author": {UserId: "author", TeamName: "backend", IsActive: true},
+		"r1":     {UserId: "r1", TeamName: "backend", IsActive: true},
+		"r2":     {UserId: "r2", TeamName: "backend", IsActive: true},
+	}
+	teamR := &stubTeamReader{teams: map[string]team.Team{
+		"backend": {TeamName: "backend", Members: map[uint]*user.User{0: users["author"], 1: users["r1"], 2: users["r2"]}},
+	}}
+	repo := &stubPRRepo{prsByID: map[string]*PR{
+		"pr-1": {PullRequestId: "pr-1", AuthorId: "author", Status: OPEN, AssignedReviewers: []string{"r1"}},
+	}}
+	svc := NewService(repo, &stubUserReader{users: users}, teamR, WithMaxRetries(2))
+
+	repo.conflicts = 2
+	pr, replacedBy, err := svc.Reassign(context.Background(), "pr-1", "r1")
+	if err != nil {
+		t.Fatalf("Reassign() error = %v", err)
+	}
+	if replacedBy != "r2" || pr.AssignedReviewers[0] != "r2" {
+		t.Fatalf("expected r1 to be replaced by r2, got %v", pr.AssignedReviewers)
+	}
+
+	repo.conflicts = 3
+	if _, err := svc.Merge(context.Background(), "pr-1"); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict once retries are exhausted, got %v", err)
+	}
+}
+
+func TestService_ReassignRecordsReviewerChange(t *testing.T) {
+	users := map[string]*user.User{
+		"author": {UserId: "author", TeamName: "backend", IsActive: true},
+		"r1":     {UserId: "r1", TeamName: "backend", IsActive: true},
+		"r2":     {UserId: "r2", TeamName: "backend", IsActive: true},
+	}
+	teamR := &stubTeamReader{teams: map[string]team.Team{
+		"backend": {TeamName: "backend", Members: map[uint]*user.User{0: users["author"], 1: users["r1"], 2: users["r2"]}},
+	}}
+	repo := &stubPRRepo{}
+	now := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+	svc := NewService(repo, &stubUserReader{users: users}, teamR, WithClock(clock.Fixed(now)))
+
+	ctx := actor.WithID(context.Background(), "lead")
+	pr, err := svc.Create(ctx, CreateParams{ID: "pr-1", Name: "Test", AuthorID: "author"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if pr.ReviewerChange != (ReviewerChange{Reason: ReasonCreated, Actor: "lead", At: now}) {
+		t.Fatalf("unexpected change on create: %+v", pr.ReviewerChange)
+	}
+
+	pr.AssignedReviewers = []string{"r1"}
+	repo.prsByID["pr-1"] = clonePR(pr)
+
+	if _, _, err := svc.Reassign(ctx, "pr-1", "r1"); err != nil {
+		t.Fatalf("Reassign() error = %v", err)
+	}
+	if repo.updated.ReviewerChange != (ReviewerChange{Reason: ReasonReassigned, Actor: "lead", At: now}) {
+		t.Fatalf("unexpected change on reassign: %+v", repo.updated.ReviewerChange)
+	}
+}
+
+func TestService_GetAssignmentsReturnsNotFoundForUnknownPR(t *testing.T) {
+	svc := NewService(&stubPRRepo{}, &stubUserReader{}, &stubTeamReader{})
+
+	if _, err := svc.GetAssignments(context.Background(), "missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestService_ListPaginatesWithCursor(t *testing.T) {
+	repo := &stubPRRepo{prsByID: map[string]*PR{}}
+	for _, id := range []string{"pr-1", "pr-2", "pr-3"} {
+		repo.prsByID[id] = NewPR(id, id, "author", OPEN)
+	}
+	svc := NewService(repo, &stubUserReader{}, &stubTeamReader{})
+	ctx := context.Background()
+
+	page, err := svc.List(ctx, ListFilter{Sort: SortCreatedAsc, Limit: 2})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(page.Items) != 2 || page.Items[1].PullRequestId != "pr-2" || page.Next == nil {
+		t.Fatalf("unexpected first page: %+v", page)
+	}
+	if got := repo.listFilters[0].Limit; got != 3 {
+		t.Fatalf("expected the storage to be asked for one extra row, got limit %d", got)
+	}
+
+	next, err := ParseCursor(page.Next.String())
+	if err != nil {
+		t.Fatalf("ParseCursor() error = %v", err)
+	}
+	page, err = svc.List(ctx, ListFilter{Sort: SortCreatedAsc, Limit: 2, After: &next})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].PullRequestId != "pr-3" || page.Next != nil {
+		t.Fatalf("unexpected last page: %+v", page)
+	}
+
+	if _, err := svc.List(ctx, ListFilter{After: &next}); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor for a cursor of another sort, got %v", err)
+	}
+	if _, err := svc.List(ctx, ListFilter{Status: "CLOSED"}); !errors.Is(err, ErrInvalidFilter) {
+		t.Fatalf("expected ErrInvalidFilter for an unknown status, got %v", err)
+	}
+	if _, err := ParseCursor("not a cursor"); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor for garbage, got %v", err)
+	}
+}
+
+func TestService_GetDetailsExpandsParticipants(t *testing.T) {
+	users := map[string]*user.User{
+		"author": {UserId: "author", UserName: "Author", TeamName: "backend", IsActive: true},
+		"r1":     {UserId: "r1", UserName: "Alice", TeamName: "backend", IsActive: false},
+	}
+	repo := &stubPRRepo{prsByID: map[string]*PR{
+		"pr-1": {PullRequestId: "pr-1", AuthorId: "author", Status: OPEN, AssignedReviewers: []string{"r1"}},
+		"pr-2": {PullRequestId: "pr-2", AuthorId: "author", Status: OPEN, AssignedReviewers: []string{"r1"}},
+		"pr-3": {PullRequestId: "pr-3", AuthorId: "r1", Status: MERGED, AssignedReviewers: []string{"author"}},
+	}}
+	svc := NewService(repo, &stubUserReader{users: users}, &stubTeamReader{})
+	ctx := context.Background()
+
+	d, err := svc.GetDetails(ctx, "pr-1", Expand{})
+	if err != nil {
+		t.Fatalf("GetDetails() error = %v", err)
+	}
+	if d.PR.PullRequestId != "pr-1" || d.Author != nil || d.Reviewers != nil {
+		t.Fatalf("expected a bare PR without expand, got %+v", d)
+	}
+
+	d, err = svc.GetDetails(ctx, "pr-1", Expand{Author: true, Reviewers: true})
+	if err != nil {
+		t.Fatalf("GetDetails() error = %v", err)
+	}
+	if d.Author == nil || d.Author.User.UserName != "Author" || d.Author.OpenReviews != 0 {
+		t.Fatalf("unexpected author: %+v", d.Author)
+	}
+	if len(d.Reviewers) != 1 || d.Reviewers[0].User.UserId != "r1" || d.Reviewers[0].OpenReviews != 2 {
+		t.Fatalf("unexpected reviewers: %+v", d.Reviewers)
+	}
+
+	if _, err := svc.GetDetails(ctx, "missing", Expand{Author: true}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestService_ListReviewsComputesAge(t *testing.T) {
+	now := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+	mergedAt := now.Add(-time.Hour)
+	repo := &stubPRRepo{reviews: []Review{
+		{PullRequestShort: PullRequestShort{PullRequestId: "pr-1", Status: OPEN}, AssignedAt: now.Add(-3 * time.Hour)},
+		{PullRequestShort: PullRequestShort{PullRequestId: "pr-2", Status: MERGED}, AssignedAt: now.Add(-3 * time.Hour), MergedAt: &mergedAt},
+	}}
+	svc := NewService(repo, &stubUserReader{}, &stubTeamReader{}, WithClock(clock.Fixed(now)))
+
+	page, err := svc.ListReviews(context.Background(), ReviewFilter{ReviewerID: "r1", Limit: 1})
+	if err != nil {
+		t.Fatalf("ListReviews() error = %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].Age != 3*time.Hour || page.Next == nil || page.Next.ID != "pr-1" {
+		t.Fatalf("unexpected first page: %+v", page)
+	}
+
+	page, err = svc.ListReviews(context.Background(), ReviewFilter{ReviewerID: "r1"})
+	if err != nil {
+		t.Fatalf("ListReviews() error = %v", err)
+	}
+	if len(page.Items) != 2 || page.Items[1].Age != 2*time.Hour || page.Next != nil {
+		t.Fatalf("expected the age of a merged PR to stop at the merge, got %+v", page)
+	}
+}
+
+func TestService_SetVerdict(t *testing.T) {
+	now := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+	repo := &stubPRRepo{
+		prsByID: map[string]*PR{
+			"pr-1": {PullRequestId: "pr-1", Status: OPEN, AssignedReviewers: []string{"r1"}},
+			"pr-2": {PullRequestId: "pr-2", Status: MERGED, AssignedReviewers: []string{"r1"}},
+		},
+		assignments: map[string][]Assignment{
+			"pr-1": {{ReviewerID: "r1", AssignedAt: now, Verdict: VerdictPending}},
+		},
+	}
+	svc := NewService(repo, &stubUserReader{}, &stubTeamReader{}, WithClock(clock.Fixed(now)))
+	ctx := context.Background()
+
+	a, err := svc.SetVerdict(ctx, "pr-1", "r1", VerdictApproved)
+	if err != nil {
+		t.Fatalf("SetVerdict() error = %v", err)
+	}
+	if a.Verdict != VerdictApproved || a.VerdictAt == nil || !a.VerdictAt.Equal(now) {
+		t.Fatalf("unexpected assignment: %+v", a)
+	}
+	if got := repo.assignments["pr-1"][0].Verdict; got != VerdictApproved {
+		t.Fatalf("expected the verdict to be stored, got %q", got)
+	}
+
+	for _, tc := range []struct {
+		prID, reviewerID string
+		verdict          Verdict
+		want             error
+	}{
+		{"pr-1", "r1", VerdictPending, ErrInvalidVerdict},
+		{"pr-1", "r2", VerdictApproved, ErrNotAssigned},
+		{"pr-2", "r1", VerdictApproved, ErrPRMerged},
+		{"missing", "r1", VerdictApproved, ErrNotFound},
+	} {
+		if _, err := svc.SetVerdict(ctx, tc.prID, tc.reviewerID, tc.verdict); !errors.Is(err, tc.want) {
+			t.Fatalf("SetVerdict(%s, %s, %s): expected %v, got %v", tc.prID, tc.reviewerID, tc.verdict, tc.want, err)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package pull_request
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type ReviewerSLA struct {
+	ReviewerID  string
+	TimeZone    string
+	WorkingTime time.Duration
+	Breached    bool
+}
+
+type ReviewSLA struct {
+	PullRequestID string
+	Target        time.Duration
+	Reviewers     []ReviewerSLA
+}
+
+// ReviewSLA reports how many working hours each current reviewer has spent on
+// the PR, counted in the reviewer's own time zone and working hours from their
+// assignment until the merge or until now for open PRs.
+func (s *Service) ReviewSLA(ctx context.Context, prID string) (*ReviewSLA, error) {
+	pr, err := s.repo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	end := s.clock.Now()
+	if pr.MergedAt != nil {
+		end = *pr.MergedAt
+	}
+	// Reviewers without a recorded assignment are charged from the creation.
+	created := end
+	if pr.CreatedAt != nil {
+		created = *pr.CreatedAt
+	}
+
+	history, err := s.repo.GetAssignments(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("get assignments: %w", err)
+	}
+	assignedAt := make(map[string]time.Time, len(history))
+	for _, a := range history {
+		if a.UnassignedAt == nil {
+			assignedAt[a.ReviewerID] = a.AssignedAt
+		}
+	}
+
+	result := &ReviewSLA{
+		PullRequestID: pr.PullRequestId,
+		Target:        s.reviewSLA,
+		Reviewers:     make([]ReviewerSLA, 0, len(pr.AssignedReviewers)),
+	}
+
+	for _, reviewerID := range pr.AssignedReviewers {
+		reviewer, err := s.userReader.GetByID(ctx, reviewerID)
+		if err != nil {
+			return nil, fmt.Errorf("get reviewer %s: %w", reviewerID, err)
+		}
+
+		start, ok := assignedAt[reviewerID]
+		if !ok {
+			start = created
+		}
+		worked := reviewer.Schedule.WorkingDuration(start, end)
+		result.Reviewers = append(result.Reviewers, ReviewerSLA{
+			ReviewerID:  reviewerID,
+			TimeZone:    reviewer.Schedule.TimeZone,
+			WorkingTime: worked,
+			Breached:    worked > s.reviewSLA,
+		})
+	}
+
+	return result, nil
+}
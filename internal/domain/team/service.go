@@ -1,27 +1,162 @@
 package team
 
-import "context"
+import (
+	"InternshipTask/internal/domain/audit"
+	"InternshipTask/internal/domain/txn"
+	"InternshipTask/internal/domain/user"
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+)
 
 type Storager interface {
+	// Create fails with ErrTeamExists if the team is already there; members
+	// that already exist as users are updated in place.
 	Create(ctx context.Context, team Team) error
 	GetByTeamName(ctx context.Context, teamName string) (Team, error)
+	AddMember(ctx context.Context, teamName, userID string) error
+	RemoveMember(ctx context.Context, teamName, userID string) error
+	GetTeamNamesByUserID(ctx context.Context, userID string) ([]string, error)
+	// ListTeamNames returns the names of all teams in order.
+	ListTeamNames(ctx context.Context) ([]string, error)
 }
 type Service struct {
 	storage Storager
+	tx      txn.Manager
+	audit   audit.Recorder
 	Teams   []Team
 }
 
-func NewService(storage Storager) *Service {
-	return &Service{
+type Option func(*Service)
+
+func WithTxManager(m txn.Manager) Option {
+	return func(s *Service) {
+		s.tx = m
+	}
+}
+
+// WithAuditLog records every change of a team and its memberships in r.
+func WithAuditLog(r audit.Recorder) Option {
+	return func(s *Service) {
+		s.audit = r
+	}
+}
+
+func NewService(storage Storager, opts ...Option) *Service {
+	s := &Service{
 		storage: storage,
+		tx:      txn.Nop{},
+		audit:   audit.Nop{},
 		Teams:   make([]Team, 0, 2),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
+// Create stores the team together with all its members in one unit of work,
+// so a failing member never leaves a half-created team behind. It fails with
+// ErrTeamExists if the team name is taken.
 func (s *Service) Create(ctx context.Context, team Team) error {
-	return s.storage.Create(ctx, team)
+	return s.mutate(ctx, team.TeamName, audit.ActionTeamCreate, func(ctx context.Context) error {
+		return s.storage.Create(ctx, team)
+	})
 }
 
 func (s *Service) GetByTeamName(ctx context.Context, teamName string) (Team, error) {
 	return s.storage.GetByTeamName(ctx, teamName)
 }
+
+func (s *Service) AddMember(ctx context.Context, teamName, userID string) error {
+	return s.mutate(ctx, teamName, audit.ActionTeamAddMember, func(ctx context.Context) error {
+		return s.storage.AddMember(ctx, teamName, userID)
+	})
+}
+
+func (s *Service) RemoveMember(ctx context.Context, teamName, userID string) error {
+	return s.mutate(ctx, teamName, audit.ActionTeamRemoveMember, func(ctx context.Context) error {
+		return s.storage.RemoveMember(ctx, teamName, userID)
+	})
+}
+
+// List returns all teams with their members, ordered by name, read in one
+// unit of work.
+func (s *Service) List(ctx context.Context) ([]Team, error) {
+	var teams []Team
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		names, err := s.storage.ListTeamNames(ctx)
+		if err != nil {
+			return err
+		}
+
+		teams = make([]Team, 0, len(names))
+		for _, name := range names {
+			t, err := s.storage.GetByTeamName(ctx, name)
+			if err != nil {
+				return err
+			}
+			teams = append(teams, t)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return teams, nil
+}
+
+func (s *Service) GetTeamNamesByUserID(ctx context.Context, userID string) ([]string, error) {
+	return s.storage.GetTeamNamesByUserID(ctx, userID)
+}
+
+// auditState is how a team is written to the audit log: members as a list
+// rather than the positional map of Team.
+type auditState struct {
+	TeamName string       `json:"team_name"`
+	Members  []*user.User `json:"members"`
+}
+
+// mutate runs change as a unit of work and records the team's state before
+// and after it in the audit log. The state before is null for a new team.
+func (s *Service) mutate(ctx context.Context, teamName string, action audit.Action, change func(ctx context.Context) error) error {
+	return s.tx.Do(ctx, func(ctx context.Context) error {
+		before, err := s.auditState(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		if err := change(ctx); err != nil {
+			return err
+		}
+
+		after, err := s.auditState(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, action, audit.EntityTeam, teamName, before, after)
+	})
+}
+
+func (s *Service) auditState(ctx context.Context, teamName string) (*auditState, error) {
+	t, err := s.storage.GetByTeamName(ctx, teamName)
+	if errors.Is(err, ErrTeamNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]*user.User, 0, len(t.Members))
+	for _, m := range t.Members {
+		members = append(members, m)
+	}
+	slices.SortFunc(members, func(a, b *user.User) int {
+		return cmp.Compare(a.UserId, b.UserId)
+	})
+
+	return &auditState{TeamName: t.TeamName, Members: members}, nil
+}
@@ -20,6 +20,22 @@ func (s *stubStorage) GetByTeamName(_ context.Context, _ string) (Team, error) {
 	return Team{}, nil
 }
 
+func (s *stubStorage) AddMember(_ context.Context, _, _ string) error {
+	return nil
+}
+
+func (s *stubStorage) RemoveMember(_ context.Context, _, _ string) error {
+	return nil
+}
+
+func (s *stubStorage) GetTeamNamesByUserID(_ context.Context, _ string) ([]string, error) {
+	return nil, nil
+}
+
+func (s *stubStorage) ListTeamNames(_ context.Context) ([]string, error) {
+	return nil, nil
+}
+
 func TestService_CreateDelegatesToStorage(t *testing.T) {
 	storage := &stubStorage{}
 	svc := NewService(storage)
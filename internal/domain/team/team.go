@@ -6,7 +6,10 @@ import (
 )
 
 var (
-	ErrTeamNotFound = errors.New("team not found")
+	ErrTeamNotFound      = errors.New("team not found")
+	ErrTeamExists        = errors.New("team already exists")
+	ErrNotMember         = errors.New("user is not a member of the team")
+	ErrPrimaryMembership = errors.New("cannot remove user from primary team")
 )
 
 type Team struct {
@@ -0,0 +1,72 @@
+package txn
+
+import (
+	"context"
+	"sync"
+)
+
+// Manager runs fn as a single unit of work: either every repository call made
+// with the ctx passed to fn takes effect, or none does. Calling Do again with
+// that ctx joins the outer unit of work instead of starting a new one.
+type Manager interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Nop runs fn without any transactional guarantees.
+type Nop struct{}
+
+func (Nop) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type journalKey struct{}
+
+type journal struct {
+	owner *Memory
+	undo  []func()
+}
+
+// Memory is a Manager for in-memory storages. Units of work are serialized,
+// and storages register undo steps with OnRollback that are replayed in
+// reverse order when fn fails or panics.
+type Memory struct {
+	mu sync.Mutex
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (m *Memory) Do(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if j, ok := ctx.Value(journalKey{}).(*journal); ok && j.owner == m {
+		return fn(ctx)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j := &journal{owner: m}
+	committed := false
+	defer func() {
+		if !committed {
+			for i := len(j.undo) - 1; i >= 0; i-- {
+				j.undo[i]()
+			}
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, journalKey{}, j)); err != nil {
+		return err
+	}
+	committed = true
+
+	return nil
+}
+
+// OnRollback registers undo to run if the unit of work in ctx is rolled back.
+// Outside of a Memory unit of work it does nothing.
+func OnRollback(ctx context.Context, undo func()) {
+	if j, ok := ctx.Value(journalKey{}).(*journal); ok {
+		j.undo = append(j.undo, undo)
+	}
+}
@@ -0,0 +1,65 @@
+package txn
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMemory_RollsBackInReverseOrderOnError(t *testing.T) {
+	m := NewMemory()
+	state := []string{"a"}
+
+	errBoom := errors.New("boom")
+	err := m.Do(context.Background(), func(ctx context.Context) error {
+		state = append(state, "b")
+		OnRollback(ctx, func() { state = state[:len(state)-1] })
+
+		return m.Do(ctx, func(ctx context.Context) error {
+			state = append(state, "c")
+			OnRollback(ctx, func() { state = state[:len(state)-1] })
+			return errBoom
+		})
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("expected errBoom, got %v", err)
+	}
+	if len(state) != 1 || state[0] != "a" {
+		t.Fatalf("expected state to be rolled back, got %v", state)
+	}
+}
+
+func TestMemory_KeepsChangesOnSuccess(t *testing.T) {
+	m := NewMemory()
+	state := 0
+
+	err := m.Do(context.Background(), func(ctx context.Context) error {
+		state++
+		OnRollback(ctx, func() { state-- })
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if state != 1 {
+		t.Fatalf("expected state 1, got %d", state)
+	}
+}
+
+func TestMemory_RollsBackOnPanic(t *testing.T) {
+	m := NewMemory()
+	state := 0
+
+	func() {
+		defer func() { _ = recover() }()
+		_ = m.Do(context.Background(), func(ctx context.Context) error {
+			state++
+			OnRollback(ctx, func() { state-- })
+			panic("boom")
+		})
+	}()
+
+	if state != 0 {
+		t.Fatalf("expected state to be rolled back, got %d", state)
+	}
+}
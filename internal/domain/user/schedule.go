@@ -0,0 +1,120 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidSchedule = errors.New("invalid schedule")
+)
+
+const minutesPerDay = 24 * 60
+
+// Schedule describes when a user is available for reviews: working hours in
+// their IANA time zone, Monday to Friday. Minutes are counted from local midnight.
+type Schedule struct {
+	TimeZone  string `json:"time_zone"`
+	WorkStart int    `json:"work_start_minute"`
+	WorkEnd   int    `json:"work_end_minute"`
+}
+
+func DefaultSchedule() Schedule {
+	return Schedule{
+		TimeZone:  "UTC",
+		WorkStart: 9 * 60,
+		WorkEnd:   18 * 60,
+	}
+}
+
+func (s Schedule) IsZero() bool {
+	return s == Schedule{}
+}
+
+func (s Schedule) Validate() error {
+	if _, err := time.LoadLocation(s.TimeZone); err != nil || s.TimeZone == "" {
+		return fmt.Errorf("%w: unknown time zone %q", ErrInvalidSchedule, s.TimeZone)
+	}
+	if s.WorkStart < 0 || s.WorkEnd > minutesPerDay || s.WorkStart >= s.WorkEnd {
+		return fmt.Errorf("%w: working hours must satisfy 00:00 <= start < end <= 24:00", ErrInvalidSchedule)
+	}
+	return nil
+}
+
+// IsWorkingTime reports whether t falls inside the user's working hours.
+func (s Schedule) IsWorkingTime(t time.Time) bool {
+	s = s.orDefault()
+	local := t.In(s.location())
+	if !isWorkday(local.Weekday()) {
+		return false
+	}
+	minute := local.Hour()*60 + local.Minute()
+	return minute >= s.WorkStart && minute < s.WorkEnd
+}
+
+// WorkingDuration returns how much of [from, to) falls inside working hours.
+func (s Schedule) WorkingDuration(from, to time.Time) time.Duration {
+	if !to.After(from) {
+		return 0
+	}
+	s = s.orDefault()
+	loc := s.location()
+
+	var total time.Duration
+	local := from.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	for !day.After(to) {
+		if isWorkday(day.Weekday()) {
+			start := time.Date(day.Year(), day.Month(), day.Day(), 0, s.WorkStart, 0, 0, loc)
+			end := time.Date(day.Year(), day.Month(), day.Day(), 0, s.WorkEnd, 0, 0, loc)
+			if start.Before(from) {
+				start = from
+			}
+			if end.After(to) {
+				end = to
+			}
+			if end.After(start) {
+				total += end.Sub(start)
+			}
+		}
+		day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc)
+	}
+
+	return total
+}
+
+func (s Schedule) orDefault() Schedule {
+	if s.IsZero() {
+		return DefaultSchedule()
+	}
+	return s
+}
+
+func (s Schedule) location() *time.Location {
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+func isWorkday(d time.Weekday) bool {
+	return d != time.Saturday && d != time.Sunday
+}
+
+// ParseTimeOfDay parses "HH:MM" into minutes since midnight; "24:00" is allowed as an end of day.
+func ParseTimeOfDay(v string) (int, error) {
+	var h, m int
+	if _, err := fmt.Sscanf(v, "%d:%d", &h, &m); err != nil || len(v) != 5 {
+		return 0, fmt.Errorf("%w: time %q must be in HH:MM format", ErrInvalidSchedule, v)
+	}
+	if h < 0 || h > 24 || m < 0 || m > 59 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("%w: time %q is out of range", ErrInvalidSchedule, v)
+	}
+	return h*60 + m, nil
+}
+
+func FormatTimeOfDay(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
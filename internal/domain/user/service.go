@@ -1,22 +1,71 @@
 package user
 
 import (
+	"InternshipTask/internal/domain/audit"
+	"InternshipTask/internal/domain/clock"
+	"InternshipTask/internal/domain/events"
+	"InternshipTask/internal/domain/txn"
 	"context"
+	"time"
 )
 
 type Storager interface {
 	GetByID(ctx context.Context, id string) (*User, error)
 	SetIsActive(ctx context.Context, id string, isActive bool) (*User, error)
+	SetSkills(ctx context.Context, id string, skills []string) (*User, error)
+	SetSchedule(ctx context.Context, id string, schedule Schedule) (*User, error)
+	MarkDeparted(ctx context.Context, id string, at time.Time) (*User, error)
 }
 
 type Service struct {
 	storage Storager
+	clock   clock.Clock
+	tx      txn.Manager
+	audit   audit.Recorder
+	events  events.Publisher
 }
 
-func NewService(storage Storager) *Service {
-	return &Service{
+type Option func(*Service)
+
+func WithClock(c clock.Clock) Option {
+	return func(s *Service) {
+		s.clock = c
+	}
+}
+
+func WithTxManager(m txn.Manager) Option {
+	return func(s *Service) {
+		s.tx = m
+	}
+}
+
+// WithAuditLog records every change of a user in r.
+func WithAuditLog(r audit.Recorder) Option {
+	return func(s *Service) {
+		s.audit = r
+	}
+}
+
+// WithEvents publishes user.activity_changed through p whenever a user is
+// activated, deactivated or departs.
+func WithEvents(p events.Publisher) Option {
+	return func(s *Service) {
+		s.events = p
+	}
+}
+
+func NewService(storage Storager, opts ...Option) *Service {
+	s := &Service{
 		storage: storage,
+		clock:   clock.Real{},
+		tx:      txn.Nop{},
+		audit:   audit.Nop{},
+		events:  events.Nop{},
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 func (s *Service) GetByID(ctx context.Context, id string) (*User, error) {
@@ -24,5 +73,115 @@ func (s *Service) GetByID(ctx context.Context, id string) (*User, error) {
 }
 
 func (s *Service) SetIsActive(ctx context.Context, id string, isActive bool) (*User, error) {
-	return s.storage.SetIsActive(ctx, id, isActive)
+	return s.mutate(ctx, id, audit.ActionUserSetIsActive, func(ctx context.Context, before *User) (*User, error) {
+		if isActive && before.IsDeparted() {
+			return nil, ErrUserDeparted
+		}
+		return s.storage.SetIsActive(ctx, id, isActive)
+	})
+}
+
+// MarkDeparted deactivates the user for good: departed users can never be
+// reactivated or assigned as reviewers again.
+func (s *Service) MarkDeparted(ctx context.Context, id string) (*User, error) {
+	return s.mutate(ctx, id, audit.ActionUserDepart, func(ctx context.Context, _ *User) (*User, error) {
+		return s.storage.MarkDeparted(ctx, id, s.clock.Now())
+	})
+}
+
+func (s *Service) SetSkills(ctx context.Context, id string, skills []string) (*User, error) {
+	normalized, err := NormalizeSkills(skills)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mutate(ctx, id, audit.ActionUserSetSkills, func(ctx context.Context, _ *User) (*User, error) {
+		return s.storage.SetSkills(ctx, id, normalized)
+	})
+}
+
+func (s *Service) SetSchedule(ctx context.Context, id string, schedule Schedule) (*User, error) {
+	if err := schedule.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.mutate(ctx, id, audit.ActionUserSetSchedule, func(ctx context.Context, _ *User) (*User, error) {
+		return s.storage.SetSchedule(ctx, id, schedule)
+	})
+}
+
+// Update is a partial change of a user: nil fields are left as they are. An
+// empty, non-nil Skills clears the skills.
+type Update struct {
+	IsActive *bool
+	Skills   []string
+	Schedule *Schedule
+}
+
+// Update applies u in one unit of work, so a failing field leaves the user
+// untouched. Each changed field is audited as by its own setter.
+func (s *Service) Update(ctx context.Context, id string, u Update) (*User, error) {
+	var result *User
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if result, err = s.storage.GetByID(ctx, id); err != nil {
+			return err
+		}
+		if u.IsActive != nil {
+			if result, err = s.SetIsActive(ctx, id, *u.IsActive); err != nil {
+				return err
+			}
+		}
+		if u.Skills != nil {
+			if result, err = s.SetSkills(ctx, id, u.Skills); err != nil {
+				return err
+			}
+		}
+		if u.Schedule != nil {
+			if result, err = s.SetSchedule(ctx, id, *u.Schedule); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// mutate runs change as a unit of work and records the user's state before
+// and after it in the audit log. Activity changes are published as events in
+// the same unit of work.
+func (s *Service) mutate(ctx context.Context, id string, action audit.Action, change func(ctx context.Context, before *User) (*User, error)) (*User, error) {
+	var result *User
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		before, err := s.storage.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		after, err := change(ctx, before)
+		if err != nil {
+			return err
+		}
+
+		if err := s.audit.Record(ctx, action, audit.EntityUser, id, before, after); err != nil {
+			return err
+		}
+		if before.IsActive != after.IsActive || before.IsDeparted() != after.IsDeparted() {
+			payload := events.ActivityChangedPayload{UserID: id, IsActive: after.IsActive, Departed: after.IsDeparted()}
+			if err := s.events.Publish(ctx, events.UserActivityChanged, events.AggregateUser, id, payload); err != nil {
+				return err
+			}
+		}
+		result = after
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
 }
@@ -1,8 +1,12 @@
 package user
 
 import (
+	"InternshipTask/internal/domain/clock"
+	"InternshipTask/internal/domain/events"
 	"context"
+	"errors"
 	"testing"
+	"time"
 )
 
 type stubUserStorage struct {
@@ -10,9 +14,13 @@ type stubUserStorage struct {
 	lastID            string
 	lastActive        bool
 	user              *User
+	lastSkills        []string
 }
 
 func (s *stubUserStorage) GetByID(_ context.Context, id string) (*User, error) {
+	if s.user != nil {
+		return s.user, nil
+	}
 	return &User{UserId: id}, nil
 }
 
@@ -26,6 +34,19 @@ func (s *stubUserStorage) SetIsActive(_ context.Context, id string, isActive boo
 	return &User{UserId: id, IsActive: isActive}, nil
 }
 
+func (s *stubUserStorage) SetSkills(_ context.Context, id string, skills []string) (*User, error) {
+	s.lastSkills = skills
+	return &User{UserId: id, Skills: skills}, nil
+}
+
+func (s *stubUserStorage) SetSchedule(_ context.Context, id string, schedule Schedule) (*User, error) {
+	return &User{UserId: id, Schedule: schedule}, nil
+}
+
+func (s *stubUserStorage) MarkDeparted(_ context.Context, id string, at time.Time) (*User, error) {
+	return &User{UserId: id, DepartedAt: &at}, nil
+}
+
 func TestService_SetIsActiveDelegatesToStorage(t *testing.T) {
 	storage := &stubUserStorage{}
 	svc := NewService(storage)
@@ -46,4 +67,138 @@ func TestService_SetIsActiveDelegatesToStorage(t *testing.T) {
 	}
 }
 
+type stubPublisher struct {
+	payloads []any
+}
+
+func (p *stubPublisher) Publish(_ context.Context, eventType events.Type, _, _ string, payload any) error {
+	if eventType != events.UserActivityChanged {
+		return errors.New("unexpected event type " + string(eventType))
+	}
+	p.payloads = append(p.payloads, payload)
+	return nil
+}
+
+func TestService_PublishesActivityChangesOnly(t *testing.T) {
+	pub := &stubPublisher{}
+	svc := NewService(&stubUserStorage{}, WithEvents(pub))
+
+	if _, err := svc.SetIsActive(context.Background(), "u1", true); err != nil {
+		t.Fatalf("SetIsActive() error = %v", err)
+	}
+	if _, err := svc.SetSkills(context.Background(), "u1", []string{"go"}); err != nil {
+		t.Fatalf("SetSkills() error = %v", err)
+	}
+
+	want := events.ActivityChangedPayload{UserID: "u1", IsActive: true}
+	if len(pub.payloads) != 1 || pub.payloads[0] != want {
+		t.Fatalf("expected a single %+v, got %+v", want, pub.payloads)
+	}
+}
+
+func TestService_SetSkillsNormalizes(t *testing.T) {
+	storage := &stubUserStorage{}
+	svc := NewService(storage)
+
+	if _, err := svc.SetSkills(context.Background(), "u1", []string{" Go", "frontend-react", "go"}); err != nil {
+		t.Fatalf("SetSkills() error = %v", err)
+	}
+	if len(storage.lastSkills) != 2 || storage.lastSkills[0] != "frontend-react" || storage.lastSkills[1] != "go" {
+		t.Fatalf("unexpected skills: %v", storage.lastSkills)
+	}
+
+	if _, err := svc.SetSkills(context.Background(), "u1", []string{"c sharp"}); !errors.Is(err, ErrInvalidSkill) {
+		t.Fatalf("expected ErrInvalidSkill, got %v", err)
+	}
+}
+
+func TestService_SetScheduleValidates(t *testing.T) {
+	svc := NewService(&stubUserStorage{})
+
+	u, err := svc.SetSchedule(context.Background(), "u1", Schedule{TimeZone: "America/New_York", WorkStart: 8 * 60, WorkEnd: 17 * 60})
+	if err != nil {
+		t.Fatalf("SetSchedule() error = %v", err)
+	}
+	if u.Schedule.TimeZone != "America/New_York" {
+		t.Fatalf("unexpected schedule: %+v", u.Schedule)
+	}
 
+	invalid := []Schedule{
+		{TimeZone: "", WorkStart: 9 * 60, WorkEnd: 18 * 60},
+		{TimeZone: "Not/AZone", WorkStart: 9 * 60, WorkEnd: 18 * 60},
+		{TimeZone: "UTC", WorkStart: 18 * 60, WorkEnd: 9 * 60},
+	}
+	for _, sch := range invalid {
+		if _, err := svc.SetSchedule(context.Background(), "u1", sch); !errors.Is(err, ErrInvalidSchedule) {
+			t.Fatalf("expected ErrInvalidSchedule for %+v, got %v", sch, err)
+		}
+	}
+}
+
+func TestService_UpdateAppliesSetFieldsOnly(t *testing.T) {
+	storage := &stubUserStorage{}
+	svc := NewService(storage)
+
+	u, err := svc.Update(context.Background(), "u1", Update{Skills: []string{"Go"}})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if storage.setIsActiveCalled || len(u.Skills) != 1 || u.Skills[0] != "go" {
+		t.Fatalf("expected only normalized skills to be set, got %+v", u)
+	}
+
+	active := true
+	if _, err := svc.Update(context.Background(), "u1", Update{IsActive: &active}); err != nil || !storage.lastActive {
+		t.Fatalf("expected the user to be activated, got %v", err)
+	}
+
+	if _, err := svc.Update(context.Background(), "u1", Update{Schedule: &Schedule{TimeZone: "UTC", WorkStart: 18 * 60, WorkEnd: 9 * 60}}); !errors.Is(err, ErrInvalidSchedule) {
+		t.Fatalf("expected ErrInvalidSchedule, got %v", err)
+	}
+
+	u, err = svc.Update(context.Background(), "u2", Update{})
+	if err != nil || u.UserId != "u2" {
+		t.Fatalf("expected an empty update to return the user, got %+v, %v", u, err)
+	}
+}
+
+func TestSchedule_WorkingDuration(t *testing.T) {
+	moscow, _ := time.LoadLocation("Europe/Moscow")
+	sch := Schedule{TimeZone: "Europe/Moscow", WorkStart: 9 * 60, WorkEnd: 18 * 60}
+
+	// Friday 16:00 until Monday 10:00 local time: 2h on Friday and 1h on Monday.
+	from := time.Date(2026, time.October, 16, 16, 0, 0, 0, moscow)
+	to := time.Date(2026, time.October, 19, 10, 0, 0, 0, moscow)
+
+	if got := sch.WorkingDuration(from, to); got != 3*time.Hour {
+		t.Fatalf("expected 3h, got %s", got)
+	}
+	if sch.IsWorkingTime(time.Date(2026, time.October, 17, 12, 0, 0, 0, moscow)) {
+		t.Fatalf("saturday must not be a working time")
+	}
+	if !sch.IsWorkingTime(time.Date(2026, time.October, 19, 6, 30, 0, 0, time.UTC)) {
+		t.Fatalf("06:30 UTC is 09:30 in Moscow and must be a working time")
+	}
+}
+
+func TestService_DepartedUserCannotBeReactivated(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	storage := &stubUserStorage{}
+	svc := NewService(storage, WithClock(clock.Fixed(now)))
+
+	u, err := svc.MarkDeparted(context.Background(), "u1")
+	if err != nil {
+		t.Fatalf("MarkDeparted() error = %v", err)
+	}
+	if u.DepartedAt == nil || !u.DepartedAt.Equal(now) {
+		t.Fatalf("expected departedAt %v, got %v", now, u.DepartedAt)
+	}
+
+	storage.user = u
+	if _, err := svc.SetIsActive(context.Background(), "u1", true); !errors.Is(err, ErrUserDeparted) {
+		t.Fatalf("expected ErrUserDeparted, got %v", err)
+	}
+	if storage.setIsActiveCalled {
+		t.Fatalf("storage must not be called for departed user")
+	}
+}
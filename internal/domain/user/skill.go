@@ -0,0 +1,45 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrInvalidSkill = errors.New("invalid skill tag")
+)
+
+const maxSkillLength = 64
+
+// NormalizeSkills lower-cases, trims, validates and de-duplicates skill tags.
+// Tags may contain latin letters, digits and the characters "-", "_", ".", "+" and "#".
+func NormalizeSkills(skills []string) ([]string, error) {
+	result := make([]string, 0, len(skills))
+	for _, raw := range skills {
+		skill := strings.ToLower(strings.TrimSpace(raw))
+		if skill == "" || len(skill) > maxSkillLength {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidSkill, raw)
+		}
+		for _, r := range skill {
+			if !isSkillRune(r) {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidSkill, raw)
+			}
+		}
+		result = append(result, skill)
+	}
+
+	slices.Sort(result)
+	return slices.Compact(result), nil
+}
+
+func isSkillRune(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+		return true
+	case r == '-', r == '_', r == '.', r == '+', r == '#':
+		return true
+	}
+	return false
+}
@@ -1,16 +1,24 @@
 package user
 
-import "errors"
+import (
+	"errors"
+	"slices"
+	"time"
+)
 
 var (
 	ErrUserNotFound = errors.New("user not found")
+	ErrUserDeparted = errors.New("user has departed")
 )
 
 type User struct {
-	UserId   string `gorm:"primaryKey"`
-	UserName string
-	TeamName string
-	IsActive bool
+	UserId     string     `json:"user_id" gorm:"primaryKey"`
+	UserName   string     `json:"username"`
+	TeamName   string     `json:"team_name"`
+	IsActive   bool       `json:"is_active"`
+	Skills     []string   `json:"skills"`
+	Schedule   Schedule   `json:"schedule"`
+	DepartedAt *time.Time `json:"departed_at,omitempty"`
 }
 
 func NewUser(id string, name string, teamName string, isActive bool) *User {
@@ -19,5 +27,20 @@ func NewUser(id string, name string, teamName string, isActive bool) *User {
 		UserName: name,
 		TeamName: teamName,
 		IsActive: isActive,
+		Skills:   make([]string, 0),
+		Schedule: DefaultSchedule(),
 	}
 }
+
+func (u *User) HasSkill(skill string) bool {
+	return slices.Contains(u.Skills, skill)
+}
+
+func (u *User) IsDeparted() bool {
+	return u.DepartedAt != nil
+}
+
+// CanReview reports whether the user may be assigned as a reviewer.
+func (u *User) CanReview() bool {
+	return u.IsActive && !u.IsDeparted()
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"InternshipTask/internal/domain/audit"
+	"cmp"
+	"context"
+	"slices"
+)
+
+type auditStorage struct {
+	db *DB
+}
+
+func NewAuditStorage(db *DB) *auditStorage {
+	return &auditStorage{db: db}
+}
+
+var _ audit.Storager = (*auditStorage)(nil)
+
+func (s *auditStorage) Append(ctx context.Context, e *audit.Entry) error {
+	db := s.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.auditSeq++
+	e.ID = db.auditSeq
+	db.audit = append(db.audit, cloneEntry(*e))
+
+	id := e.ID
+	db.onRollback(ctx, func() {
+		db.audit = slices.DeleteFunc(db.audit, func(e audit.Entry) bool { return e.ID == id })
+	})
+
+	return nil
+}
+
+func (s *auditStorage) List(_ context.Context, f audit.Filter) ([]audit.Entry, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	result := make([]audit.Entry, 0)
+	for _, e := range s.db.audit {
+		if f.EntityType != "" && e.EntityType != f.EntityType {
+			continue
+		}
+		if f.EntityID != "" && e.EntityID != f.EntityID {
+			continue
+		}
+		if f.Actor != "" && e.Actor != f.Actor {
+			continue
+		}
+		if !f.From.IsZero() && e.At.Before(f.From) {
+			continue
+		}
+		if !f.To.IsZero() && !e.At.Before(f.To) {
+			continue
+		}
+		result = append(result, cloneEntry(e))
+	}
+
+	slices.SortFunc(result, func(a, b audit.Entry) int {
+		if c := b.At.Compare(a.At); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+	if f.Limit > 0 && len(result) > f.Limit {
+		result = result[:f.Limit]
+	}
+
+	return result, nil
+}
+
+func cloneEntry(e audit.Entry) audit.Entry {
+	e.Before = slices.Clone(e.Before)
+	e.After = slices.Clone(e.After)
+	return e
+}
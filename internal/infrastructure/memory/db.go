@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"InternshipTask/internal/domain/audit"
+	"InternshipTask/internal/domain/auth"
+	"InternshipTask/internal/domain/events"
+	"InternshipTask/internal/domain/idempotency"
+	"InternshipTask/internal/domain/pull_request"
+	"InternshipTask/internal/domain/txn"
+	"InternshipTask/internal/domain/user"
+	"context"
+	"slices"
+	"sync"
+)
+
+// DB is the state shared by the in-memory storages, the counterpart of a
+// Postgres database. Storages hand out copies, never pointers into DB.
+type DB struct {
+	mu sync.RWMutex
+
+	teams       map[string]struct{}
+	memberships map[string]map[string]struct{} // team name -> user ids
+	users       map[string]*user.User
+	prs         map[string]*pull_request.PR
+	assignments map[string][]assignment // pr id -> history, oldest first
+	// archivedPRs and archivedAssignments mirror prs and assignments for
+	// PRs moved out by ArchiveMerged.
+	archivedPRs         map[string]*pull_request.PR
+	archivedAssignments map[string][]assignment
+	audit               []audit.Entry // oldest first
+	auditSeq            int64
+	outbox              []events.Event // undelivered only, oldest first
+	outboxSeq           int64
+	idempotency         map[string]idempotency.Record
+	tokens              []auth.Token // oldest first
+}
+
+type assignment struct {
+	pull_request.Assignment
+	slot int
+}
+
+func NewDB() *DB {
+	return &DB{
+		teams:       make(map[string]struct{}),
+		memberships: make(map[string]map[string]struct{}),
+		users:       make(map[string]*user.User),
+		prs:         make(map[string]*pull_request.PR),
+		assignments: make(map[string][]assignment),
+
+		archivedPRs:         make(map[string]*pull_request.PR),
+		archivedAssignments: make(map[string][]assignment),
+		idempotency:         make(map[string]idempotency.Record),
+	}
+}
+
+// onRollback registers undo with the unit of work in ctx. It must be called
+// with db.mu held; undo runs later and takes the lock itself.
+func (db *DB) onRollback(ctx context.Context, undo func()) {
+	txn.OnRollback(ctx, func() {
+		db.mu.Lock()
+		defer db.mu.Unlock()
+		undo()
+	})
+}
+
+func cloneUser(u *user.User) *user.User {
+	c := *u
+	c.Skills = slices.Clone(u.Skills)
+	if c.Skills == nil {
+		c.Skills = make([]string, 0)
+	}
+	if u.DepartedAt != nil {
+		at := *u.DepartedAt
+		c.DepartedAt = &at
+	}
+	return &c
+}
+
+func clonePR(pr *pull_request.PR) *pull_request.PR {
+	c := *pr
+	c.AssignedReviewers = slices.Clone(pr.AssignedReviewers)
+	c.RequiredSkills = slices.Clone(pr.RequiredSkills)
+	if c.RequiredSkills == nil {
+		c.RequiredSkills = make([]string, 0)
+	}
+	if pr.CreatedAt != nil {
+		at := *pr.CreatedAt
+		c.CreatedAt = &at
+	}
+	if pr.MergedAt != nil {
+		at := *pr.MergedAt
+		c.MergedAt = &at
+	}
+	c.SkillFallback = false
+	c.ReviewerChange = pull_request.ReviewerChange{}
+	return &c
+}
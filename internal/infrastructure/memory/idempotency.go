@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"InternshipTask/internal/domain/idempotency"
+	"context"
+	"slices"
+	"time"
+)
+
+type idempotencyStorage struct {
+	db *DB
+}
+
+func NewIdempotencyStorage(db *DB) *idempotencyStorage {
+	return &idempotencyStorage{db: db}
+}
+
+var _ idempotency.Store = (*idempotencyStorage)(nil)
+
+func (s *idempotencyStorage) Reserve(_ context.Context, rec idempotency.Record) (*idempotency.Record, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if existing, ok := s.db.idempotency[rec.Key]; ok && existing.ExpiresAt.After(rec.CreatedAt) {
+		existing.Body = slices.Clone(existing.Body)
+		return &existing, nil
+	}
+
+	rec.Status = 0
+	rec.Body = nil
+	s.db.idempotency[rec.Key] = rec
+
+	return nil, nil
+}
+
+func (s *idempotencyStorage) Complete(_ context.Context, key string, status int, body []byte) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	rec, ok := s.db.idempotency[key]
+	if !ok {
+		return nil
+	}
+	rec.Status = status
+	rec.Body = slices.Clone(body)
+	s.db.idempotency[key] = rec
+
+	return nil
+}
+
+func (s *idempotencyStorage) Release(_ context.Context, key string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	delete(s.db.idempotency, key)
+
+	return nil
+}
+
+func (s *idempotencyStorage) DeleteExpired(_ context.Context, now time.Time) (int, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	n := 0
+	for key, rec := range s.db.idempotency {
+		if !rec.ExpiresAt.After(now) {
+			delete(s.db.idempotency, key)
+			n++
+		}
+	}
+
+	return n, nil
+}
@@ -0,0 +1,23 @@
+package memory
+
+import (
+	"InternshipTask/internal/domain/txn"
+	"InternshipTask/internal/infrastructure/storagetest"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Backend {
+		db := NewDB()
+		return storagetest.Backend{
+			Teams:       NewTeamStorage(db),
+			Users:       NewUserStorage(db),
+			PRs:         NewPullRequestStorage(db),
+			Audit:       NewAuditStorage(db),
+			Outbox:      NewOutboxStorage(db),
+			Idempotency: NewIdempotencyStorage(db),
+			Tokens:      NewTokenStorage(db),
+			Tx:          txn.NewMemory(),
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"InternshipTask/internal/domain/events"
+	"context"
+	"slices"
+	"time"
+)
+
+type outboxStorage struct {
+	db *DB
+}
+
+func NewOutboxStorage(db *DB) *outboxStorage {
+	return &outboxStorage{db: db}
+}
+
+var _ events.Outbox = (*outboxStorage)(nil)
+
+func (s *outboxStorage) Append(ctx context.Context, e *events.Event) error {
+	db := s.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.outboxSeq++
+	e.ID = db.outboxSeq
+	db.outbox = append(db.outbox, cloneEvent(*e))
+
+	id := e.ID
+	db.onRollback(ctx, func() {
+		db.outbox = slices.DeleteFunc(db.outbox, func(e events.Event) bool { return e.ID == id })
+	})
+
+	return nil
+}
+
+func (s *outboxStorage) Pending(_ context.Context, now time.Time, limit int) ([]events.Event, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	result := make([]events.Event, 0)
+	heads := make(map[string]bool)
+	for _, e := range s.db.outbox {
+		key := e.AggregateType + "/" + e.AggregateID
+		if e.ParkedAt != nil || heads[key] {
+			continue
+		}
+		heads[key] = true
+		if e.NextAttemptAt.After(now) {
+			continue
+		}
+		result = append(result, cloneEvent(e))
+		if limit > 0 && len(result) == limit {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+func (s *outboxStorage) MarkDelivered(_ context.Context, id int64) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	s.db.outbox = slices.DeleteFunc(s.db.outbox, func(e events.Event) bool { return e.ID == id })
+
+	return nil
+}
+
+func (s *outboxStorage) MarkFailed(_ context.Context, e events.Event) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for i := range s.db.outbox {
+		if s.db.outbox[i].ID == e.ID {
+			s.db.outbox[i].Attempts = e.Attempts
+			s.db.outbox[i].NextAttemptAt = e.NextAttemptAt
+			s.db.outbox[i].LastError = e.LastError
+			s.db.outbox[i].ParkedAt = cloneTime(e.ParkedAt)
+		}
+	}
+
+	return nil
+}
+
+func cloneEvent(e events.Event) events.Event {
+	e.Payload = slices.Clone(e.Payload)
+	e.ParkedAt = cloneTime(e.ParkedAt)
+	return e
+}
@@ -0,0 +1,493 @@
+package memory
+
+import (
+	"InternshipTask/internal/domain/pull_request"
+	"InternshipTask/internal/domain/user"
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+type pullRequestStorage struct {
+	db *DB
+}
+
+func NewPullRequestStorage(db *DB) *pullRequestStorage {
+	return &pullRequestStorage{db: db}
+}
+
+var _ pull_request.Repository = (*pullRequestStorage)(nil)
+
+func (s *pullRequestStorage) Create(ctx context.Context, pr *pull_request.PR) error {
+	db := s.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.prs[pr.PullRequestId]; ok {
+		return fmt.Errorf("insert pull_request: %w", pull_request.ErrPRExists)
+	}
+	if _, ok := db.archivedPRs[pr.PullRequestId]; ok {
+		return fmt.Errorf("insert pull_request: archived: %w", pull_request.ErrPRExists)
+	}
+	if _, ok := db.users[pr.AuthorId]; !ok {
+		return fmt.Errorf("insert pull_request: author %s: %w", pr.AuthorId, user.ErrUserNotFound)
+	}
+
+	stored := clonePR(pr)
+	stored.AssignedReviewers = nil
+	stored.Version = 1
+	db.prs[pr.PullRequestId] = stored
+
+	history := make([]assignment, 0, len(pr.AssignedReviewers))
+	for slot, reviewerID := range pr.AssignedReviewers {
+		history = append(history, newAssignment(pr, reviewerID, slot))
+	}
+	db.assignments[pr.PullRequestId] = history
+
+	db.onRollback(ctx, func() {
+		delete(db.prs, pr.PullRequestId)
+		delete(db.assignments, pr.PullRequestId)
+	})
+
+	pr.Version = 1
+
+	return nil
+}
+
+func (s *pullRequestStorage) GetByID(_ context.Context, id string) (*pull_request.PR, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	stored, ok := s.db.prs[id]
+	if !ok {
+		return nil, pull_request.ErrNotFound
+	}
+
+	return s.withReviewers(stored), nil
+}
+
+// Update follows the Postgres storage: it fails with ErrConflict on a stale
+// version and closes or opens assignments for the reviewers that changed.
+func (s *pullRequestStorage) Update(ctx context.Context, pr *pull_request.PR) error {
+	db := s.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	prev, ok := db.prs[pr.PullRequestId]
+	if !ok {
+		return pull_request.ErrNotFound
+	}
+	if prev.Version != pr.Version {
+		return pull_request.ErrConflict
+	}
+
+	stored := clonePR(prev)
+	stored.Status = pr.Status
+	stored.MergedAt = clonePR(pr).MergedAt
+	stored.AuthorId = pr.AuthorId
+	stored.Version++
+	db.prs[pr.PullRequestId] = stored
+
+	prevHistory := db.assignments[pr.PullRequestId]
+	history := slices.Clone(prevHistory)
+	active := activeReviewers(history)
+
+	change := pr.ReviewerChange
+	at := changeTime(change)
+	for i := range history {
+		a := &history[i]
+		if a.UnassignedAt != nil || slices.Contains(pr.AssignedReviewers, a.ReviewerID) {
+			continue
+		}
+		a.UnassignedAt = &at
+		a.UnassignReason = change.Reason
+		a.UnassignedBy = change.Actor
+	}
+	for slot, reviewerID := range pr.AssignedReviewers {
+		if !slices.Contains(active, reviewerID) {
+			history = append(history, newAssignment(pr, reviewerID, slot))
+		}
+	}
+	db.assignments[pr.PullRequestId] = history
+
+	db.onRollback(ctx, func() {
+		db.prs[pr.PullRequestId] = prev
+		db.assignments[pr.PullRequestId] = prevHistory
+	})
+
+	pr.Version = stored.Version
+
+	return nil
+}
+
+func (s *pullRequestStorage) GetByReviewerID(_ context.Context, reviewerID string, includeArchived bool) ([]pull_request.PullRequestShort, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	return s.shorts(includeArchived, func(pr *pull_request.PR, history []assignment) bool {
+		return slices.Contains(activeReviewers(history), reviewerID)
+	}), nil
+}
+
+func (s *pullRequestStorage) GetOpenByAuthorID(_ context.Context, authorID string) ([]pull_request.PullRequestShort, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	return s.shorts(false, func(pr *pull_request.PR, _ []assignment) bool {
+		return pr.AuthorId == authorID && pr.Status == pull_request.OPEN
+	}), nil
+}
+
+func (s *pullRequestStorage) GetReviewerStats(_ context.Context, includeArchived bool) (map[string]int64, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	stats := make(map[string]int64)
+	count := func(assignments map[string][]assignment) {
+		for _, history := range assignments {
+			for _, a := range history {
+				stats[a.ReviewerID]++
+			}
+		}
+	}
+	count(s.db.assignments)
+	if includeArchived {
+		count(s.db.archivedAssignments)
+	}
+
+	return stats, nil
+}
+
+func (s *pullRequestStorage) ListReviews(_ context.Context, f pull_request.ReviewFilter) ([]pull_request.Review, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	result := make([]pull_request.Review, 0)
+	collect := func(prs map[string]*pull_request.PR, assignments map[string][]assignment) {
+		for id, pr := range prs {
+			a, ok := activeAssignment(assignments[id], f.ReviewerID)
+			if !ok || !matchesFilter(pr, nil, pull_request.ListFilter{
+				Status:      f.Status,
+				TeamName:    f.TeamName,
+				CreatedFrom: f.CreatedFrom,
+				CreatedTo:   f.CreatedTo,
+			}) {
+				continue
+			}
+			result = append(result, pull_request.Review{
+				PullRequestShort: *pull_request.NewPullRequestShort(pr.PullRequestId, pr.PullRequestName, pr.AuthorId, pr.Status),
+				TeamName:         pr.TeamName,
+				CreatedAt:        cloneTime(pr.CreatedAt),
+				MergedAt:         cloneTime(pr.MergedAt),
+				AssignedAt:       a.AssignedAt,
+				Verdict:          a.Verdict,
+				VerdictAt:        cloneTime(a.VerdictAt),
+			})
+		}
+	}
+	collect(s.db.prs, s.db.assignments)
+	if f.IncludeArchived {
+		collect(s.db.archivedPRs, s.db.archivedAssignments)
+	}
+
+	compare := func(a, b pull_request.Review) int {
+		return cmp.Or(compareTimes(a.CreatedAt, b.CreatedAt), cmp.Compare(a.PullRequestId, b.PullRequestId))
+	}
+	slices.SortFunc(result, compare)
+
+	if f.After != nil {
+		after := pull_request.Review{CreatedAt: &f.After.CreatedAt}
+		after.PullRequestId = f.After.ID
+		result = slices.DeleteFunc(result, func(r pull_request.Review) bool {
+			return compare(r, after) <= 0
+		})
+	}
+	if f.Limit > 0 && len(result) > f.Limit {
+		result = result[:f.Limit]
+	}
+
+	return result, nil
+}
+
+func (s *pullRequestStorage) SetVerdict(ctx context.Context, prID, reviewerID string, verdict pull_request.Verdict, at time.Time) error {
+	db := s.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	prevHistory := db.assignments[prID]
+	history := slices.Clone(prevHistory)
+	for i := range history {
+		a := &history[i]
+		if a.ReviewerID != reviewerID || a.UnassignedAt != nil {
+			continue
+		}
+		a.Verdict = verdict
+		a.VerdictAt = &at
+		db.assignments[prID] = history
+
+		db.onRollback(ctx, func() {
+			db.assignments[prID] = prevHistory
+		})
+		return nil
+	}
+
+	return pull_request.ErrNotAssigned
+}
+
+func activeAssignment(history []assignment, reviewerID string) (assignment, bool) {
+	for _, a := range history {
+		if a.ReviewerID == reviewerID && a.UnassignedAt == nil {
+			return a, true
+		}
+	}
+	return assignment{}, false
+}
+
+func (s *pullRequestStorage) GetOpenReviewCounts(_ context.Context, reviewerIDs []string) (map[string]int64, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	counts := make(map[string]int64)
+	for id, pr := range s.db.prs {
+		if pr.Status != pull_request.OPEN {
+			continue
+		}
+		for _, reviewerID := range activeReviewers(s.db.assignments[id]) {
+			if slices.Contains(reviewerIDs, reviewerID) {
+				counts[reviewerID]++
+			}
+		}
+	}
+
+	return counts, nil
+}
+
+func (s *pullRequestStorage) ArchiveMerged(ctx context.Context, mergedBefore time.Time, limit int) (int, error) {
+	db := s.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	due := make([]*pull_request.PR, 0)
+	for _, pr := range db.prs {
+		if pr.Status == pull_request.MERGED && pr.MergedAt != nil && pr.MergedAt.Before(mergedBefore) {
+			due = append(due, pr)
+		}
+	}
+	slices.SortFunc(due, func(a, b *pull_request.PR) int {
+		return cmp.Or(a.MergedAt.Compare(*b.MergedAt), cmp.Compare(a.PullRequestId, b.PullRequestId))
+	})
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+
+	for _, pr := range due {
+		id := pr.PullRequestId
+		db.archivedPRs[id] = pr
+		db.archivedAssignments[id] = db.assignments[id]
+		delete(db.prs, id)
+		delete(db.assignments, id)
+
+		db.onRollback(ctx, func() {
+			db.prs[id] = db.archivedPRs[id]
+			db.assignments[id] = db.archivedAssignments[id]
+			delete(db.archivedPRs, id)
+			delete(db.archivedAssignments, id)
+		})
+	}
+
+	return len(due), nil
+}
+
+func (s *pullRequestStorage) List(_ context.Context, f pull_request.ListFilter) ([]pull_request.PR, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	type listed struct {
+		pr      *pull_request.PR
+		history []assignment
+	}
+	matched := make([]listed, 0)
+	collect := func(prs map[string]*pull_request.PR, assignments map[string][]assignment) {
+		for id, pr := range prs {
+			if history := assignments[id]; matchesFilter(pr, history, f) {
+				matched = append(matched, listed{pr: pr, history: history})
+			}
+		}
+	}
+	collect(s.db.prs, s.db.assignments)
+	if f.IncludeArchived {
+		collect(s.db.archivedPRs, s.db.archivedAssignments)
+	}
+
+	compare := func(a, b *pull_request.PR) int {
+		return cmp.Or(compareTimes(a.CreatedAt, b.CreatedAt), cmp.Compare(a.PullRequestId, b.PullRequestId))
+	}
+	if f.Sort == pull_request.SortCreatedDesc {
+		compare = func(a, b *pull_request.PR) int {
+			return cmp.Or(compareTimes(b.CreatedAt, a.CreatedAt), cmp.Compare(b.PullRequestId, a.PullRequestId))
+		}
+	}
+	slices.SortFunc(matched, func(a, b listed) int {
+		return compare(a.pr, b.pr)
+	})
+
+	var after *pull_request.PR
+	if f.After != nil {
+		after = &pull_request.PR{PullRequestId: f.After.ID, CreatedAt: &f.After.CreatedAt}
+	}
+
+	result := make([]pull_request.PR, 0)
+	for _, m := range matched {
+		if after != nil && compare(m.pr, after) <= 0 {
+			continue
+		}
+		if f.Limit > 0 && len(result) == f.Limit {
+			break
+		}
+		pr := clonePR(m.pr)
+		pr.AssignedReviewers = activeReviewers(m.history)
+		result = append(result, *pr)
+	}
+
+	return result, nil
+}
+
+func matchesFilter(pr *pull_request.PR, history []assignment, f pull_request.ListFilter) bool {
+	switch {
+	case f.Status != "" && pr.Status != f.Status,
+		f.AuthorID != "" && pr.AuthorId != f.AuthorID,
+		f.TeamName != "" && pr.TeamName != f.TeamName,
+		f.ReviewerID != "" && !slices.Contains(activeReviewers(history), f.ReviewerID),
+		f.Name != "" && !strings.Contains(strings.ToLower(pr.PullRequestName), strings.ToLower(f.Name)):
+		return false
+	}
+	return inRange(pr.CreatedAt, f.CreatedFrom, f.CreatedTo) && inRange(pr.MergedAt, f.MergedFrom, f.MergedTo)
+}
+
+// inRange reports whether t is within [from, to); zero bounds are open and a
+// nil t is only within fully open bounds.
+func inRange(t *time.Time, from, to time.Time) bool {
+	if from.IsZero() && to.IsZero() {
+		return true
+	}
+	if t == nil {
+		return false
+	}
+	return (from.IsZero() || !t.Before(from)) && (to.IsZero() || t.Before(to))
+}
+
+func (s *pullRequestStorage) GetAssignments(_ context.Context, prID string) ([]pull_request.Assignment, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	history := slices.Clone(s.db.assignments[prID])
+	slices.SortStableFunc(history, func(a, b assignment) int {
+		return a.AssignedAt.Compare(b.AssignedAt)
+	})
+
+	result := make([]pull_request.Assignment, 0, len(history))
+	for _, a := range history {
+		a.UnassignedAt = cloneTime(a.UnassignedAt)
+		a.VerdictAt = cloneTime(a.VerdictAt)
+		result = append(result, a.Assignment)
+	}
+
+	return result, nil
+}
+
+func (s *pullRequestStorage) withReviewers(stored *pull_request.PR) *pull_request.PR {
+	pr := clonePR(stored)
+	pr.AssignedReviewers = activeReviewers(s.db.assignments[pr.PullRequestId])
+	return pr
+}
+
+// shorts returns the PRs matching keep ordered by creation time, like the
+// Postgres queries do.
+func (s *pullRequestStorage) shorts(includeArchived bool, keep func(pr *pull_request.PR, history []assignment) bool) []pull_request.PullRequestShort {
+	matched := make([]*pull_request.PR, 0)
+	for id, pr := range s.db.prs {
+		if keep(pr, s.db.assignments[id]) {
+			matched = append(matched, pr)
+		}
+	}
+	if includeArchived {
+		for id, pr := range s.db.archivedPRs {
+			if keep(pr, s.db.archivedAssignments[id]) {
+				matched = append(matched, pr)
+			}
+		}
+	}
+	slices.SortFunc(matched, func(a, b *pull_request.PR) int {
+		return cmp.Or(compareTimes(a.CreatedAt, b.CreatedAt), cmp.Compare(a.PullRequestId, b.PullRequestId))
+	})
+
+	result := make([]pull_request.PullRequestShort, 0, len(matched))
+	for _, pr := range matched {
+		result = append(result, *pull_request.NewPullRequestShort(pr.PullRequestId, pr.PullRequestName, pr.AuthorId, pr.Status))
+	}
+	return result
+}
+
+// activeReviewers returns the reviewers with an open assignment ordered by
+// slot.
+func activeReviewers(history []assignment) []string {
+	active := make([]assignment, 0, 2)
+	for _, a := range history {
+		if a.UnassignedAt == nil {
+			active = append(active, a)
+		}
+	}
+	slices.SortStableFunc(active, func(a, b assignment) int {
+		return cmp.Compare(a.slot, b.slot)
+	})
+
+	ids := make([]string, 0, len(active))
+	for _, a := range active {
+		ids = append(ids, a.ReviewerID)
+	}
+	return ids
+}
+
+func newAssignment(pr *pull_request.PR, reviewerID string, slot int) assignment {
+	change := pr.ReviewerChange
+	return assignment{
+		Assignment: pull_request.Assignment{
+			ReviewerID:     reviewerID,
+			AssignedAt:     changeTime(change),
+			AssignedReason: change.Reason,
+			AssignedBy:     change.Actor,
+			Verdict:        pull_request.VerdictPending,
+		},
+		slot: slot,
+	}
+}
+
+func changeTime(change pull_request.ReviewerChange) time.Time {
+	if change.At.IsZero() {
+		return time.Now().UTC()
+	}
+	return change.At
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+func compareTimes(a, b *time.Time) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	return a.Compare(*b)
+}
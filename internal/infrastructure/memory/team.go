@@ -0,0 +1,188 @@
+package memory
+
+import (
+	"InternshipTask/internal/domain/team"
+	"InternshipTask/internal/domain/user"
+	"context"
+	"slices"
+)
+
+type teamStorage struct {
+	db *DB
+}
+
+func NewTeamStorage(db *DB) *teamStorage {
+	return &teamStorage{db: db}
+}
+
+var _ team.Storager = (*teamStorage)(nil)
+
+func (s *teamStorage) Create(ctx context.Context, t team.Team) error {
+	db := s.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.teams[t.TeamName]; ok {
+		return team.ErrTeamExists
+	}
+	db.teams[t.TeamName] = struct{}{}
+	db.memberships[t.TeamName] = make(map[string]struct{})
+	db.onRollback(ctx, func() {
+		delete(db.teams, t.TeamName)
+		delete(db.memberships, t.TeamName)
+	})
+
+	for _, member := range t.Members {
+		if member == nil {
+			continue
+		}
+		s.upsertMember(ctx, t.TeamName, member)
+	}
+
+	return nil
+}
+
+// upsertMember mirrors the Postgres upsert: existing users keep their skills,
+// schedule and departure, and departed users stay inactive.
+func (s *teamStorage) upsertMember(ctx context.Context, teamName string, member *user.User) {
+	db := s.db
+
+	prev, exists := db.users[member.UserId]
+	var u *user.User
+	if exists {
+		u = cloneUser(prev)
+		u.UserName = member.UserName
+		u.TeamName = teamName
+		u.IsActive = member.IsActive && prev.DepartedAt == nil
+	} else {
+		u = user.NewUser(member.UserId, member.UserName, teamName, member.IsActive)
+	}
+	db.users[member.UserId] = u
+	db.onRollback(ctx, func() {
+		if exists {
+			db.users[member.UserId] = prev
+		} else {
+			delete(db.users, member.UserId)
+		}
+	})
+
+	members := db.memberships[teamName]
+	if _, ok := members[member.UserId]; !ok {
+		members[member.UserId] = struct{}{}
+		db.onRollback(ctx, func() {
+			delete(db.memberships[teamName], member.UserId)
+		})
+	}
+}
+
+func (s *teamStorage) GetByTeamName(_ context.Context, teamName string) (team.Team, error) {
+	db := s.db
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	if _, ok := db.teams[teamName]; !ok {
+		return team.Team{}, team.ErrTeamNotFound
+	}
+
+	ids := make([]string, 0, len(db.memberships[teamName]))
+	for id := range db.memberships[teamName] {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	t := team.NewTeam(teamName)
+	for i, id := range ids {
+		t.Members[uint(i)] = cloneUser(db.users[id])
+	}
+
+	return *t, nil
+}
+
+func (s *teamStorage) AddMember(ctx context.Context, teamName, userID string) error {
+	db := s.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if err := s.checkTeamAndUser(teamName, userID); err != nil {
+		return err
+	}
+
+	members := db.memberships[teamName]
+	if _, ok := members[userID]; ok {
+		return nil
+	}
+	members[userID] = struct{}{}
+	db.onRollback(ctx, func() {
+		delete(db.memberships[teamName], userID)
+	})
+
+	return nil
+}
+
+func (s *teamStorage) RemoveMember(ctx context.Context, teamName, userID string) error {
+	db := s.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if err := s.checkTeamAndUser(teamName, userID); err != nil {
+		return err
+	}
+	if db.users[userID].TeamName == teamName {
+		return team.ErrPrimaryMembership
+	}
+
+	members := db.memberships[teamName]
+	if _, ok := members[userID]; !ok {
+		return team.ErrNotMember
+	}
+	delete(members, userID)
+	db.onRollback(ctx, func() {
+		db.memberships[teamName][userID] = struct{}{}
+	})
+
+	return nil
+}
+
+func (s *teamStorage) GetTeamNamesByUserID(_ context.Context, userID string) ([]string, error) {
+	db := s.db
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	if _, ok := db.users[userID]; !ok {
+		return nil, user.ErrUserNotFound
+	}
+
+	teams := make([]string, 0)
+	for name, members := range db.memberships {
+		if _, ok := members[userID]; ok {
+			teams = append(teams, name)
+		}
+	}
+	slices.Sort(teams)
+
+	return teams, nil
+}
+
+func (s *teamStorage) ListTeamNames(_ context.Context) ([]string, error) {
+	db := s.db
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	names := make([]string, 0, len(db.teams))
+	for name := range db.teams {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names, nil
+}
+
+func (s *teamStorage) checkTeamAndUser(teamName, userID string) error {
+	if _, ok := s.db.teams[teamName]; !ok {
+		return team.ErrTeamNotFound
+	}
+	if _, ok := s.db.users[userID]; !ok {
+		return user.ErrUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"InternshipTask/internal/domain/auth"
+	"context"
+	"slices"
+	"time"
+)
+
+type tokenStorage struct {
+	db *DB
+}
+
+func NewTokenStorage(db *DB) *tokenStorage {
+	return &tokenStorage{db: db}
+}
+
+var _ auth.Storager = (*tokenStorage)(nil)
+
+func (s *tokenStorage) Create(ctx context.Context, t *auth.Token) error {
+	db := s.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.tokens = append(db.tokens, cloneToken(*t))
+
+	id := t.ID
+	db.onRollback(ctx, func() {
+		db.tokens = slices.DeleteFunc(db.tokens, func(t auth.Token) bool { return t.ID == id })
+	})
+
+	return nil
+}
+
+func (s *tokenStorage) GetByHash(_ context.Context, hash string) (*auth.Token, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	for _, t := range s.db.tokens {
+		if t.Hash == hash {
+			c := cloneToken(t)
+			return &c, nil
+		}
+	}
+
+	return nil, auth.ErrTokenNotFound
+}
+
+func (s *tokenStorage) List(_ context.Context) ([]auth.Token, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	result := make([]auth.Token, 0, len(s.db.tokens))
+	for _, t := range s.db.tokens {
+		result = append(result, cloneToken(t))
+	}
+
+	return result, nil
+}
+
+func (s *tokenStorage) Revoke(ctx context.Context, id string, at time.Time) (*auth.Token, error) {
+	db := s.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	i := slices.IndexFunc(db.tokens, func(t auth.Token) bool { return t.ID == id })
+	if i < 0 {
+		return nil, auth.ErrTokenNotFound
+	}
+
+	t := &db.tokens[i]
+	if t.RevokedAt == nil {
+		t.RevokedAt = &at
+		db.onRollback(ctx, func() {
+			if i := slices.IndexFunc(db.tokens, func(t auth.Token) bool { return t.ID == id }); i >= 0 {
+				db.tokens[i].RevokedAt = nil
+			}
+		})
+	}
+
+	c := cloneToken(*t)
+	return &c, nil
+}
+
+func cloneToken(t auth.Token) auth.Token {
+	t.ExpiresAt = cloneTime(t.ExpiresAt)
+	t.RevokedAt = cloneTime(t.RevokedAt)
+	return t
+}
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"InternshipTask/internal/domain/user"
+	"context"
+	"slices"
+	"time"
+)
+
+type userStorage struct {
+	db *DB
+}
+
+func NewUserStorage(db *DB) *userStorage {
+	return &userStorage{db: db}
+}
+
+var _ user.Storager = (*userStorage)(nil)
+
+func (s *userStorage) GetByID(_ context.Context, id string) (*user.User, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	u, ok := s.db.users[id]
+	if !ok {
+		return nil, user.ErrUserNotFound
+	}
+
+	return cloneUser(u), nil
+}
+
+func (s *userStorage) SetIsActive(ctx context.Context, id string, isActive bool) (*user.User, error) {
+	return s.update(ctx, id, func(u *user.User) {
+		u.IsActive = isActive
+	})
+}
+
+func (s *userStorage) SetSkills(ctx context.Context, id string, skills []string) (*user.User, error) {
+	normalized := slices.Clone(skills)
+	slices.Sort(normalized)
+	normalized = slices.Compact(normalized)
+
+	return s.update(ctx, id, func(u *user.User) {
+		u.Skills = normalized
+	})
+}
+
+func (s *userStorage) SetSchedule(ctx context.Context, id string, schedule user.Schedule) (*user.User, error) {
+	return s.update(ctx, id, func(u *user.User) {
+		u.Schedule = schedule
+	})
+}
+
+func (s *userStorage) MarkDeparted(ctx context.Context, id string, at time.Time) (*user.User, error) {
+	return s.update(ctx, id, func(u *user.User) {
+		u.IsActive = false
+		if u.DepartedAt == nil {
+			u.DepartedAt = &at
+		}
+	})
+}
+
+// update applies fn to a copy of the user and swaps it in, so readers never
+// observe a half-applied change.
+func (s *userStorage) update(ctx context.Context, id string, fn func(u *user.User)) (*user.User, error) {
+	db := s.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	prev, ok := db.users[id]
+	if !ok {
+		return nil, user.ErrUserNotFound
+	}
+
+	u := cloneUser(prev)
+	fn(u)
+	db.users[id] = u
+	db.onRollback(ctx, func() {
+		db.users[id] = prev
+	})
+
+	return cloneUser(u), nil
+}
@@ -0,0 +1,116 @@
+package audit
+
+import (
+	domain "InternshipTask/internal/domain/audit"
+	"InternshipTask/internal/infrastructure/postgres"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresStorage struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresStorage(pool *pgxpool.Pool) *postgresStorage {
+	return &postgresStorage{
+		db: pool,
+	}
+}
+
+func (s *postgresStorage) conn(ctx context.Context) postgres.DBTX {
+	return postgres.Conn(ctx, s.db)
+}
+
+var _ domain.Storager = (*postgresStorage)(nil)
+
+func (s *postgresStorage) Append(ctx context.Context, e *domain.Entry) error {
+	query := `
+		INSERT INTO audit_log (at, actor, action, entity_type, entity_id, before, after)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, NULLIF($6, '')::jsonb, NULLIF($7, '')::jsonb)
+		RETURNING id
+	`
+
+	err := s.conn(ctx).QueryRow(ctx, query,
+		e.At,
+		e.Actor,
+		string(e.Action),
+		string(e.EntityType),
+		e.EntityID,
+		string(e.Before),
+		string(e.After),
+	).Scan(&e.ID)
+	if err != nil {
+		return fmt.Errorf("insert audit entry: %w", err)
+	}
+
+	return nil
+}
+
+func (s *postgresStorage) List(ctx context.Context, f domain.Filter) ([]domain.Entry, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	where := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if f.EntityType != "" {
+		where("entity_type = $%d", string(f.EntityType))
+	}
+	if f.EntityID != "" {
+		where("entity_id = $%d", f.EntityID)
+	}
+	if f.Actor != "" {
+		where("actor = $%d", f.Actor)
+	}
+	if !f.From.IsZero() {
+		where("at >= $%d", f.From)
+	}
+	if !f.To.IsZero() {
+		where("at < $%d", f.To)
+	}
+
+	query := `
+		SELECT id, at, COALESCE(actor, ''), action, entity_type, entity_id, before, after
+		FROM audit_log
+	`
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	query += " ORDER BY at DESC, id DESC"
+	if f.Limit > 0 {
+		args = append(args, f.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := s.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("select audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]domain.Entry, 0)
+	for rows.Next() {
+		var (
+			e          domain.Entry
+			action     string
+			entityType string
+		)
+		if err := rows.Scan(&e.ID, &e.At, &e.Actor, &action, &entityType, &e.EntityID, &e.Before, &e.After); err != nil {
+			return nil, fmt.Errorf("scan audit entry: %w", err)
+		}
+		e.Action = domain.Action(action)
+		e.EntityType = domain.EntityType(entityType)
+		result = append(result, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,61 @@
+package postgres_test
+
+import (
+	"InternshipTask/db"
+	"InternshipTask/internal/infrastructure/postgres"
+	auditpg "InternshipTask/internal/infrastructure/postgres/audit"
+	idempotencypg "InternshipTask/internal/infrastructure/postgres/idempotency"
+	"InternshipTask/internal/infrastructure/postgres/migrate"
+	outboxpg "InternshipTask/internal/infrastructure/postgres/outbox"
+	prpg "InternshipTask/internal/infrastructure/postgres/pull_request"
+	teampg "InternshipTask/internal/infrastructure/postgres/team"
+	tokenpg "InternshipTask/internal/infrastructure/postgres/token"
+	userpg "InternshipTask/internal/infrastructure/postgres/user"
+	"InternshipTask/internal/infrastructure/storagetest"
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestConformance runs against a disposable database given in
+// TEST_DATABASE_DSN; all its tables are truncated between subtests.
+func TestConformance(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	ctx := context.Background()
+	pool, err := postgres.NewPool(ctx, &postgres.Config{DatabaseDSN: dsn, ConnectTimeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("NewPool() error = %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	migrator, err := migrate.New(pool, db.Migrations())
+	if err != nil {
+		t.Fatalf("migrate.New() error = %v", err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+
+	storagetest.Run(t, func(t *testing.T) storagetest.Backend {
+		truncate := `TRUNCATE api_tokens, idempotency_keys, outbox, audit_log, review_assignments_archive, pull_requests_archive, review_assignments, pull_requests, user_skills, team_memberships, users, teams CASCADE`
+		if _, err := pool.Exec(ctx, truncate); err != nil {
+			t.Fatalf("truncate: %v", err)
+		}
+
+		return storagetest.Backend{
+			Teams:       teampg.NewPostgresStorage(pool),
+			Users:       userpg.NewPostgresStorage(pool),
+			PRs:         prpg.NewPostgresStorage(pool),
+			Audit:       auditpg.NewPostgresStorage(pool),
+			Outbox:      outboxpg.NewPostgresStorage(pool),
+			Idempotency: idempotencypg.NewPostgresStorage(pool),
+			Tokens:      tokenpg.NewPostgresStorage(pool),
+			Tx:          postgres.NewTxManager(pool),
+		}
+	})
+}
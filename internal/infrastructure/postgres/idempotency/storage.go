@@ -0,0 +1,99 @@
+package idempotency
+
+import (
+	"InternshipTask/internal/domain/idempotency"
+	"InternshipTask/internal/infrastructure/postgres"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresStorage struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresStorage(pool *pgxpool.Pool) *postgresStorage {
+	return &postgresStorage{
+		db: pool,
+	}
+}
+
+func (s *postgresStorage) conn(ctx context.Context) postgres.DBTX {
+	return postgres.Conn(ctx, s.db)
+}
+
+var _ idempotency.Store = (*postgresStorage)(nil)
+
+func (s *postgresStorage) Reserve(ctx context.Context, rec idempotency.Record) (*idempotency.Record, error) {
+	// An expired record is taken over as if it did not exist.
+	query := `
+		INSERT INTO idempotency_keys (key, fingerprint, created_at, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (key) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint, status = NULL, body = NULL,
+			created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+		RETURNING key
+	`
+
+	var key string
+	err := s.conn(ctx).QueryRow(ctx, query, rec.Key, rec.Fingerprint, rec.CreatedAt, rec.ExpiresAt).Scan(&key)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+
+	query = `
+		SELECT key, fingerprint, COALESCE(status, 0), body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE key = $1
+	`
+
+	var existing idempotency.Record
+	err = s.conn(ctx).QueryRow(ctx, query, rec.Key).Scan(
+		&existing.Key,
+		&existing.Fingerprint,
+		&existing.Status,
+		&existing.Body,
+		&existing.CreatedAt,
+		&existing.ExpiresAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("select idempotency key: %w", err)
+	}
+
+	return &existing, nil
+}
+
+func (s *postgresStorage) Complete(ctx context.Context, key string, status int, body []byte) error {
+	query := `UPDATE idempotency_keys SET status = $2, body = $3 WHERE key = $1`
+
+	if _, err := s.conn(ctx).Exec(ctx, query, key, status, body); err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func (s *postgresStorage) Release(ctx context.Context, key string) error {
+	if _, err := s.conn(ctx).Exec(ctx, `DELETE FROM idempotency_keys WHERE key = $1`, key); err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func (s *postgresStorage) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	tag, err := s.conn(ctx).Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, fmt.Errorf("delete expired idempotency keys: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,277 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrNoDownMigration = errors.New("migration has no down script")
+	ErrUnknownVersion  = errors.New("applied migration is unknown to this binary")
+)
+
+// lockKey is the pg_advisory_lock key that keeps several replicas from
+// migrating the same database at once.
+const lockKey int64 = 0x70725f6d6967
+
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Load reads NNN_name.up.sql / NNN_name.down.sql pairs from fsys and returns
+// them ordered by version. The down script is optional.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations dir: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		m := fileName.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("unexpected migration file name %q", e.Name())
+		}
+
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse version of %q: %w", e.Name(), err)
+		}
+		body, err := fs.ReadFile(fsys, path.Clean(e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read %q: %w", e.Name(), err)
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has two names: %q and %q", version, mig.Name, m[2])
+		}
+
+		if m[3] == "up" {
+			mig.Up = string(body)
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	result := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", mig.Version, mig.Name)
+		}
+		result = append(result, *mig)
+	}
+	slices.SortFunc(result, func(a, b Migration) int {
+		return int(a.Version - b.Version)
+	})
+
+	return result, nil
+}
+
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+func New(pool *pgxpool.Pool, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		pool:       pool,
+		migrations: migrations,
+	}, nil
+}
+
+// Up applies all pending migrations in version order, each in its own
+// transaction, and returns the ones it applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	applied := make([]Migration, 0)
+
+	err := m.locked(ctx, func(conn *pgxpool.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := done[mig.Version]; ok {
+				continue
+			}
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, mig.Up); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx,
+					"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)",
+					mig.Version, mig.Name)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("apply %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			applied = append(applied, mig)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down rolls back the last steps applied migrations and returns them.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	rolledBack := make([]Migration, 0, steps)
+
+	err := m.locked(ctx, func(conn *pgxpool.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		versions := make([]int64, 0, len(done))
+		for v := range done {
+			versions = append(versions, v)
+		}
+		slices.Sort(versions)
+		slices.Reverse(versions)
+
+		for _, v := range versions[:min(steps, len(versions))] {
+			idx := slices.IndexFunc(m.migrations, func(mig Migration) bool { return mig.Version == v })
+			if idx == -1 {
+				return fmt.Errorf("roll back %d: %w", v, ErrUnknownVersion)
+			}
+			mig := m.migrations[idx]
+			if mig.Down == "" {
+				return fmt.Errorf("roll back %d_%s: %w", mig.Version, mig.Name, ErrNoDownMigration)
+			}
+
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, mig.Down); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, "DELETE FROM schema_migrations WHERE version = $1", mig.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("roll back %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			rolledBack = append(rolledBack, mig)
+		}
+
+		return nil
+	})
+
+	return rolledBack, err
+}
+
+// Status reports every known migration together with the time it was applied,
+// nil for pending ones.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	result := make([]Status, 0, len(m.migrations))
+
+	err := m.locked(ctx, func(conn *pgxpool.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			st := Status{Migration: mig}
+			if at, ok := done[mig.Version]; ok {
+				st.AppliedAt = &at
+			}
+			result = append(result, st)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// Seed runs an idempotent data script, such as the demo data, in one transaction.
+func (m *Migrator) Seed(ctx context.Context, script string) error {
+	return m.locked(ctx, func(conn *pgxpool.Conn) error {
+		return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, script); err != nil {
+				return fmt.Errorf("apply seed: %w", err)
+			}
+			return nil
+		})
+	})
+}
+
+func (m *Migrator) locked(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey)
+
+	createQuery := `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    BIGINT PRIMARY KEY,
+			name       TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`
+	if _, err := conn.Exec(ctx, createQuery); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+func appliedVersions(ctx context.Context, conn *pgxpool.Conn) (map[int64]time.Time, error) {
+	rows, err := conn.Query(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("select schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	done := make(map[int64]time.Time)
+	for rows.Next() {
+		var (
+			version int64
+			at      time.Time
+		)
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, fmt.Errorf("scan schema_migrations: %w", err)
+		}
+		done[version] = at
+	}
+
+	return done, rows.Err()
+}
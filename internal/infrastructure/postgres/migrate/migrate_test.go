@@ -0,0 +1,65 @@
+package migrate
+
+import (
+	"InternshipTask/db"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad_OrdersByVersionAndPairsScripts(t *testing.T) {
+	fsys := fstest.MapFS{
+		"010_add_index.up.sql":   {Data: []byte("CREATE INDEX i ON t(c);")},
+		"002_create.up.sql":      {Data: []byte("CREATE TABLE t (c INT);")},
+		"002_create.down.sql":    {Data: []byte("DROP TABLE t;")},
+		"010_add_index.down.sql": {Data: []byte("DROP INDEX i;")},
+		"011_backfill.up.sql":    {Data: []byte("UPDATE t SET c = 1;")},
+	}
+
+	migrations, err := Load(fsys)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(migrations) != 3 {
+		t.Fatalf("expected 3 migrations, got %d", len(migrations))
+	}
+	if migrations[0].Version != 2 || migrations[1].Version != 10 || migrations[2].Version != 11 {
+		t.Fatalf("unexpected order: %+v", migrations)
+	}
+	if migrations[0].Name != "create" || migrations[0].Down != "DROP TABLE t;" {
+		t.Fatalf("unexpected migration: %+v", migrations[0])
+	}
+	if migrations[2].Down != "" {
+		t.Fatalf("expected 011 to have no down script")
+	}
+}
+
+func TestLoad_RejectsBrokenSets(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"bad name":     {"init.sql": {Data: []byte("SELECT 1;")}},
+		"missing up":   {"001_init.down.sql": {Data: []byte("SELECT 1;")}},
+		"name clash":   {"001_a.up.sql": {Data: []byte("SELECT 1;")}, "001_b.up.sql": {Data: []byte("SELECT 1;")}},
+		"no extension": {"001_init.up": {Data: []byte("SELECT 1;")}},
+	}
+
+	for name, fsys := range cases {
+		if _, err := Load(fsys); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestLoad_EmbeddedMigrationsAreReversible(t *testing.T) {
+	migrations, err := Load(db.Migrations())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatalf("expected embedded migrations")
+	}
+	for _, m := range migrations {
+		if m.Down == "" {
+			t.Fatalf("migration %d_%s has no down script", m.Version, m.Name)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package outbox
+
+import (
+	"InternshipTask/internal/domain/events"
+	"InternshipTask/internal/infrastructure/postgres"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresStorage struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresStorage(pool *pgxpool.Pool) *postgresStorage {
+	return &postgresStorage{
+		db: pool,
+	}
+}
+
+func (s *postgresStorage) conn(ctx context.Context) postgres.DBTX {
+	return postgres.Conn(ctx, s.db)
+}
+
+var _ events.Outbox = (*postgresStorage)(nil)
+
+func (s *postgresStorage) Append(ctx context.Context, e *events.Event) error {
+	query := `
+		INSERT INTO outbox (event_type, aggregate_type, aggregate_id, payload, occurred_at, attempts, next_attempt_at, last_error)
+		VALUES ($1, $2, $3, $4::jsonb, $5, $6, $7, NULLIF($8, ''))
+		RETURNING id
+	`
+
+	err := s.conn(ctx).QueryRow(ctx, query,
+		string(e.Type),
+		e.AggregateType,
+		e.AggregateID,
+		string(e.Payload),
+		e.OccurredAt,
+		e.Attempts,
+		e.NextAttemptAt,
+		e.LastError,
+	).Scan(&e.ID)
+	if err != nil {
+		return fmt.Errorf("insert outbox event: %w", err)
+	}
+
+	return nil
+}
+
+func (s *postgresStorage) Pending(ctx context.Context, now time.Time, limit int) ([]events.Event, error) {
+	query := `
+		SELECT id, event_type, aggregate_type, aggregate_id, payload, occurred_at, attempts, next_attempt_at, COALESCE(last_error, '')
+		FROM (
+			SELECT DISTINCT ON (aggregate_type, aggregate_id) *
+			FROM outbox
+			WHERE parked_at IS NULL
+			ORDER BY aggregate_type, aggregate_id, id
+		) heads
+		WHERE next_attempt_at <= $1
+		ORDER BY id
+		LIMIT $2
+	`
+
+	rows, err := s.conn(ctx).Query(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("select pending events: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]events.Event, 0)
+	for rows.Next() {
+		var (
+			e         events.Event
+			eventType string
+		)
+		if err := rows.Scan(&e.ID, &eventType, &e.AggregateType, &e.AggregateID, &e.Payload, &e.OccurredAt, &e.Attempts, &e.NextAttemptAt, &e.LastError); err != nil {
+			return nil, fmt.Errorf("scan outbox event: %w", err)
+		}
+		e.Type = events.Type(eventType)
+		result = append(result, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return result, nil
+}
+
+func (s *postgresStorage) MarkDelivered(ctx context.Context, id int64) error {
+	if _, err := s.conn(ctx).Exec(ctx, `DELETE FROM outbox WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("delete delivered event: %w", err)
+	}
+
+	return nil
+}
+
+func (s *postgresStorage) MarkFailed(ctx context.Context, e events.Event) error {
+	query := `
+		UPDATE outbox
+		SET attempts = $2, next_attempt_at = $3, last_error = NULLIF($4, ''), parked_at = $5
+		WHERE id = $1
+	`
+
+	if _, err := s.conn(ctx).Exec(ctx, query, e.ID, e.Attempts, e.NextAttemptAt, e.LastError, e.ParkedAt); err != nil {
+		return fmt.Errorf("update failed event: %w", err)
+	}
+
+	return nil
+}
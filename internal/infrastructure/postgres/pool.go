@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Config struct {
+	DatabaseDSN     string
+	ConnectTimeout  time.Duration
+	MaxConns        int32
+	MinConns        int32
+	MaxConnLifetime time.Duration
+	MaxConnIdleTime time.Duration
+}
+
+var (
+	ErrConnectTimeout = errors.New("connect timeout")
+)
+
+// NewPool opens the connection pool shared by all Postgres storages. Zero
+// values in cfg keep the pgxpool defaults.
+func NewPool(ctx context.Context, cfg *Config) (*pgxpool.Pool, error) {
+	poolCfg, err := pgxpool.ParseConfig(cfg.DatabaseDSN)
+	if err != nil {
+		return nil, fmt.Errorf("parse postgres DSN: %w", err)
+	}
+
+	if cfg.MaxConns > 0 {
+		poolCfg.MaxConns = cfg.MaxConns
+	}
+	if cfg.MinConns > 0 {
+		poolCfg.MinConns = cfg.MinConns
+	}
+	if cfg.MaxConnLifetime > 0 {
+		poolCfg.MaxConnLifetime = cfg.MaxConnLifetime
+	}
+	if cfg.MaxConnIdleTime > 0 {
+		poolCfg.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
+	poolCfg.ConnConfig.Tracer = queryTracer{}
+
+	connCtx, cancel := context.WithTimeoutCause(ctx, cfg.ConnectTimeout, ErrConnectTimeout)
+	defer cancel()
+
+	pool, err := pgxpool.NewWithConfig(connCtx, poolCfg)
+	if err != nil {
+		return nil, fmt.Errorf("postgres pool: %w", err)
+	}
+
+	if err := pool.Ping(connCtx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("postgres ping: %w", err)
+	}
+
+	return pool, nil
+}
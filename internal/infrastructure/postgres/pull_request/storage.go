@@ -2,60 +2,72 @@ package pull_request
 
 import (
 	domain "InternshipTask/internal/domain/pull_request"
+	"InternshipTask/internal/infrastructure/postgres"
 	"context"
 	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"slices"
+	"strings"
 	"time"
 )
 
-type Config struct {
-	DatabaseDSN    string
-	ConnectTimeout time.Duration
-}
-
 type postgresStorage struct {
-	db *pgx.Conn
+	db *pgxpool.Pool
 }
 
-var (
-	ErrConnectTimeout = errors.New("connect timeout")
-)
-
-func NewPostgresStorage(cfg *Config) (*postgresStorage, error) {
-	connCtx, cancel := context.WithTimeoutCause(context.Background(), cfg.ConnectTimeout, ErrConnectTimeout)
-	defer cancel()
-	conn, err := pgx.Connect(connCtx, cfg.DatabaseDSN)
-	if err != nil {
-		return nil, fmt.Errorf("postgres connect: %w", err)
+func NewPostgresStorage(pool *pgxpool.Pool) *postgresStorage {
+	return &postgresStorage{
+		db: pool,
 	}
+}
 
-	return &postgresStorage{
-		db: conn,
-	}, nil
+func (s *postgresStorage) conn(ctx context.Context) postgres.DBTX {
+	return postgres.Conn(ctx, s.db)
 }
 
 var _ domain.Repository = (*postgresStorage)(nil)
 
+// Create inserts the PR together with the assignments of its initial
+// reviewers.
 func (s *postgresStorage) Create(ctx context.Context, pr *domain.PR) error {
+	tx, err := s.conn(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var archived bool
+	err = tx.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM pull_requests_archive WHERE pull_request_id = $1)", pr.PullRequestId).Scan(&archived)
+	if err != nil {
+		return fmt.Errorf("check archived pull_request: %w", err)
+	}
+	if archived {
+		return fmt.Errorf("insert pull_request: archived: %w", domain.ErrPRExists)
+	}
+
 	query := `
 		INSERT INTO pull_requests (
 			pull_request_id,
 			pull_request_name,
 			author_id,
+			team_name,
 			status,
-			assigned_reviewers,
+			required_skills,
 			created_at,
-			merged_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			merged_at,
+			version
+		) VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8, 1)
 	`
 
-	_, err := s.db.Exec(ctx, query,
+	_, err = tx.Exec(ctx, query,
 		pr.PullRequestId,
 		pr.PullRequestName,
 		pr.AuthorId,
+		pr.TeamName,
 		pr.Status.String(),
-		pr.AssignedReviewers,
+		pr.RequiredSkills,
 		pr.CreatedAt,
 		pr.MergedAt,
 	)
@@ -63,6 +75,18 @@ func (s *postgresStorage) Create(ctx context.Context, pr *domain.PR) error {
 		return fmt.Errorf("insert pull_request: %w", err)
 	}
 
+	for slot, reviewerID := range pr.AssignedReviewers {
+		if err := insertAssignment(ctx, tx, pr, reviewerID, slot); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	pr.Version = 1
+
 	return nil
 }
 
@@ -72,15 +96,23 @@ func (s *postgresStorage) GetByID(ctx context.Context, id string) (*domain.PR, e
 			pull_request_id,
 			pull_request_name,
 			author_id,
+			COALESCE(team_name, ''),
 			status,
-			assigned_reviewers,
+			ARRAY(
+				SELECT reviewer_id
+				FROM review_assignments
+				WHERE pull_request_id = $1 AND unassigned_at IS NULL
+				ORDER BY slot, assigned_at
+			),
+			required_skills,
 			created_at,
-			merged_at
+			merged_at,
+			version
 		FROM pull_requests
 		WHERE pull_request_id = $1
 	`
 
-	row := s.db.QueryRow(ctx, query, id)
+	row := s.conn(ctx).QueryRow(ctx, query, id)
 
 	var pr domain.PR
 	var status string
@@ -89,10 +121,13 @@ func (s *postgresStorage) GetByID(ctx context.Context, id string) (*domain.PR, e
 		&pr.PullRequestId,
 		&pr.PullRequestName,
 		&pr.AuthorId,
+		&pr.TeamName,
 		&status,
 		&pr.AssignedReviewers,
+		&pr.RequiredSkills,
 		&pr.CreatedAt,
 		&pr.MergedAt,
+		&pr.Version,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, domain.ErrNotFound
@@ -106,44 +141,172 @@ func (s *postgresStorage) GetByID(ctx context.Context, id string) (*domain.PR, e
 	return &pr, nil
 }
 
+// Update writes pr only if nobody has updated it since pr.Version was read,
+// and bumps pr.Version on success. Reviewers that left AssignedReviewers get
+// their assignment closed and new ones get an assignment opened, both
+// attributed to pr.ReviewerChange.
 func (s *postgresStorage) Update(ctx context.Context, pr *domain.PR) error {
+	tx, err := s.conn(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
 		UPDATE pull_requests
 		SET
 			status = $2,
-			assigned_reviewers = $3,
-			merged_at = $4
-		WHERE pull_request_id = $1
+			merged_at = $3,
+			author_id = $4,
+			version = version + 1
+		WHERE pull_request_id = $1 AND version = $5
+		RETURNING version
 	`
 
-	_, err := s.db.Exec(ctx, query,
+	var version int64
+	err = tx.QueryRow(ctx, query,
 		pr.PullRequestId,
 		pr.Status.String(),
-		pr.AssignedReviewers,
 		pr.MergedAt,
-	)
+		pr.AuthorId,
+		pr.Version,
+	).Scan(&version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		var exists bool
+		if err := tx.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM pull_requests WHERE pull_request_id = $1)", pr.PullRequestId).Scan(&exists); err != nil {
+			return fmt.Errorf("check pull_request exists: %w", err)
+		}
+		if !exists {
+			return domain.ErrNotFound
+		}
+		return domain.ErrConflict
+	}
 	if err != nil {
 		return fmt.Errorf("update pull_request: %w", err)
 	}
 
+	if err := syncAssignments(ctx, tx, pr); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	pr.Version = version
+
+	return nil
+}
+
+func syncAssignments(ctx context.Context, tx pgx.Tx, pr *domain.PR) error {
+	rows, err := tx.Query(ctx, `
+		SELECT reviewer_id
+		FROM review_assignments
+		WHERE pull_request_id = $1 AND unassigned_at IS NULL
+	`, pr.PullRequestId)
+	if err != nil {
+		return fmt.Errorf("select active assignments: %w", err)
+	}
+	active, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("scan active assignments: %w", err)
+	}
+
+	change := pr.ReviewerChange
+	for _, reviewerID := range active {
+		if slices.Contains(pr.AssignedReviewers, reviewerID) {
+			continue
+		}
+		query := `
+			UPDATE review_assignments
+			SET unassigned_at = $3, unassign_reason = $4, unassigned_by = NULLIF($5, '')
+			WHERE pull_request_id = $1 AND reviewer_id = $2 AND unassigned_at IS NULL
+		`
+		if _, err := tx.Exec(ctx, query, pr.PullRequestId, reviewerID, changeTime(change), string(change.Reason), change.Actor); err != nil {
+			return fmt.Errorf("close assignment of %s: %w", reviewerID, err)
+		}
+	}
+
+	for slot, reviewerID := range pr.AssignedReviewers {
+		if slices.Contains(active, reviewerID) {
+			continue
+		}
+		if err := insertAssignment(ctx, tx, pr, reviewerID, slot); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func (s *postgresStorage) GetByReviewerID(ctx context.Context, reviewerID string) ([]domain.PullRequestShort, error) {
+func insertAssignment(ctx context.Context, tx pgx.Tx, pr *domain.PR, reviewerID string, slot int) error {
+	change := pr.ReviewerChange
 	query := `
-		SELECT 
+		INSERT INTO review_assignments (pull_request_id, reviewer_id, slot, assigned_at, assigned_reason, assigned_by)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+	`
+	if _, err := tx.Exec(ctx, query, pr.PullRequestId, reviewerID, slot, changeTime(change), string(change.Reason), change.Actor); err != nil {
+		return fmt.Errorf("insert assignment of %s: %w", reviewerID, err)
+	}
+	return nil
+}
+
+func changeTime(change domain.ReviewerChange) time.Time {
+	if change.At.IsZero() {
+		return time.Now().UTC()
+	}
+	return change.At
+}
+
+func (s *postgresStorage) GetByReviewerID(ctx context.Context, reviewerID string, includeArchived bool) ([]domain.PullRequestShort, error) {
+	query := `
+		SELECT pull_request_id, pull_request_name, author_id, status
+		FROM (
+			SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at
+			FROM pull_requests AS pr
+			JOIN review_assignments AS ra ON ra.pull_request_id = pr.pull_request_id
+			WHERE ra.reviewer_id = $1 AND ra.unassigned_at IS NULL
+			UNION ALL
+			SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at
+			FROM pull_requests_archive AS pr
+			JOIN review_assignments_archive AS ra ON ra.pull_request_id = pr.pull_request_id
+			WHERE $2 AND ra.reviewer_id = $1 AND ra.unassigned_at IS NULL
+		) AS reviews
+		ORDER BY created_at
+	`
+
+	result, err := s.queryShorts(ctx, query, reviewerID, includeArchived)
+	if err != nil {
+		return nil, fmt.Errorf("select pull_requests by reviewer: %w", err)
+	}
+
+	return result, nil
+}
+
+func (s *postgresStorage) GetOpenByAuthorID(ctx context.Context, authorID string) ([]domain.PullRequestShort, error) {
+	query := `
+		SELECT
 			pull_request_id,
 			pull_request_name,
 			author_id,
 			status
 		FROM pull_requests
-		WHERE $1 = ANY(assigned_reviewers)
+		WHERE author_id = $1 AND status = 'OPEN'
 		ORDER BY created_at
 	`
 
-	rows, err := s.db.Query(ctx, query, reviewerID)
+	result, err := s.queryShorts(ctx, query, authorID)
 	if err != nil {
-		return nil, fmt.Errorf("select pull_requests by reviewer: %w", err)
+		return nil, fmt.Errorf("select open pull_requests by author: %w", err)
+	}
+
+	return result, nil
+}
+
+func (s *postgresStorage) queryShorts(ctx context.Context, query string, args ...any) ([]domain.PullRequestShort, error) {
+	rows, err := s.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
 	}
 	defer rows.Close()
 
@@ -176,17 +339,20 @@ func (s *postgresStorage) GetByReviewerID(ctx context.Context, reviewerID string
 	return result, nil
 }
 
-func (s *postgresStorage) GetReviewerStats(ctx context.Context) (map[string]int64, error) {
+// GetReviewerStats counts every assignment a reviewer has ever had, including
+// the ones that were later reassigned.
+func (s *postgresStorage) GetReviewerStats(ctx context.Context, includeArchived bool) (map[string]int64, error) {
 	query := `
 		SELECT reviewer_id, COUNT(*) AS assign_count
 		FROM (
-			SELECT unnest(assigned_reviewers) AS reviewer_id
-			FROM pull_requests
-		) AS t
+			SELECT reviewer_id FROM review_assignments
+			UNION ALL
+			SELECT reviewer_id FROM review_assignments_archive WHERE $1
+		) AS assignments
 		GROUP BY reviewer_id
 	`
 
-	rows, err := s.db.Query(ctx, query)
+	rows, err := s.conn(ctx).Query(ctx, query, includeArchived)
 	if err != nil {
 		return nil, fmt.Errorf("select reviewer stats: %w", err)
 	}
@@ -209,3 +375,360 @@ func (s *postgresStorage) GetReviewerStats(ctx context.Context) (map[string]int6
 
 	return stats, nil
 }
+
+func (s *postgresStorage) ListReviews(ctx context.Context, f domain.ReviewFilter) ([]domain.Review, error) {
+	args := []any{f.ReviewerID}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conds := []string{"ra.reviewer_id = $1", "ra.unassigned_at IS NULL"}
+	if f.Status != "" {
+		conds = append(conds, "pr.status = "+arg(f.Status.String()))
+	}
+	if f.TeamName != "" {
+		conds = append(conds, "pr.team_name = "+arg(f.TeamName))
+	}
+	if !f.CreatedFrom.IsZero() {
+		conds = append(conds, "pr.created_at >= "+arg(f.CreatedFrom))
+	}
+	if !f.CreatedTo.IsZero() {
+		conds = append(conds, "pr.created_at < "+arg(f.CreatedTo))
+	}
+	if f.After != nil {
+		conds = append(conds, fmt.Sprintf("(pr.created_at, pr.pull_request_id) > (%s, %s)", arg(f.After.CreatedAt), arg(f.After.ID)))
+	}
+	var limit string
+	if f.Limit > 0 {
+		limit = " LIMIT " + arg(f.Limit)
+	}
+
+	branch := func(prs, assignments string) string {
+		return fmt.Sprintf(`
+			SELECT
+				pr.pull_request_id,
+				pr.pull_request_name,
+				pr.author_id,
+				pr.status,
+				COALESCE(pr.team_name, '') AS team_name,
+				pr.created_at,
+				pr.merged_at,
+				ra.assigned_at,
+				ra.verdict,
+				ra.verdict_at
+			FROM %s AS pr
+			JOIN %s AS ra ON ra.pull_request_id = pr.pull_request_id
+			WHERE %s
+			ORDER BY pr.created_at, pr.pull_request_id
+		`, prs, assignments, strings.Join(conds, " AND ")) + limit
+	}
+
+	query := branch("pull_requests", "review_assignments")
+	if f.IncludeArchived {
+		query = "SELECT * FROM (" + query + ") AS live UNION ALL SELECT * FROM (" +
+			branch("pull_requests_archive", "review_assignments_archive") + ") AS archived" +
+			" ORDER BY created_at, pull_request_id" + limit
+	}
+
+	rows, err := s.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("select reviews: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]domain.Review, 0)
+	for rows.Next() {
+		var (
+			r       domain.Review
+			status  string
+			verdict string
+		)
+		err := rows.Scan(&r.PullRequestId, &r.PullRequestName, &r.AuthorId, &status, &r.TeamName,
+			&r.CreatedAt, &r.MergedAt, &r.AssignedAt, &verdict, &r.VerdictAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan review: %w", err)
+		}
+		r.Status = domain.PullRequestStatus(status)
+		r.Verdict = domain.Verdict(verdict)
+		result = append(result, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return result, nil
+}
+
+func (s *postgresStorage) SetVerdict(ctx context.Context, prID, reviewerID string, verdict domain.Verdict, at time.Time) error {
+	query := `
+		UPDATE review_assignments
+		SET verdict = $3, verdict_at = $4
+		WHERE pull_request_id = $1 AND reviewer_id = $2 AND unassigned_at IS NULL
+	`
+
+	tag, err := s.conn(ctx).Exec(ctx, query, prID, reviewerID, string(verdict), at)
+	if err != nil {
+		return fmt.Errorf("update verdict: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotAssigned
+	}
+
+	return nil
+}
+
+func (s *postgresStorage) GetOpenReviewCounts(ctx context.Context, reviewerIDs []string) (map[string]int64, error) {
+	query := `
+		SELECT ra.reviewer_id, COUNT(*)
+		FROM review_assignments AS ra
+		JOIN pull_requests AS pr ON pr.pull_request_id = ra.pull_request_id
+		WHERE ra.reviewer_id = ANY($1) AND ra.unassigned_at IS NULL AND pr.status = 'OPEN'
+		GROUP BY ra.reviewer_id
+	`
+
+	rows, err := s.conn(ctx).Query(ctx, query, reviewerIDs)
+	if err != nil {
+		return nil, fmt.Errorf("select open review counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+
+	for rows.Next() {
+		var reviewerID string
+		var count int64
+		if err := rows.Scan(&reviewerID, &count); err != nil {
+			return nil, fmt.Errorf("scan open review counts: %w", err)
+		}
+		counts[reviewerID] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (s *postgresStorage) ArchiveMerged(ctx context.Context, mergedBefore time.Time, limit int) (int, error) {
+	tx, err := s.conn(ctx).Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT pull_request_id
+		FROM pull_requests
+		WHERE status = 'MERGED' AND merged_at < $1
+		ORDER BY merged_at, pull_request_id
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`, mergedBefore, limit)
+	if err != nil {
+		return 0, fmt.Errorf("select merged pull_requests: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, fmt.Errorf("scan merged pull_requests: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	statements := []string{
+		`INSERT INTO pull_requests_archive (pull_request_id, pull_request_name, author_id, team_name, status, required_skills, created_at, merged_at, version)
+		 SELECT pull_request_id, pull_request_name, author_id, team_name, status, required_skills, created_at, merged_at, version
+		 FROM pull_requests WHERE pull_request_id = ANY($1)`,
+		`INSERT INTO review_assignments_archive
+		 SELECT id, pull_request_id, reviewer_id, slot, assigned_at, assigned_reason, assigned_by, unassigned_at, unassign_reason, unassigned_by, verdict, verdict_at
+		 FROM review_assignments WHERE pull_request_id = ANY($1)`,
+		// review_assignments go with the PRs through ON DELETE CASCADE.
+		`DELETE FROM pull_requests WHERE pull_request_id = ANY($1)`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(ctx, stmt, ids); err != nil {
+			return 0, fmt.Errorf("archive pull_requests: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return len(ids), nil
+}
+
+func (s *postgresStorage) List(ctx context.Context, f domain.ListFilter) ([]domain.PR, error) {
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var conds []string
+	if f.Status != "" {
+		conds = append(conds, "pr.status = "+arg(f.Status.String()))
+	}
+	if f.AuthorID != "" {
+		conds = append(conds, "pr.author_id = "+arg(f.AuthorID))
+	}
+	if f.TeamName != "" {
+		conds = append(conds, "pr.team_name = "+arg(f.TeamName))
+	}
+	if f.Name != "" {
+		conds = append(conds, "pr.pull_request_name ILIKE "+arg(containsPattern(f.Name))+` ESCAPE '\'`)
+	}
+	if !f.CreatedFrom.IsZero() {
+		conds = append(conds, "pr.created_at >= "+arg(f.CreatedFrom))
+	}
+	if !f.CreatedTo.IsZero() {
+		conds = append(conds, "pr.created_at < "+arg(f.CreatedTo))
+	}
+	if !f.MergedFrom.IsZero() {
+		conds = append(conds, "pr.merged_at >= "+arg(f.MergedFrom))
+	}
+	if !f.MergedTo.IsZero() {
+		conds = append(conds, "pr.merged_at < "+arg(f.MergedTo))
+	}
+	var reviewer string
+	if f.ReviewerID != "" {
+		reviewer = arg(f.ReviewerID)
+	}
+
+	after, order := ">", "ASC"
+	if f.Sort == domain.SortCreatedDesc {
+		after, order = "<", "DESC"
+	}
+	if f.After != nil {
+		conds = append(conds, fmt.Sprintf("(pr.created_at, pr.pull_request_id) %s (%s, %s)", after, arg(f.After.CreatedAt), arg(f.After.ID)))
+	}
+	orderBy := fmt.Sprintf(" ORDER BY created_at %[1]s, pull_request_id %[1]s", order)
+	if f.Limit > 0 {
+		orderBy += " LIMIT " + arg(f.Limit)
+	}
+
+	// Each table is ordered and limited on its own, so that both can walk
+	// their (created_at, pull_request_id) index, and the results are merged.
+	branch := func(prs, assignments string) string {
+		where := slices.Clone(conds)
+		if reviewer != "" {
+			where = append(where, fmt.Sprintf(`EXISTS (
+				SELECT 1 FROM %s AS ra
+				WHERE ra.pull_request_id = pr.pull_request_id AND ra.reviewer_id = %s AND ra.unassigned_at IS NULL
+			)`, assignments, reviewer))
+		}
+		query := fmt.Sprintf(`
+			SELECT
+				pr.pull_request_id,
+				pr.pull_request_name,
+				pr.author_id,
+				COALESCE(pr.team_name, '') AS team_name,
+				pr.status,
+				ARRAY(
+					SELECT ra.reviewer_id
+					FROM %[2]s AS ra
+					WHERE ra.pull_request_id = pr.pull_request_id AND ra.unassigned_at IS NULL
+					ORDER BY ra.slot, ra.assigned_at
+				) AS assigned_reviewers,
+				pr.required_skills,
+				pr.created_at,
+				pr.merged_at,
+				pr.version
+			FROM %[1]s AS pr
+		`, prs, assignments)
+		if len(where) > 0 {
+			query += " WHERE " + strings.Join(where, " AND ")
+		}
+		return query + orderBy
+	}
+
+	query := branch("pull_requests", "review_assignments")
+	if f.IncludeArchived {
+		query = "SELECT * FROM (" + query + ") AS live UNION ALL SELECT * FROM (" +
+			branch("pull_requests_archive", "review_assignments_archive") + ") AS archived" + orderBy
+	}
+
+	rows, err := s.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("select pull_requests: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]domain.PR, 0)
+	for rows.Next() {
+		var pr domain.PR
+		var status string
+		err := rows.Scan(&pr.PullRequestId, &pr.PullRequestName, &pr.AuthorId, &pr.TeamName, &status,
+			&pr.AssignedReviewers, &pr.RequiredSkills, &pr.CreatedAt, &pr.MergedAt, &pr.Version)
+		if err != nil {
+			return nil, fmt.Errorf("scan pull_request: %w", err)
+		}
+		pr.Status = domain.PullRequestStatus(status)
+		result = append(result, pr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return result, nil
+}
+
+// containsPattern is a LIKE pattern matching s anywhere, with the wildcards
+// in s escaped.
+func containsPattern(s string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s) + "%"
+}
+
+func (s *postgresStorage) GetAssignments(ctx context.Context, prID string) ([]domain.Assignment, error) {
+	query := `
+		SELECT
+			reviewer_id,
+			assigned_at,
+			assigned_reason,
+			COALESCE(assigned_by, ''),
+			unassigned_at,
+			COALESCE(unassign_reason, ''),
+			COALESCE(unassigned_by, ''),
+			verdict,
+			verdict_at
+		FROM review_assignments
+		WHERE pull_request_id = $1
+		ORDER BY assigned_at, id
+	`
+
+	rows, err := s.conn(ctx).Query(ctx, query, prID)
+	if err != nil {
+		return nil, fmt.Errorf("select assignments: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]domain.Assignment, 0)
+
+	for rows.Next() {
+		var (
+			a              domain.Assignment
+			assignedReason string
+			unassignReason string
+			verdict        string
+		)
+		err := rows.Scan(&a.ReviewerID, &a.AssignedAt, &assignedReason, &a.AssignedBy,
+			&a.UnassignedAt, &unassignReason, &a.UnassignedBy, &verdict, &a.VerdictAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan assignment: %w", err)
+		}
+		a.AssignedReason = domain.AssignmentReason(assignedReason)
+		a.UnassignReason = domain.AssignmentReason(unassignReason)
+		a.Verdict = domain.Verdict(verdict)
+		result = append(result, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return result, nil
+}
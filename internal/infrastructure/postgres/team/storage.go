@@ -3,47 +3,41 @@ package team
 import (
 	"InternshipTask/internal/domain/team"
 	"InternshipTask/internal/domain/user"
+	"InternshipTask/internal/infrastructure/postgres"
 	"context"
 	"errors"
 	"fmt"
-	"github.com/jackc/pgx/v5"
-	"time"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-type Config struct {
-	DatabaseDSN    string
-	ConnectTimeout time.Duration
-}
-
 type postgresStorage struct {
-	db *pgx.Conn
+	db *pgxpool.Pool
 }
 
 var (
-	ErrTeamNotFound   = errors.New("team not found")
 	ErrQueryExecution = errors.New("query execution failed")
-	ErrConnectTimeout = errors.New("connect timeout")
 )
 
-func NewPostgresStorage(cfg *Config) (*postgresStorage, error) {
-	connCtx, cancel := context.WithTimeoutCause(context.Background(), cfg.ConnectTimeout, ErrConnectTimeout)
-	defer cancel()
-	conn, err := pgx.Connect(connCtx, cfg.DatabaseDSN)
-	if err != nil {
-		return nil, fmt.Errorf("postgres connect: %w", err)
+func NewPostgresStorage(pool *pgxpool.Pool) *postgresStorage {
+	return &postgresStorage{
+		db: pool,
 	}
+}
 
-	return &postgresStorage{
-		db: conn,
-	}, nil
+func (s *postgresStorage) conn(ctx context.Context) postgres.DBTX {
+	return postgres.Conn(ctx, s.db)
 }
 
 func (s *postgresStorage) Create(ctx context.Context, t team.Team) error {
 	query := `INSERT INTO teams (team_name) VALUES ($1)
 	          ON CONFLICT (team_name) DO NOTHING`
-	if _, err := s.db.Exec(ctx, query, t.TeamName); err != nil {
+	tag, err := s.conn(ctx).Exec(ctx, query, t.TeamName)
+	if err != nil {
 		return fmt.Errorf("insert team: %w", ErrQueryExecution)
 	}
+	if tag.RowsAffected() == 0 {
+		return team.ErrTeamExists
+	}
 
 	for _, member := range t.Members {
 		upsertUserQuery := `
@@ -53,9 +47,9 @@ func (s *postgresStorage) Create(ctx context.Context, t team.Team) error {
 			DO UPDATE SET
 				username  = EXCLUDED.username,
 				team_name = EXCLUDED.team_name,
-				is_active = EXCLUDED.is_active
+				is_active = EXCLUDED.is_active AND users.departed_at IS NULL
 		`
-		if _, err := s.db.Exec(ctx, upsertUserQuery,
+		if _, err := s.conn(ctx).Exec(ctx, upsertUserQuery,
 			member.UserId,
 			member.UserName,
 			t.TeamName,
@@ -63,6 +57,15 @@ func (s *postgresStorage) Create(ctx context.Context, t team.Team) error {
 		); err != nil {
 			return fmt.Errorf("upsert user %s: %w", member.UserId, ErrQueryExecution)
 		}
+
+		membershipQuery := `
+			INSERT INTO team_memberships (team_name, user_id)
+			VALUES ($1, $2)
+			ON CONFLICT (team_name, user_id) DO NOTHING
+		`
+		if _, err := s.conn(ctx).Exec(ctx, membershipQuery, t.TeamName, member.UserId); err != nil {
+			return fmt.Errorf("insert membership %s: %w", member.UserId, ErrQueryExecution)
+		}
 	}
 
 	return nil
@@ -71,20 +74,23 @@ func (s *postgresStorage) Create(ctx context.Context, t team.Team) error {
 func (s *postgresStorage) GetByTeamName(ctx context.Context, teamName string) (team.Team, error) {
 	var exists bool
 	checkQuery := "SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)"
-	err := s.db.QueryRow(ctx, checkQuery, teamName).Scan(&exists)
+	err := s.conn(ctx).QueryRow(ctx, checkQuery, teamName).Scan(&exists)
 	if err != nil {
 		return team.Team{}, fmt.Errorf("check team exists: %w", err)
 	}
 	if !exists {
-		return team.Team{}, ErrTeamNotFound
+		return team.Team{}, team.ErrTeamNotFound
 	}
 	query := `
-		SELECT user_id, username, team_name, is_active 
-		FROM users 
-		WHERE team_name = $1
-		ORDER BY user_id
+		SELECT u.user_id, u.username, u.team_name, u.is_active,
+			ARRAY(SELECT s.skill FROM user_skills s WHERE s.user_id = u.user_id ORDER BY s.skill),
+			u.time_zone, u.work_start_minute, u.work_end_minute, u.departed_at
+		FROM users u
+		JOIN team_memberships m ON m.user_id = u.user_id
+		WHERE m.team_name = $1
+		ORDER BY u.user_id
 	`
-	rows, err := s.db.Query(ctx, query, teamName)
+	rows, err := s.conn(ctx).Query(ctx, query, teamName)
 	if err != nil {
 		return team.Team{}, fmt.Errorf("query users: %w", err)
 	}
@@ -95,7 +101,8 @@ func (s *postgresStorage) GetByTeamName(ctx context.Context, teamName string) (t
 
 	for rows.Next() {
 		var u user.User
-		err := rows.Scan(&u.UserId, &u.UserName, &u.TeamName, &u.IsActive)
+		err := rows.Scan(&u.UserId, &u.UserName, &u.TeamName, &u.IsActive, &u.Skills,
+			&u.Schedule.TimeZone, &u.Schedule.WorkStart, &u.Schedule.WorkEnd, &u.DepartedAt)
 		if err != nil {
 			return team.Team{}, fmt.Errorf("scan user: %w", err)
 		}
@@ -113,11 +120,122 @@ func (s *postgresStorage) GetByTeamName(ctx context.Context, teamName string) (t
 	}, nil
 }
 
-func (s *postgresStorage) Close() error {
-	err := s.db.Close(context.Background())
+func (s *postgresStorage) AddMember(ctx context.Context, teamName, userID string) error {
+	if err := s.checkTeamAndUser(ctx, teamName, userID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO team_memberships (team_name, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (team_name, user_id) DO NOTHING
+	`
+	if _, err := s.conn(ctx).Exec(ctx, query, teamName, userID); err != nil {
+		return fmt.Errorf("insert membership: %w", err)
+	}
+
+	return nil
+}
+
+func (s *postgresStorage) RemoveMember(ctx context.Context, teamName, userID string) error {
+	if err := s.checkTeamAndUser(ctx, teamName, userID); err != nil {
+		return err
+	}
+
+	var primaryTeam string
+	if err := s.conn(ctx).QueryRow(ctx, "SELECT team_name FROM users WHERE user_id = $1", userID).Scan(&primaryTeam); err != nil {
+		return fmt.Errorf("select primary team: %w", err)
+	}
+	if primaryTeam == teamName {
+		return team.ErrPrimaryMembership
+	}
+
+	tag, err := s.conn(ctx).Exec(ctx, "DELETE FROM team_memberships WHERE team_name = $1 AND user_id = $2", teamName, userID)
+	if err != nil {
+		return fmt.Errorf("delete membership: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return team.ErrNotMember
+	}
+
+	return nil
+}
+
+func (s *postgresStorage) GetTeamNamesByUserID(ctx context.Context, userID string) ([]string, error) {
+	var exists bool
+	if err := s.conn(ctx).QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE user_id = $1)", userID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("check user exists: %w", err)
+	}
+	if !exists {
+		return nil, user.ErrUserNotFound
+	}
 
+	query := `
+		SELECT team_name
+		FROM team_memberships
+		WHERE user_id = $1
+		ORDER BY team_name
+	`
+	rows, err := s.conn(ctx).Query(ctx, query, userID)
 	if err != nil {
-		return fmt.Errorf("postgres close: %w", err)
+		return nil, fmt.Errorf("query memberships: %w", err)
+	}
+	defer rows.Close()
+
+	teams := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("scan membership: %w", err)
+		}
+		teams = append(teams, name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return teams, nil
+}
+
+func (s *postgresStorage) ListTeamNames(ctx context.Context) ([]string, error) {
+	rows, err := s.conn(ctx).Query(ctx, "SELECT team_name FROM teams ORDER BY team_name")
+	if err != nil {
+		return nil, fmt.Errorf("query teams: %w", err)
+	}
+	defer rows.Close()
+
+	names := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("scan team: %w", err)
+		}
+		names = append(names, name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return names, nil
+}
+
+func (s *postgresStorage) checkTeamAndUser(ctx context.Context, teamName, userID string) error {
+	var teamExists, userExists bool
+	query := `
+		SELECT
+			EXISTS(SELECT 1 FROM teams WHERE team_name = $1),
+			EXISTS(SELECT 1 FROM users WHERE user_id = $2)
+	`
+	if err := s.conn(ctx).QueryRow(ctx, query, teamName, userID).Scan(&teamExists, &userExists); err != nil {
+		return fmt.Errorf("check team and user exist: %w", err)
+	}
+	if !teamExists {
+		return team.ErrTeamNotFound
+	}
+	if !userExists {
+		return user.ErrUserNotFound
 	}
 
 	return nil
@@ -0,0 +1,123 @@
+package token
+
+import (
+	"InternshipTask/internal/domain/auth"
+	"InternshipTask/internal/infrastructure/postgres"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresStorage struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresStorage(pool *pgxpool.Pool) *postgresStorage {
+	return &postgresStorage{
+		db: pool,
+	}
+}
+
+func (s *postgresStorage) conn(ctx context.Context) postgres.DBTX {
+	return postgres.Conn(ctx, s.db)
+}
+
+var _ auth.Storager = (*postgresStorage)(nil)
+
+const columns = `token_id, user_id, role, description, token_hash, created_at, expires_at, revoked_at`
+
+func (s *postgresStorage) Create(ctx context.Context, t *auth.Token) error {
+	query := `
+		INSERT INTO api_tokens (` + columns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := s.conn(ctx).Exec(ctx, query,
+		t.ID,
+		t.UserID,
+		string(t.Role),
+		t.Description,
+		t.Hash,
+		t.CreatedAt,
+		t.ExpiresAt,
+		t.RevokedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert token: %w", err)
+	}
+
+	return nil
+}
+
+func (s *postgresStorage) GetByHash(ctx context.Context, hash string) (*auth.Token, error) {
+	query := `SELECT ` + columns + ` FROM api_tokens WHERE token_hash = $1`
+
+	t, err := scanToken(s.conn(ctx).QueryRow(ctx, query, hash))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, auth.ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("select token: %w", err)
+	}
+
+	return t, nil
+}
+
+func (s *postgresStorage) List(ctx context.Context) ([]auth.Token, error) {
+	query := `SELECT ` + columns + ` FROM api_tokens ORDER BY created_at, token_id`
+
+	rows, err := s.conn(ctx).Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("select tokens: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]auth.Token, 0)
+	for rows.Next() {
+		t, err := scanToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan token: %w", err)
+		}
+		result = append(result, *t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return result, nil
+}
+
+func (s *postgresStorage) Revoke(ctx context.Context, id string, at time.Time) (*auth.Token, error) {
+	query := `
+		UPDATE api_tokens
+		SET revoked_at = COALESCE(revoked_at, $2)
+		WHERE token_id = $1
+		RETURNING ` + columns
+
+	t, err := scanToken(s.conn(ctx).QueryRow(ctx, query, id, at))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, auth.ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("revoke token: %w", err)
+	}
+
+	return t, nil
+}
+
+func scanToken(row pgx.Row) (*auth.Token, error) {
+	var (
+		t    auth.Token
+		role string
+	)
+	if err := row.Scan(&t.ID, &t.UserID, &role, &t.Description, &t.Hash, &t.CreatedAt, &t.ExpiresAt, &t.RevokedAt); err != nil {
+		return nil, err
+	}
+	t.Role = auth.Role(role)
+	return &t, nil
+}
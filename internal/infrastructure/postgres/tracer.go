@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"InternshipTask/internal/domain/logging"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// queryTracer logs failed queries through the logger of the query context,
+// so that they carry the request id. A missing row is an ordinary outcome
+// for the storages and is not logged; constraint violations are logged at
+// warn level.
+type queryTracer struct{}
+
+type querySQLKey struct{}
+
+func (queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, querySQLKey{}, data.SQL)
+}
+
+func (queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	logQueryError(ctx, data.Err)
+}
+
+func logQueryError(ctx context.Context, err error) {
+	if err == nil || errors.Is(err, pgx.ErrNoRows) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+
+	level := slog.LevelError
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && strings.HasPrefix(pgErr.Code, "23") {
+		level = slog.LevelWarn
+	}
+	sql, _ := ctx.Value(querySQLKey{}).(string)
+	logging.FromContext(ctx).Log(ctx, level, "postgres query failed",
+		slog.String("sql", sql),
+		slog.Any("err", err),
+	)
+}
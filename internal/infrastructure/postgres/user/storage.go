@@ -2,62 +2,61 @@ package user
 
 import (
 	domain "InternshipTask/internal/domain/user"
+	"InternshipTask/internal/infrastructure/postgres"
 	"context"
 	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"time"
 )
 
-type Config struct {
-	DatabaseDSN    string
-	ConnectTimeout time.Duration
-}
-
 type postgresStorage struct {
-	db *pgx.Conn
+	db *pgxpool.Pool
 }
 
-var (
-	ErrUserNotFound   = errors.New("user not found")
-	ErrConnectTimeout = errors.New("connect timeout")
-)
-
-func NewPostgresStorage(cfg *Config) (*postgresStorage, error) {
-	connCtx, cancel := context.WithTimeoutCause(context.Background(), cfg.ConnectTimeout, ErrConnectTimeout)
-	defer cancel()
-	conn, err := pgx.Connect(connCtx, cfg.DatabaseDSN)
-	if err != nil {
-		return nil, fmt.Errorf("postgres connect: %w", err)
+func NewPostgresStorage(pool *pgxpool.Pool) *postgresStorage {
+	return &postgresStorage{
+		db: pool,
 	}
+}
 
-	return &postgresStorage{
-		db: conn,
-	}, nil
+func (s *postgresStorage) conn(ctx context.Context) postgres.DBTX {
+	return postgres.Conn(ctx, s.db)
 }
 
 var _ domain.Storager = (*postgresStorage)(nil)
 
-func (s *postgresStorage) GetByID(ctx context.Context, id string) (*domain.User, error) {
-	query := `
-		SELECT user_id, username, team_name, is_active
-		FROM users
-		WHERE user_id = $1
-	`
-
-	row := s.db.QueryRow(ctx, query, id)
+const userColumns = `
+	user_id, username, team_name, is_active,
+	ARRAY(SELECT skill FROM user_skills WHERE user_skills.user_id = users.user_id ORDER BY skill),
+	time_zone, work_start_minute, work_end_minute, departed_at
+`
 
+func scanUser(row pgx.Row) (*domain.User, error) {
 	var u domain.User
 
-	err := row.Scan(&u.UserId, &u.UserName, &u.TeamName, &u.IsActive)
+	err := row.Scan(&u.UserId, &u.UserName, &u.TeamName, &u.IsActive, &u.Skills,
+		&u.Schedule.TimeZone, &u.Schedule.WorkStart, &u.Schedule.WorkEnd, &u.DepartedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+func (s *postgresStorage) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE user_id = $1`
+
+	u, err := scanUser(s.conn(ctx).QueryRow(ctx, query, id))
 	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, ErrUserNotFound
+		return nil, domain.ErrUserNotFound
 	}
 	if err != nil {
 		return nil, fmt.Errorf("select user: %w", err)
 	}
 
-	return &u, nil
+	return u, nil
 }
 
 func (s *postgresStorage) SetIsActive(ctx context.Context, id string, isActive bool) (*domain.User, error) {
@@ -65,27 +64,86 @@ func (s *postgresStorage) SetIsActive(ctx context.Context, id string, isActive b
 		UPDATE users
 		SET is_active = $2
 		WHERE user_id = $1
-		RETURNING user_id, username, team_name, is_active
-	`
-
-	row := s.db.QueryRow(ctx, query, id, isActive)
+		RETURNING ` + userColumns
 
-	var u domain.User
-
-	err := row.Scan(&u.UserId, &u.UserName, &u.TeamName, &u.IsActive)
+	u, err := scanUser(s.conn(ctx).QueryRow(ctx, query, id, isActive))
 	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, ErrUserNotFound
+		return nil, domain.ErrUserNotFound
 	}
 	if err != nil {
 		return nil, fmt.Errorf("update user is_active: %w", err)
 	}
 
-	return &u, nil
+	return u, nil
 }
 
-func (s *postgresStorage) Close() error {
-	if err := s.db.Close(context.Background()); err != nil {
-		return fmt.Errorf("postgres close: %w", err)
+func (s *postgresStorage) SetSkills(ctx context.Context, id string, skills []string) (*domain.User, error) {
+	tx, err := s.conn(ctx).Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	if err := tx.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE user_id = $1)", id).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("check user exists: %w", err)
+	}
+	if !exists {
+		return nil, domain.ErrUserNotFound
 	}
-	return nil
+
+	if _, err := tx.Exec(ctx, "DELETE FROM user_skills WHERE user_id = $1", id); err != nil {
+		return nil, fmt.Errorf("delete user skills: %w", err)
+	}
+
+	insertQuery := `
+		INSERT INTO user_skills (user_id, skill)
+		SELECT $1, unnest($2::TEXT[])
+		ON CONFLICT (user_id, skill) DO NOTHING
+	`
+	if _, err := tx.Exec(ctx, insertQuery, id, skills); err != nil {
+		return nil, fmt.Errorf("insert user skills: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+func (s *postgresStorage) SetSchedule(ctx context.Context, id string, schedule domain.Schedule) (*domain.User, error) {
+	query := `
+		UPDATE users
+		SET time_zone = $2, work_start_minute = $3, work_end_minute = $4
+		WHERE user_id = $1
+	`
+
+	tag, err := s.conn(ctx).Exec(ctx, query, id, schedule.TimeZone, schedule.WorkStart, schedule.WorkEnd)
+	if err != nil {
+		return nil, fmt.Errorf("update user schedule: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, domain.ErrUserNotFound
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+func (s *postgresStorage) MarkDeparted(ctx context.Context, id string, at time.Time) (*domain.User, error) {
+	query := `
+		UPDATE users
+		SET is_active = false, departed_at = COALESCE(departed_at, $2)
+		WHERE user_id = $1
+		RETURNING ` + userColumns
+
+	u, err := scanUser(s.conn(ctx).QueryRow(ctx, query, id, at))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("update user departed_at: %w", err)
+	}
+
+	return u, nil
 }
@@ -0,0 +1,37 @@
+package sink
+
+import (
+	"InternshipTask/internal/domain/events"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Log writes every event as a line of JSON.
+type Log struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewLog(w io.Writer) *Log {
+	return &Log{w: w}
+}
+
+var _ events.Sink = (*Log)(nil)
+
+func (l *Log) Deliver(_ context.Context, e events.Event) error {
+	data, err := marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal event %d: %w", e.ID, err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write event %d: %w", e.ID, err)
+	}
+
+	return nil
+}
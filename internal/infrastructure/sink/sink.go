@@ -0,0 +1,30 @@
+// Package sink holds the destinations the outbox dispatcher delivers domain
+// events to.
+package sink
+
+import (
+	"InternshipTask/internal/domain/events"
+	"encoding/json"
+	"time"
+)
+
+// envelope is the wire format shared by all sinks.
+type envelope struct {
+	ID            int64           `json:"id"`
+	Type          events.Type     `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+func marshal(e events.Event) ([]byte, error) {
+	return json.Marshal(envelope{
+		ID:            e.ID,
+		Type:          e.Type,
+		AggregateType: e.AggregateType,
+		AggregateID:   e.AggregateID,
+		OccurredAt:    e.OccurredAt,
+		Payload:       e.Payload,
+	})
+}
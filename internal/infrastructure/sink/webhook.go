@@ -0,0 +1,59 @@
+package sink
+
+import (
+	"InternshipTask/internal/domain/events"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const defaultWebhookTimeout = 10 * time.Second
+
+// Webhook POSTs every event as JSON to a URL. Any non-2xx response fails the
+// delivery, so the event is retried. Receivers can deduplicate by the
+// X-Event-ID header.
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhook(url string, client *http.Client) *Webhook {
+	if client == nil {
+		client = &http.Client{Timeout: defaultWebhookTimeout}
+	}
+	return &Webhook{url: url, client: client}
+}
+
+var _ events.Sink = (*Webhook)(nil)
+
+func (w *Webhook) Deliver(ctx context.Context, e events.Event) error {
+	data, err := marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal event %d: %w", e.ID, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", strconv.FormatInt(e.ID, 10))
+	req.Header.Set("X-Event-Type", string(e.Type))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post event %d: %w", e.ID, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("post event %d: unexpected status %d", e.ID, resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package sink
+
+import (
+	"InternshipTask/internal/domain/events"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebhook_PostsEnvelopeAndFailsOnErrorStatus(t *testing.T) {
+	status := http.StatusOK
+	var (
+		got    envelope
+		header http.Header
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	e := events.Event{
+		ID:            7,
+		Type:          events.PullRequestMerged,
+		AggregateType: events.AggregatePullRequest,
+		AggregateID:   "pr-1",
+		Payload:       json.RawMessage(`{"pull_request_id":"pr-1"}`),
+		OccurredAt:    time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC),
+	}
+	sink := NewWebhook(srv.URL, srv.Client())
+
+	if err := sink.Deliver(context.Background(), e); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+	if header.Get("X-Event-ID") != "7" || header.Get("X-Event-Type") != string(events.PullRequestMerged) {
+		t.Fatalf("unexpected headers: %v", header)
+	}
+	if got.ID != 7 || got.AggregateID != "pr-1" || string(got.Payload) != `{"pull_request_id":"pr-1"}` {
+		t.Fatalf("unexpected envelope: %+v", got)
+	}
+
+	status = http.StatusServiceUnavailable
+	if err := sink.Deliver(context.Background(), e); err == nil {
+		t.Fatalf("expected an error for status %d", status)
+	}
+}
@@ -0,0 +1,129 @@
+package audit
+
+import (
+	domain "InternshipTask/internal/domain/audit"
+	"InternshipTask/internal/infrastructure/sqlite"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type sqliteStorage struct {
+	db *sql.DB
+}
+
+func NewSQLiteStorage(db *sql.DB) *sqliteStorage {
+	return &sqliteStorage{
+		db: db,
+	}
+}
+
+func (s *sqliteStorage) conn(ctx context.Context) sqlite.DBTX {
+	return sqlite.Conn(ctx, s.db)
+}
+
+var _ domain.Storager = (*sqliteStorage)(nil)
+
+func (s *sqliteStorage) Append(ctx context.Context, e *domain.Entry) error {
+	query := `
+		INSERT INTO audit_log (at, actor, action, entity_type, entity_id, before, after)
+		VALUES (?, NULLIF(?, ''), ?, ?, ?, ?, ?)
+		RETURNING id
+	`
+
+	err := s.conn(ctx).QueryRowContext(ctx, query,
+		sqlite.FormatTime(e.At),
+		e.Actor,
+		string(e.Action),
+		string(e.EntityType),
+		e.EntityID,
+		nullJSON(e.Before),
+		nullJSON(e.After),
+	).Scan(&e.ID)
+	if err != nil {
+		return fmt.Errorf("insert audit entry: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqliteStorage) List(ctx context.Context, f domain.Filter) ([]domain.Entry, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	if f.EntityType != "" {
+		conds, args = append(conds, "entity_type = ?"), append(args, string(f.EntityType))
+	}
+	if f.EntityID != "" {
+		conds, args = append(conds, "entity_id = ?"), append(args, f.EntityID)
+	}
+	if f.Actor != "" {
+		conds, args = append(conds, "actor = ?"), append(args, f.Actor)
+	}
+	if !f.From.IsZero() {
+		conds, args = append(conds, "at >= ?"), append(args, sqlite.FormatTime(f.From))
+	}
+	if !f.To.IsZero() {
+		conds, args = append(conds, "at < ?"), append(args, sqlite.FormatTime(f.To))
+	}
+
+	query := `
+		SELECT id, at, COALESCE(actor, ''), action, entity_type, entity_id, before, after
+		FROM audit_log
+	`
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	query += " ORDER BY at DESC, id DESC"
+	if f.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, f.Limit)
+	}
+
+	rows, err := s.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("select audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]domain.Entry, 0)
+	for rows.Next() {
+		var (
+			e             domain.Entry
+			at            *time.Time
+			action        string
+			entityType    string
+			before, after sql.NullString
+		)
+		if err := rows.Scan(&e.ID, sqlite.ScanTime(&at), &e.Actor, &action, &entityType, &e.EntityID, &before, &after); err != nil {
+			return nil, fmt.Errorf("scan audit entry: %w", err)
+		}
+		e.At = *at
+		e.Action = domain.Action(action)
+		e.EntityType = domain.EntityType(entityType)
+		if before.Valid {
+			e.Before = json.RawMessage(before.String)
+		}
+		if after.Valid {
+			e.After = json.RawMessage(after.String)
+		}
+		result = append(result, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return result, nil
+}
+
+func nullJSON(raw json.RawMessage) any {
+	if raw == nil {
+		return nil
+	}
+	return string(raw)
+}
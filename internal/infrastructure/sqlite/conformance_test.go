@@ -0,0 +1,48 @@
+package sqlite_test
+
+import (
+	"InternshipTask/db"
+	"InternshipTask/internal/infrastructure/sqlite"
+	auditsqlite "InternshipTask/internal/infrastructure/sqlite/audit"
+	idempotencysqlite "InternshipTask/internal/infrastructure/sqlite/idempotency"
+	"InternshipTask/internal/infrastructure/sqlite/migrate"
+	outboxsqlite "InternshipTask/internal/infrastructure/sqlite/outbox"
+	prsqlite "InternshipTask/internal/infrastructure/sqlite/pull_request"
+	teamsqlite "InternshipTask/internal/infrastructure/sqlite/team"
+	tokensqlite "InternshipTask/internal/infrastructure/sqlite/token"
+	usersqlite "InternshipTask/internal/infrastructure/sqlite/user"
+	"InternshipTask/internal/infrastructure/storagetest"
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Backend {
+		ctx := context.Background()
+		conn, err := sqlite.Open(ctx, "sqlite://"+filepath.Join(t.TempDir(), "test.db"))
+		if err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+		t.Cleanup(func() { conn.Close() })
+
+		migrator, err := migrate.New(conn, db.SQLiteMigrations())
+		if err != nil {
+			t.Fatalf("migrate.New() error = %v", err)
+		}
+		if _, err := migrator.Up(ctx); err != nil {
+			t.Fatalf("migrate up: %v", err)
+		}
+
+		return storagetest.Backend{
+			Teams:       teamsqlite.NewSQLiteStorage(conn),
+			Users:       usersqlite.NewSQLiteStorage(conn),
+			PRs:         prsqlite.NewSQLiteStorage(conn),
+			Audit:       auditsqlite.NewSQLiteStorage(conn),
+			Outbox:      outboxsqlite.NewSQLiteStorage(conn),
+			Idempotency: idempotencysqlite.NewSQLiteStorage(conn),
+			Tokens:      tokensqlite.NewSQLiteStorage(conn),
+			Tx:          sqlite.NewTxManager(conn),
+		}
+	})
+}
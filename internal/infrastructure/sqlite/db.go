@@ -0,0 +1,67 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+// Open opens the database file named by dsn, given as sqlite:///path/to.db,
+// sqlite://relative.db or file:path.db, and checks that it is reachable.
+//
+// SQLite allows a single writer at a time, so all storages share one
+// connection: queries are serialized by database/sql instead of failing with
+// SQLITE_BUSY, and a unit of work started by TxManager sees every write made
+// through it.
+func Open(ctx context.Context, dsn string) (*sql.DB, error) {
+	path, err := filePath(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Set("_txlock", "immediate")
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("sqlite ping: %w", err)
+	}
+
+	return db, nil
+}
+
+func filePath(dsn string) (string, error) {
+	var path string
+	switch {
+	case strings.HasPrefix(dsn, "sqlite://"):
+		path = strings.TrimPrefix(dsn, "sqlite://")
+	case strings.HasPrefix(dsn, "sqlite:"):
+		path = strings.TrimPrefix(dsn, "sqlite:")
+	case strings.HasPrefix(dsn, "file:"):
+		path = strings.TrimPrefix(dsn, "file:")
+	default:
+		return "", fmt.Errorf("parse sqlite DSN: unsupported scheme in %q", dsn)
+	}
+
+	if i := strings.IndexByte(path, '?'); i != -1 {
+		path = path[:i]
+	}
+	if path == "" {
+		return "", fmt.Errorf("parse sqlite DSN: empty path in %q", dsn)
+	}
+
+	return path, nil
+}
@@ -0,0 +1,111 @@
+package idempotency
+
+import (
+	"InternshipTask/internal/domain/idempotency"
+	"InternshipTask/internal/infrastructure/sqlite"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type sqliteStorage struct {
+	db *sql.DB
+}
+
+func NewSQLiteStorage(db *sql.DB) *sqliteStorage {
+	return &sqliteStorage{
+		db: db,
+	}
+}
+
+func (s *sqliteStorage) conn(ctx context.Context) sqlite.DBTX {
+	return sqlite.Conn(ctx, s.db)
+}
+
+var _ idempotency.Store = (*sqliteStorage)(nil)
+
+func (s *sqliteStorage) Reserve(ctx context.Context, rec idempotency.Record) (*idempotency.Record, error) {
+	// An expired record is taken over as if it did not exist.
+	query := `
+		INSERT INTO idempotency_keys (key, fingerprint, created_at, expires_at)
+		VALUES (?1, ?2, ?3, ?4)
+		ON CONFLICT (key) DO UPDATE
+		SET fingerprint = excluded.fingerprint, status = NULL, body = NULL,
+			created_at = excluded.created_at, expires_at = excluded.expires_at
+		WHERE idempotency_keys.expires_at <= excluded.created_at
+		RETURNING key
+	`
+
+	var key string
+	err := s.conn(ctx).QueryRowContext(ctx, query,
+		rec.Key,
+		rec.Fingerprint,
+		sqlite.FormatTime(rec.CreatedAt),
+		sqlite.FormatTime(rec.ExpiresAt),
+	).Scan(&key)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+
+	query = `
+		SELECT key, fingerprint, COALESCE(status, 0), body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE key = ?
+	`
+
+	var (
+		existing             idempotency.Record
+		createdAt, expiresAt *time.Time
+	)
+	err = s.conn(ctx).QueryRowContext(ctx, query, rec.Key).Scan(
+		&existing.Key,
+		&existing.Fingerprint,
+		&existing.Status,
+		&existing.Body,
+		sqlite.ScanTime(&createdAt),
+		sqlite.ScanTime(&expiresAt),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("select idempotency key: %w", err)
+	}
+	existing.CreatedAt, existing.ExpiresAt = *createdAt, *expiresAt
+
+	return &existing, nil
+}
+
+func (s *sqliteStorage) Complete(ctx context.Context, key string, status int, body []byte) error {
+	query := `UPDATE idempotency_keys SET status = ?, body = ? WHERE key = ?`
+
+	if _, err := s.conn(ctx).ExecContext(ctx, query, status, body, key); err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqliteStorage) Release(ctx context.Context, key string) error {
+	if _, err := s.conn(ctx).ExecContext(ctx, `DELETE FROM idempotency_keys WHERE key = ?`, key); err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqliteStorage) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	res, err := s.conn(ctx).ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= ?`, sqlite.FormatTime(now))
+	if err != nil {
+		return 0, fmt.Errorf("delete expired idempotency keys: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("rows affected: %w", err)
+	}
+
+	return int(n), nil
+}
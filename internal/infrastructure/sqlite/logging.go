@@ -0,0 +1,44 @@
+package sqlite
+
+import (
+	"InternshipTask/internal/domain/logging"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+)
+
+// loggingConn logs failed queries through the logger of the query context,
+// so that they carry the request id.
+type loggingConn struct {
+	conn DBTX
+}
+
+func (c loggingConn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	res, err := c.conn.ExecContext(ctx, query, args...)
+	logQueryError(ctx, query, err)
+	return res, err
+}
+
+func (c loggingConn) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	rows, err := c.conn.QueryContext(ctx, query, args...)
+	logQueryError(ctx, query, err)
+	return rows, err
+}
+
+func (c loggingConn) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	row := c.conn.QueryRowContext(ctx, query, args...)
+	logQueryError(ctx, query, row.Err())
+	return row
+}
+
+func logQueryError(ctx context.Context, query string, err error) {
+	if err == nil || errors.Is(err, sql.ErrNoRows) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+	logging.FromContext(ctx).ErrorContext(ctx, "sqlite query failed",
+		slog.String("sql", query),
+		slog.Any("err", err),
+	)
+}
@@ -0,0 +1,180 @@
+// Package migrate applies the SQLite migrations. Files are named and paired
+// the same way as for Postgres, so loading is shared with postgres/migrate.
+package migrate
+
+import (
+	pgmigrate "InternshipTask/internal/infrastructure/postgres/migrate"
+	"InternshipTask/internal/infrastructure/sqlite"
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"slices"
+	"time"
+)
+
+type (
+	Migration = pgmigrate.Migration
+	Status    = pgmigrate.Status
+)
+
+var (
+	ErrNoDownMigration = pgmigrate.ErrNoDownMigration
+	ErrUnknownVersion  = pgmigrate.ErrUnknownVersion
+)
+
+// Migrator needs no cross-process lock: every migration runs in an IMMEDIATE
+// transaction, which SQLite already makes exclusive to one writer.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func New(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := pgmigrate.Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+// Up applies all pending migrations in version order, each in its own
+// transaction, and returns the ones it applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	applied := make([]Migration, 0)
+
+	done, err := m.appliedVersions(ctx)
+	if err != nil {
+		return applied, err
+	}
+
+	for _, mig := range m.migrations {
+		if _, ok := done[mig.Version]; ok {
+			continue
+		}
+		err := sqlite.InTx(ctx, m.db, func(tx sqlite.DBTX) error {
+			if _, err := tx.ExecContext(ctx, mig.Up); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx,
+				"INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+				mig.Version, mig.Name, sqlite.FormatTime(time.Now()))
+			return err
+		})
+		if err != nil {
+			return applied, fmt.Errorf("apply %d_%s: %w", mig.Version, mig.Name, err)
+		}
+		applied = append(applied, mig)
+	}
+
+	return applied, nil
+}
+
+// Down rolls back the last steps applied migrations and returns them.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	rolledBack := make([]Migration, 0, steps)
+
+	done, err := m.appliedVersions(ctx)
+	if err != nil {
+		return rolledBack, err
+	}
+
+	versions := make([]int64, 0, len(done))
+	for v := range done {
+		versions = append(versions, v)
+	}
+	slices.Sort(versions)
+	slices.Reverse(versions)
+
+	for _, v := range versions[:min(steps, len(versions))] {
+		idx := slices.IndexFunc(m.migrations, func(mig Migration) bool { return mig.Version == v })
+		if idx == -1 {
+			return rolledBack, fmt.Errorf("roll back %d: %w", v, ErrUnknownVersion)
+		}
+		mig := m.migrations[idx]
+		if mig.Down == "" {
+			return rolledBack, fmt.Errorf("roll back %d_%s: %w", mig.Version, mig.Name, ErrNoDownMigration)
+		}
+
+		err := sqlite.InTx(ctx, m.db, func(tx sqlite.DBTX) error {
+			if _, err := tx.ExecContext(ctx, mig.Down); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = ?", mig.Version)
+			return err
+		})
+		if err != nil {
+			return rolledBack, fmt.Errorf("roll back %d_%s: %w", mig.Version, mig.Name, err)
+		}
+		rolledBack = append(rolledBack, mig)
+	}
+
+	return rolledBack, nil
+}
+
+// Status reports every known migration together with the time it was applied,
+// nil for pending ones.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	done, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		st := Status{Migration: mig}
+		if at, ok := done[mig.Version]; ok {
+			st.AppliedAt = at
+		}
+		result = append(result, st)
+	}
+
+	return result, nil
+}
+
+// Seed runs an idempotent data script, such as the demo data, in one transaction.
+func (m *Migrator) Seed(ctx context.Context, script string) error {
+	return sqlite.InTx(ctx, m.db, func(tx sqlite.DBTX) error {
+		if _, err := tx.ExecContext(ctx, script); err != nil {
+			return fmt.Errorf("apply seed: %w", err)
+		}
+		return nil
+	})
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context) (map[int64]*time.Time, error) {
+	createQuery := `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    INTEGER PRIMARY KEY,
+			name       TEXT NOT NULL,
+			applied_at TEXT NOT NULL
+		)
+	`
+	if _, err := m.db.ExecContext(ctx, createQuery); err != nil {
+		return nil, fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	rows, err := m.db.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("select schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	done := make(map[int64]*time.Time)
+	for rows.Next() {
+		var (
+			version int64
+			at      *time.Time
+		)
+		if err := rows.Scan(&version, sqlite.ScanTime(&at)); err != nil {
+			return nil, fmt.Errorf("scan schema_migrations: %w", err)
+		}
+		done[version] = at
+	}
+
+	return done, rows.Err()
+}
@@ -0,0 +1,66 @@
+package migrate
+
+import (
+	"InternshipTask/db"
+	"InternshipTask/internal/infrastructure/sqlite"
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrator_UpDownStatusSeed(t *testing.T) {
+	ctx := context.Background()
+	conn, err := sqlite.Open(ctx, "sqlite://"+filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	m, err := New(conn, db.SQLiteMigrations())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	applied, err := m.Up(ctx)
+	if err != nil || len(applied) == 0 {
+		t.Fatalf("Up() = %v, %v", applied, err)
+	}
+	if applied, err := m.Up(ctx); err != nil || len(applied) != 0 {
+		t.Fatalf("expected a second Up to be a no-op, got %v, %v", applied, err)
+	}
+
+	for range 2 {
+		if err := m.Seed(ctx, db.SQLiteSeed); err != nil {
+			t.Fatalf("Seed() error = %v", err)
+		}
+	}
+	var assignments int
+	if err := conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM review_assignments").Scan(&assignments); err != nil {
+		t.Fatalf("count assignments: %v", err)
+	}
+	if assignments != 4 {
+		t.Fatalf("expected the seed to be idempotent, got %d assignments", assignments)
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	for _, st := range statuses {
+		if st.AppliedAt == nil {
+			t.Fatalf("expected %d_%s to be applied", st.Version, st.Name)
+		}
+	}
+
+	rolledBack, err := m.Down(ctx, len(statuses))
+	if err != nil || len(rolledBack) != len(statuses) {
+		t.Fatalf("Down() = %v, %v", rolledBack, err)
+	}
+	var tables int
+	if err := conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'pull_requests'").Scan(&tables); err != nil {
+		t.Fatalf("check tables: %v", err)
+	}
+	if tables != 0 {
+		t.Fatalf("expected Down to drop the schema")
+	}
+}
@@ -0,0 +1,119 @@
+package outbox
+
+import (
+	"InternshipTask/internal/domain/events"
+	"InternshipTask/internal/infrastructure/sqlite"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type sqliteStorage struct {
+	db *sql.DB
+}
+
+func NewSQLiteStorage(db *sql.DB) *sqliteStorage {
+	return &sqliteStorage{
+		db: db,
+	}
+}
+
+func (s *sqliteStorage) conn(ctx context.Context) sqlite.DBTX {
+	return sqlite.Conn(ctx, s.db)
+}
+
+var _ events.Outbox = (*sqliteStorage)(nil)
+
+func (s *sqliteStorage) Append(ctx context.Context, e *events.Event) error {
+	query := `
+		INSERT INTO outbox (event_type, aggregate_type, aggregate_id, payload, occurred_at, attempts, next_attempt_at, last_error)
+		VALUES (?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''))
+		RETURNING id
+	`
+
+	err := s.conn(ctx).QueryRowContext(ctx, query,
+		string(e.Type),
+		e.AggregateType,
+		e.AggregateID,
+		string(e.Payload),
+		sqlite.FormatTime(e.OccurredAt),
+		e.Attempts,
+		sqlite.FormatTime(e.NextAttemptAt),
+		e.LastError,
+	).Scan(&e.ID)
+	if err != nil {
+		return fmt.Errorf("insert outbox event: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqliteStorage) Pending(ctx context.Context, now time.Time, limit int) ([]events.Event, error) {
+	query := `
+		SELECT id, event_type, aggregate_type, aggregate_id, payload, occurred_at, attempts, next_attempt_at, COALESCE(last_error, '')
+		FROM outbox
+		WHERE id IN (
+			SELECT MIN(id)
+			FROM outbox
+			WHERE parked_at IS NULL
+			GROUP BY aggregate_type, aggregate_id
+		)
+		AND next_attempt_at <= ?
+		ORDER BY id
+		LIMIT ?
+	`
+
+	rows, err := s.conn(ctx).QueryContext(ctx, query, sqlite.FormatTime(now), limit)
+	if err != nil {
+		return nil, fmt.Errorf("select pending events: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]events.Event, 0)
+	for rows.Next() {
+		var (
+			e                       events.Event
+			eventType, payload      string
+			occurredAt, nextAttempt *time.Time
+		)
+		if err := rows.Scan(&e.ID, &eventType, &e.AggregateType, &e.AggregateID, &payload,
+			sqlite.ScanTime(&occurredAt), &e.Attempts, sqlite.ScanTime(&nextAttempt), &e.LastError); err != nil {
+			return nil, fmt.Errorf("scan outbox event: %w", err)
+		}
+		e.Type = events.Type(eventType)
+		e.Payload = json.RawMessage(payload)
+		e.OccurredAt = *occurredAt
+		e.NextAttemptAt = *nextAttempt
+		result = append(result, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return result, nil
+}
+
+func (s *sqliteStorage) MarkDelivered(ctx context.Context, id int64) error {
+	if _, err := s.conn(ctx).ExecContext(ctx, `DELETE FROM outbox WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("delete delivered event: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqliteStorage) MarkFailed(ctx context.Context, e events.Event) error {
+	query := `
+		UPDATE outbox
+		SET attempts = ?, next_attempt_at = ?, last_error = NULLIF(?, ''), parked_at = ?
+		WHERE id = ?
+	`
+
+	if _, err := s.conn(ctx).ExecContext(ctx, query, e.Attempts, sqlite.FormatTime(e.NextAttemptAt), e.LastError, sqlite.NullTime(e.ParkedAt), e.ID); err != nil {
+		return fmt.Errorf("update failed event: %w", err)
+	}
+
+	return nil
+}
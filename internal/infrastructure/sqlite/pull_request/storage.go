@@ -0,0 +1,750 @@
+package pull_request
+
+import (
+	domain "InternshipTask/internal/domain/pull_request"
+	"InternshipTask/internal/infrastructure/sqlite"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+type sqliteStorage struct {
+	db *sql.DB
+}
+
+func NewSQLiteStorage(db *sql.DB) *sqliteStorage {
+	return &sqliteStorage{
+		db: db,
+	}
+}
+
+func (s *sqliteStorage) conn(ctx context.Context) sqlite.DBTX {
+	return sqlite.Conn(ctx, s.db)
+}
+
+var _ domain.Repository = (*sqliteStorage)(nil)
+
+// Create inserts the PR together with the assignments of its initial
+// reviewers.
+func (s *sqliteStorage) Create(ctx context.Context, pr *domain.PR) error {
+	err := sqlite.InTx(ctx, s.db, func(tx sqlite.DBTX) error {
+		var archived bool
+		err := tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM pull_requests_archive WHERE pull_request_id = ?)", pr.PullRequestId).Scan(&archived)
+		if err != nil {
+			return fmt.Errorf("check archived pull_request: %w", err)
+		}
+		if archived {
+			return fmt.Errorf("insert pull_request: archived: %w", domain.ErrPRExists)
+		}
+
+		query := `
+			INSERT INTO pull_requests (
+				pull_request_id,
+				pull_request_name,
+				author_id,
+				team_name,
+				status,
+				required_skills,
+				created_at,
+				merged_at,
+				version
+			) VALUES (?, ?, ?, NULLIF(?, ''), ?, ?, ?, ?, 1)
+		`
+
+		_, err = tx.ExecContext(ctx, query,
+			pr.PullRequestId,
+			pr.PullRequestName,
+			pr.AuthorId,
+			pr.TeamName,
+			pr.Status.String(),
+			sqlite.Strings(pr.RequiredSkills),
+			sqlite.NullTime(pr.CreatedAt),
+			sqlite.NullTime(pr.MergedAt),
+		)
+		if err != nil {
+			return fmt.Errorf("insert pull_request: %w", err)
+		}
+
+		for slot, reviewerID := range pr.AssignedReviewers {
+			if err := insertAssignment(ctx, tx, pr, reviewerID, slot); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	pr.Version = 1
+
+	return nil
+}
+
+func (s *sqliteStorage) GetByID(ctx context.Context, id string) (*domain.PR, error) {
+	query := `
+		SELECT
+			pull_request_id,
+			pull_request_name,
+			author_id,
+			COALESCE(team_name, ''),
+			status,
+			(SELECT json_group_array(reviewer_id) FROM (
+				SELECT reviewer_id
+				FROM review_assignments
+				WHERE pull_request_id = ?1 AND unassigned_at IS NULL
+				ORDER BY slot, assigned_at
+			)),
+			required_skills,
+			created_at,
+			merged_at,
+			version
+		FROM pull_requests
+		WHERE pull_request_id = ?1
+	`
+
+	row := s.conn(ctx).QueryRowContext(ctx, query, id)
+
+	var pr domain.PR
+	var status string
+
+	err := row.Scan(
+		&pr.PullRequestId,
+		&pr.PullRequestName,
+		&pr.AuthorId,
+		&pr.TeamName,
+		&status,
+		sqlite.ScanStrings(&pr.AssignedReviewers),
+		sqlite.ScanStrings(&pr.RequiredSkills),
+		sqlite.ScanTime(&pr.CreatedAt),
+		sqlite.ScanTime(&pr.MergedAt),
+		&pr.Version,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("select pull_request: %w", err)
+	}
+
+	pr.Status = domain.PullRequestStatus(status)
+
+	return &pr, nil
+}
+
+// Update writes pr only if nobody has updated it since pr.Version was read,
+// and bumps pr.Version on success. Reviewers that left AssignedReviewers get
+// their assignment closed and new ones get an assignment opened, both
+// attributed to pr.ReviewerChange.
+func (s *sqliteStorage) Update(ctx context.Context, pr *domain.PR) error {
+	var version int64
+
+	err := sqlite.InTx(ctx, s.db, func(tx sqlite.DBTX) error {
+		query := `
+			UPDATE pull_requests
+			SET
+				status = ?,
+				merged_at = ?,
+				author_id = ?,
+				version = version + 1
+			WHERE pull_request_id = ? AND version = ?
+			RETURNING version
+		`
+
+		err := tx.QueryRowContext(ctx, query,
+			pr.Status.String(),
+			sqlite.NullTime(pr.MergedAt),
+			pr.AuthorId,
+			pr.PullRequestId,
+			pr.Version,
+		).Scan(&version)
+		if errors.Is(err, sql.ErrNoRows) {
+			var exists bool
+			if err := tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM pull_requests WHERE pull_request_id = ?)", pr.PullRequestId).Scan(&exists); err != nil {
+				return fmt.Errorf("check pull_request exists: %w", err)
+			}
+			if !exists {
+				return domain.ErrNotFound
+			}
+			return domain.ErrConflict
+		}
+		if err != nil {
+			return fmt.Errorf("update pull_request: %w", err)
+		}
+
+		return syncAssignments(ctx, tx, pr)
+	})
+	if err != nil {
+		return err
+	}
+
+	pr.Version = version
+
+	return nil
+}
+
+func syncAssignments(ctx context.Context, tx sqlite.DBTX, pr *domain.PR) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT reviewer_id
+		FROM review_assignments
+		WHERE pull_request_id = ? AND unassigned_at IS NULL
+	`, pr.PullRequestId)
+	if err != nil {
+		return fmt.Errorf("select active assignments: %w", err)
+	}
+	active, err := collectStrings(rows)
+	if err != nil {
+		return fmt.Errorf("scan active assignments: %w", err)
+	}
+
+	change := pr.ReviewerChange
+	for _, reviewerID := range active {
+		if slices.Contains(pr.AssignedReviewers, reviewerID) {
+			continue
+		}
+		query := `
+			UPDATE review_assignments
+			SET unassigned_at = ?, unassign_reason = ?, unassigned_by = NULLIF(?, '')
+			WHERE pull_request_id = ? AND reviewer_id = ? AND unassigned_at IS NULL
+		`
+		if _, err := tx.ExecContext(ctx, query, changeTime(change), string(change.Reason), change.Actor, pr.PullRequestId, reviewerID); err != nil {
+			return fmt.Errorf("close assignment of %s: %w", reviewerID, err)
+		}
+	}
+
+	for slot, reviewerID := range pr.AssignedReviewers {
+		if slices.Contains(active, reviewerID) {
+			continue
+		}
+		if err := insertAssignment(ctx, tx, pr, reviewerID, slot); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func insertAssignment(ctx context.Context, tx sqlite.DBTX, pr *domain.PR, reviewerID string, slot int) error {
+	change := pr.ReviewerChange
+	query := `
+		INSERT INTO review_assignments (pull_request_id, reviewer_id, slot, assigned_at, assigned_reason, assigned_by)
+		VALUES (?, ?, ?, ?, ?, NULLIF(?, ''))
+	`
+	if _, err := tx.ExecContext(ctx, query, pr.PullRequestId, reviewerID, slot, changeTime(change), string(change.Reason), change.Actor); err != nil {
+		return fmt.Errorf("insert assignment of %s: %w", reviewerID, err)
+	}
+	return nil
+}
+
+func changeTime(change domain.ReviewerChange) string {
+	if change.At.IsZero() {
+		return sqlite.FormatTime(time.Now())
+	}
+	return sqlite.FormatTime(change.At)
+}
+
+func collectStrings(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+
+	result := make([]string, 0)
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		result = append(result, v)
+	}
+
+	return result, rows.Err()
+}
+
+func (s *sqliteStorage) GetByReviewerID(ctx context.Context, reviewerID string, includeArchived bool) ([]domain.PullRequestShort, error) {
+	query := `
+		SELECT pull_request_id, pull_request_name, author_id, status
+		FROM (
+			SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at
+			FROM pull_requests AS pr
+			JOIN review_assignments AS ra ON ra.pull_request_id = pr.pull_request_id
+			WHERE ra.reviewer_id = ?1 AND ra.unassigned_at IS NULL
+			UNION ALL
+			SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at
+			FROM pull_requests_archive AS pr
+			JOIN review_assignments_archive AS ra ON ra.pull_request_id = pr.pull_request_id
+			WHERE ?2 AND ra.reviewer_id = ?1 AND ra.unassigned_at IS NULL
+		)
+		ORDER BY created_at IS NULL, created_at, pull_request_id
+	`
+
+	result, err := s.queryShorts(ctx, query, reviewerID, includeArchived)
+	if err != nil {
+		return nil, fmt.Errorf("select pull_requests by reviewer: %w", err)
+	}
+
+	return result, nil
+}
+
+func (s *sqliteStorage) GetOpenByAuthorID(ctx context.Context, authorID string) ([]domain.PullRequestShort, error) {
+	query := `
+		SELECT
+			pull_request_id,
+			pull_request_name,
+			author_id,
+			status
+		FROM pull_requests
+		WHERE author_id = ? AND status = 'OPEN'
+		ORDER BY created_at IS NULL, created_at, pull_request_id
+	`
+
+	result, err := s.queryShorts(ctx, query, authorID)
+	if err != nil {
+		return nil, fmt.Errorf("select open pull_requests by author: %w", err)
+	}
+
+	return result, nil
+}
+
+func (s *sqliteStorage) queryShorts(ctx context.Context, query string, args ...any) ([]domain.PullRequestShort, error) {
+	rows, err := s.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []domain.PullRequestShort
+
+	for rows.Next() {
+		var (
+			id        string
+			name      string
+			authorID  string
+			statusStr string
+		)
+
+		if err := rows.Scan(&id, &name, &authorID, &statusStr); err != nil {
+			return nil, fmt.Errorf("scan pull_request_short: %w", err)
+		}
+
+		result = append(result, *domain.NewPullRequestShort(
+			id,
+			name,
+			authorID,
+			domain.PullRequestStatus(statusStr),
+		))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetReviewerStats counts every assignment a reviewer has ever had, including
+// the ones that were later reassigned.
+func (s *sqliteStorage) GetReviewerStats(ctx context.Context, includeArchived bool) (map[string]int64, error) {
+	query := `
+		SELECT reviewer_id, COUNT(*) AS assign_count
+		FROM (
+			SELECT reviewer_id FROM review_assignments
+			UNION ALL
+			SELECT reviewer_id FROM review_assignments_archive WHERE ?
+		)
+		GROUP BY reviewer_id
+	`
+
+	rows, err := s.conn(ctx).QueryContext(ctx, query, includeArchived)
+	if err != nil {
+		return nil, fmt.Errorf("select reviewer stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make(map[string]int64)
+
+	for rows.Next() {
+		var reviewerID string
+		var count int64
+		if err := rows.Scan(&reviewerID, &count); err != nil {
+			return nil, fmt.Errorf("scan reviewer stats: %w", err)
+		}
+		stats[reviewerID] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return stats, nil
+}
+
+func (s *sqliteStorage) ListReviews(ctx context.Context, f domain.ReviewFilter) ([]domain.Review, error) {
+	args := []any{f.ReviewerID}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("?%d", len(args))
+	}
+
+	conds := []string{"ra.reviewer_id = ?1", "ra.unassigned_at IS NULL"}
+	if f.Status != "" {
+		conds = append(conds, "pr.status = "+arg(f.Status.String()))
+	}
+	if f.TeamName != "" {
+		conds = append(conds, "pr.team_name = "+arg(f.TeamName))
+	}
+	if !f.CreatedFrom.IsZero() {
+		conds = append(conds, "pr.created_at >= "+arg(sqlite.FormatTime(f.CreatedFrom)))
+	}
+	if !f.CreatedTo.IsZero() {
+		conds = append(conds, "pr.created_at < "+arg(sqlite.FormatTime(f.CreatedTo)))
+	}
+	if f.After != nil {
+		conds = append(conds, fmt.Sprintf("(pr.created_at, pr.pull_request_id) > (%s, %s)", arg(sqlite.FormatTime(f.After.CreatedAt)), arg(f.After.ID)))
+	}
+	var limit string
+	if f.Limit > 0 {
+		limit = " LIMIT " + arg(f.Limit)
+	}
+
+	branch := func(prs, assignments string) string {
+		return fmt.Sprintf(`
+			SELECT
+				pr.pull_request_id,
+				pr.pull_request_name,
+				pr.author_id,
+				pr.status,
+				COALESCE(pr.team_name, '') AS team_name,
+				pr.created_at,
+				pr.merged_at,
+				ra.assigned_at,
+				ra.verdict,
+				ra.verdict_at
+			FROM %s AS pr
+			JOIN %s AS ra ON ra.pull_request_id = pr.pull_request_id
+			WHERE %s
+			ORDER BY pr.created_at, pr.pull_request_id
+		`, prs, assignments, strings.Join(conds, " AND ")) + limit
+	}
+
+	query := branch("pull_requests", "review_assignments")
+	if f.IncludeArchived {
+		query = "SELECT * FROM (" + query + ") AS live UNION ALL SELECT * FROM (" +
+			branch("pull_requests_archive", "review_assignments_archive") + ") AS archived" +
+			" ORDER BY created_at, pull_request_id" + limit
+	}
+
+	rows, err := s.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("select reviews: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]domain.Review, 0)
+	for rows.Next() {
+		var (
+			r          domain.Review
+			status     string
+			verdict    string
+			assignedAt *time.Time
+		)
+		err := rows.Scan(&r.PullRequestId, &r.PullRequestName, &r.AuthorId, &status, &r.TeamName,
+			sqlite.ScanTime(&r.CreatedAt), sqlite.ScanTime(&r.MergedAt), sqlite.ScanTime(&assignedAt),
+			&verdict, sqlite.ScanTime(&r.VerdictAt))
+		if err != nil {
+			return nil, fmt.Errorf("scan review: %w", err)
+		}
+		r.Status = domain.PullRequestStatus(status)
+		r.AssignedAt = *assignedAt
+		r.Verdict = domain.Verdict(verdict)
+		result = append(result, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return result, nil
+}
+
+func (s *sqliteStorage) SetVerdict(ctx context.Context, prID, reviewerID string, verdict domain.Verdict, at time.Time) error {
+	query := `
+		UPDATE review_assignments
+		SET verdict = ?, verdict_at = ?
+		WHERE pull_request_id = ? AND reviewer_id = ? AND unassigned_at IS NULL
+	`
+
+	res, err := s.conn(ctx).ExecContext(ctx, query, string(verdict), sqlite.FormatTime(at), prID, reviewerID)
+	if err != nil {
+		return fmt.Errorf("update verdict: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update verdict: %w", err)
+	}
+	if n == 0 {
+		return domain.ErrNotAssigned
+	}
+
+	return nil
+}
+
+func (s *sqliteStorage) GetOpenReviewCounts(ctx context.Context, reviewerIDs []string) (map[string]int64, error) {
+	query := `
+		SELECT ra.reviewer_id, COUNT(*)
+		FROM review_assignments AS ra
+		JOIN pull_requests AS pr ON pr.pull_request_id = ra.pull_request_id
+		WHERE ra.reviewer_id IN (SELECT value FROM json_each(?))
+			AND ra.unassigned_at IS NULL AND pr.status = 'OPEN'
+		GROUP BY ra.reviewer_id
+	`
+
+	rows, err := s.conn(ctx).QueryContext(ctx, query, sqlite.Strings(reviewerIDs))
+	if err != nil {
+		return nil, fmt.Errorf("select open review counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+
+	for rows.Next() {
+		var reviewerID string
+		var count int64
+		if err := rows.Scan(&reviewerID, &count); err != nil {
+			return nil, fmt.Errorf("scan open review counts: %w", err)
+		}
+		counts[reviewerID] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (s *sqliteStorage) ArchiveMerged(ctx context.Context, mergedBefore time.Time, limit int) (int, error) {
+	var archived int
+	err := sqlite.InTx(ctx, s.db, func(tx sqlite.DBTX) error {
+		rows, err := tx.QueryContext(ctx, `
+			SELECT pull_request_id
+			FROM pull_requests
+			WHERE status = 'MERGED' AND merged_at < ?
+			ORDER BY merged_at, pull_request_id
+			LIMIT ?
+		`, sqlite.FormatTime(mergedBefore), limit)
+		if err != nil {
+			return fmt.Errorf("select merged pull_requests: %w", err)
+		}
+		ids, err := collectStrings(rows)
+		if err != nil {
+			return fmt.Errorf("scan merged pull_requests: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		statements := []string{
+			`INSERT INTO pull_requests_archive (pull_request_id, pull_request_name, author_id, team_name, status, required_skills, created_at, merged_at, version, archived_at)
+			 SELECT pull_request_id, pull_request_name, author_id, team_name, status, required_skills, created_at, merged_at, version, ?2
+			 FROM pull_requests WHERE pull_request_id IN (SELECT value FROM json_each(?1))`,
+			`INSERT INTO review_assignments_archive
+			 SELECT id, pull_request_id, reviewer_id, slot, assigned_at, assigned_reason, assigned_by, unassigned_at, unassign_reason, unassigned_by, verdict, verdict_at
+			 FROM review_assignments WHERE pull_request_id IN (SELECT value FROM json_each(?1))`,
+			// review_assignments go with the PRs through ON DELETE CASCADE.
+			`DELETE FROM pull_requests WHERE pull_request_id IN (SELECT value FROM json_each(?1))`,
+		}
+		now := sqlite.FormatTime(time.Now())
+		for _, stmt := range statements {
+			if _, err := tx.ExecContext(ctx, stmt, sqlite.Strings(ids), now); err != nil {
+				return fmt.Errorf("archive pull_requests: %w", err)
+			}
+		}
+
+		archived = len(ids)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return archived, nil
+}
+
+func (s *sqliteStorage) List(ctx context.Context, f domain.ListFilter) ([]domain.PR, error) {
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("?%d", len(args))
+	}
+
+	var conds []string
+	if f.Status != "" {
+		conds = append(conds, "pr.status = "+arg(f.Status.String()))
+	}
+	if f.AuthorID != "" {
+		conds = append(conds, "pr.author_id = "+arg(f.AuthorID))
+	}
+	if f.TeamName != "" {
+		conds = append(conds, "pr.team_name = "+arg(f.TeamName))
+	}
+	if f.Name != "" {
+		// LIKE is case-insensitive for ASCII in SQLite.
+		conds = append(conds, "pr.pull_request_name LIKE "+arg(containsPattern(f.Name))+` ESCAPE '\'`)
+	}
+	if !f.CreatedFrom.IsZero() {
+		conds = append(conds, "pr.created_at >= "+arg(sqlite.FormatTime(f.CreatedFrom)))
+	}
+	if !f.CreatedTo.IsZero() {
+		conds = append(conds, "pr.created_at < "+arg(sqlite.FormatTime(f.CreatedTo)))
+	}
+	if !f.MergedFrom.IsZero() {
+		conds = append(conds, "pr.merged_at >= "+arg(sqlite.FormatTime(f.MergedFrom)))
+	}
+	if !f.MergedTo.IsZero() {
+		conds = append(conds, "pr.merged_at < "+arg(sqlite.FormatTime(f.MergedTo)))
+	}
+	var reviewer string
+	if f.ReviewerID != "" {
+		reviewer = arg(f.ReviewerID)
+	}
+
+	after, order := ">", "ASC"
+	if f.Sort == domain.SortCreatedDesc {
+		after, order = "<", "DESC"
+	}
+	if f.After != nil {
+		conds = append(conds, fmt.Sprintf("(pr.created_at, pr.pull_request_id) %s (%s, %s)", after, arg(sqlite.FormatTime(f.After.CreatedAt)), arg(f.After.ID)))
+	}
+	orderBy := fmt.Sprintf(" ORDER BY created_at %[1]s, pull_request_id %[1]s", order)
+	if f.Limit > 0 {
+		orderBy += " LIMIT " + arg(f.Limit)
+	}
+
+	// Each table is ordered and limited on its own, so that both can walk
+	// their (created_at, pull_request_id) index, and the results are merged.
+	branch := func(prs, assignments string) string {
+		where := slices.Clone(conds)
+		if reviewer != "" {
+			where = append(where, fmt.Sprintf(`EXISTS (
+				SELECT 1 FROM %s AS ra
+				WHERE ra.pull_request_id = pr.pull_request_id AND ra.reviewer_id = %s AND ra.unassigned_at IS NULL
+			)`, assignments, reviewer))
+		}
+		query := fmt.Sprintf(`
+			SELECT
+				pr.pull_request_id,
+				pr.pull_request_name,
+				pr.author_id,
+				COALESCE(pr.team_name, '') AS team_name,
+				pr.status,
+				(SELECT json_group_array(reviewer_id) FROM (
+					SELECT ra.reviewer_id
+					FROM %[2]s AS ra
+					WHERE ra.pull_request_id = pr.pull_request_id AND ra.unassigned_at IS NULL
+					ORDER BY ra.slot, ra.assigned_at
+				)) AS assigned_reviewers,
+				pr.required_skills,
+				pr.created_at,
+				pr.merged_at,
+				pr.version
+			FROM %[1]s AS pr
+		`, prs, assignments)
+		if len(where) > 0 {
+			query += " WHERE " + strings.Join(where, " AND ")
+		}
+		return query + orderBy
+	}
+
+	query := branch("pull_requests", "review_assignments")
+	if f.IncludeArchived {
+		query = "SELECT * FROM (" + query + ") AS live UNION ALL SELECT * FROM (" +
+			branch("pull_requests_archive", "review_assignments_archive") + ") AS archived" + orderBy
+	}
+
+	rows, err := s.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("select pull_requests: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]domain.PR, 0)
+	for rows.Next() {
+		var pr domain.PR
+		var status string
+		err := rows.Scan(&pr.PullRequestId, &pr.PullRequestName, &pr.AuthorId, &pr.TeamName, &status,
+			sqlite.ScanStrings(&pr.AssignedReviewers), sqlite.ScanStrings(&pr.RequiredSkills),
+			sqlite.ScanTime(&pr.CreatedAt), sqlite.ScanTime(&pr.MergedAt), &pr.Version)
+		if err != nil {
+			return nil, fmt.Errorf("scan pull_request: %w", err)
+		}
+		pr.Status = domain.PullRequestStatus(status)
+		result = append(result, pr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return result, nil
+}
+
+// containsPattern is a LIKE pattern matching s anywhere, with the wildcards
+// in s escaped.
+func containsPattern(s string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s) + "%"
+}
+
+func (s *sqliteStorage) GetAssignments(ctx context.Context, prID string) ([]domain.Assignment, error) {
+	query := `
+		SELECT
+			reviewer_id,
+			assigned_at,
+			assigned_reason,
+			COALESCE(assigned_by, ''),
+			unassigned_at,
+			COALESCE(unassign_reason, ''),
+			COALESCE(unassigned_by, ''),
+			verdict,
+			verdict_at
+		FROM review_assignments
+		WHERE pull_request_id = ?
+		ORDER BY assigned_at, id
+	`
+
+	rows, err := s.conn(ctx).QueryContext(ctx, query, prID)
+	if err != nil {
+		return nil, fmt.Errorf("select assignments: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]domain.Assignment, 0)
+
+	for rows.Next() {
+		var (
+			a              domain.Assignment
+			assignedAt     *time.Time
+			assignedReason string
+			unassignReason string
+			verdict        string
+		)
+		err := rows.Scan(&a.ReviewerID, sqlite.ScanTime(&assignedAt), &assignedReason, &a.AssignedBy,
+			sqlite.ScanTime(&a.UnassignedAt), &unassignReason, &a.UnassignedBy, &verdict, sqlite.ScanTime(&a.VerdictAt))
+		if err != nil {
+			return nil, fmt.Errorf("scan assignment: %w", err)
+		}
+		a.AssignedAt = *assignedAt
+		a.AssignedReason = domain.AssignmentReason(assignedReason)
+		a.UnassignReason = domain.AssignmentReason(unassignReason)
+		a.Verdict = domain.Verdict(verdict)
+		result = append(result, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,249 @@
+package team
+
+import (
+	"InternshipTask/internal/domain/team"
+	"InternshipTask/internal/domain/user"
+	"InternshipTask/internal/infrastructure/sqlite"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type sqliteStorage struct {
+	db *sql.DB
+}
+
+func NewSQLiteStorage(db *sql.DB) *sqliteStorage {
+	return &sqliteStorage{
+		db: db,
+	}
+}
+
+func (s *sqliteStorage) conn(ctx context.Context) sqlite.DBTX {
+	return sqlite.Conn(ctx, s.db)
+}
+
+var _ team.Storager = (*sqliteStorage)(nil)
+
+func (s *sqliteStorage) Create(ctx context.Context, t team.Team) error {
+	return sqlite.InTx(ctx, s.db, func(tx sqlite.DBTX) error {
+		query := `INSERT INTO teams (team_name) VALUES (?)
+		          ON CONFLICT (team_name) DO NOTHING`
+		res, err := tx.ExecContext(ctx, query, t.TeamName)
+		if err != nil {
+			return fmt.Errorf("insert team: %w", err)
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return fmt.Errorf("insert team: %w", err)
+		} else if n == 0 {
+			return team.ErrTeamExists
+		}
+
+		for _, member := range t.Members {
+			upsertUserQuery := `
+				INSERT INTO users (user_id, username, team_name, is_active)
+				VALUES (?, ?, ?, ?)
+				ON CONFLICT (user_id)
+				DO UPDATE SET
+					username  = excluded.username,
+					team_name = excluded.team_name,
+					is_active = excluded.is_active AND users.departed_at IS NULL
+			`
+			if _, err := tx.ExecContext(ctx, upsertUserQuery,
+				member.UserId,
+				member.UserName,
+				t.TeamName,
+				member.IsActive,
+			); err != nil {
+				return fmt.Errorf("upsert user %s: %w", member.UserId, err)
+			}
+
+			membershipQuery := `
+				INSERT INTO team_memberships (team_name, user_id)
+				VALUES (?, ?)
+				ON CONFLICT (team_name, user_id) DO NOTHING
+			`
+			if _, err := tx.ExecContext(ctx, membershipQuery, t.TeamName, member.UserId); err != nil {
+				return fmt.Errorf("insert membership %s: %w", member.UserId, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *sqliteStorage) GetByTeamName(ctx context.Context, teamName string) (team.Team, error) {
+	var exists bool
+	checkQuery := "SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = ?)"
+	if err := s.conn(ctx).QueryRowContext(ctx, checkQuery, teamName).Scan(&exists); err != nil {
+		return team.Team{}, fmt.Errorf("check team exists: %w", err)
+	}
+	if !exists {
+		return team.Team{}, team.ErrTeamNotFound
+	}
+
+	query := `
+		SELECT u.user_id, u.username, u.team_name, u.is_active,
+			(SELECT json_group_array(skill) FROM (
+				SELECT s.skill FROM user_skills s WHERE s.user_id = u.user_id ORDER BY s.skill
+			)),
+			u.time_zone, u.work_start_minute, u.work_end_minute, u.departed_at
+		FROM users u
+		JOIN team_memberships m ON m.user_id = u.user_id
+		WHERE m.team_name = ?
+		ORDER BY u.user_id
+	`
+	rows, err := s.conn(ctx).QueryContext(ctx, query, teamName)
+	if err != nil {
+		return team.Team{}, fmt.Errorf("query users: %w", err)
+	}
+	defer rows.Close()
+
+	members := make(map[uint]*user.User)
+	index := uint(0)
+
+	for rows.Next() {
+		var u user.User
+		err := rows.Scan(&u.UserId, &u.UserName, &u.TeamName, &u.IsActive, sqlite.ScanStrings(&u.Skills),
+			&u.Schedule.TimeZone, &u.Schedule.WorkStart, &u.Schedule.WorkEnd, sqlite.ScanTime(&u.DepartedAt))
+		if err != nil {
+			return team.Team{}, fmt.Errorf("scan user: %w", err)
+		}
+		members[index] = &u
+		index++
+	}
+
+	if err := rows.Err(); err != nil {
+		return team.Team{}, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return team.Team{
+		TeamName: teamName,
+		Members:  members,
+	}, nil
+}
+
+func (s *sqliteStorage) AddMember(ctx context.Context, teamName, userID string) error {
+	if err := s.checkTeamAndUser(ctx, teamName, userID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO team_memberships (team_name, user_id)
+		VALUES (?, ?)
+		ON CONFLICT (team_name, user_id) DO NOTHING
+	`
+	if _, err := s.conn(ctx).ExecContext(ctx, query, teamName, userID); err != nil {
+		return fmt.Errorf("insert membership: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqliteStorage) RemoveMember(ctx context.Context, teamName, userID string) error {
+	if err := s.checkTeamAndUser(ctx, teamName, userID); err != nil {
+		return err
+	}
+
+	var primaryTeam string
+	if err := s.conn(ctx).QueryRowContext(ctx, "SELECT team_name FROM users WHERE user_id = ?", userID).Scan(&primaryTeam); err != nil {
+		return fmt.Errorf("select primary team: %w", err)
+	}
+	if primaryTeam == teamName {
+		return team.ErrPrimaryMembership
+	}
+
+	res, err := s.conn(ctx).ExecContext(ctx, "DELETE FROM team_memberships WHERE team_name = ? AND user_id = ?", teamName, userID)
+	if err != nil {
+		return fmt.Errorf("delete membership: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete membership: %w", err)
+	}
+	if n == 0 {
+		return team.ErrNotMember
+	}
+
+	return nil
+}
+
+func (s *sqliteStorage) GetTeamNamesByUserID(ctx context.Context, userID string) ([]string, error) {
+	var exists bool
+	if err := s.conn(ctx).QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE user_id = ?)", userID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("check user exists: %w", err)
+	}
+	if !exists {
+		return nil, user.ErrUserNotFound
+	}
+
+	query := `
+		SELECT team_name
+		FROM team_memberships
+		WHERE user_id = ?
+		ORDER BY team_name
+	`
+	rows, err := s.conn(ctx).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query memberships: %w", err)
+	}
+	defer rows.Close()
+
+	teams := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("scan membership: %w", err)
+		}
+		teams = append(teams, name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return teams, nil
+}
+
+func (s *sqliteStorage) ListTeamNames(ctx context.Context) ([]string, error) {
+	rows, err := s.conn(ctx).QueryContext(ctx, "SELECT team_name FROM teams ORDER BY team_name")
+	if err != nil {
+		return nil, fmt.Errorf("query teams: %w", err)
+	}
+	defer rows.Close()
+
+	names := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("scan team: %w", err)
+		}
+		names = append(names, name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return names, nil
+}
+
+func (s *sqliteStorage) checkTeamAndUser(ctx context.Context, teamName, userID string) error {
+	var teamExists, userExists bool
+	query := `
+		SELECT
+			EXISTS(SELECT 1 FROM teams WHERE team_name = ?),
+			EXISTS(SELECT 1 FROM users WHERE user_id = ?)
+	`
+	if err := s.conn(ctx).QueryRowContext(ctx, query, teamName, userID).Scan(&teamExists, &userExists); err != nil {
+		return fmt.Errorf("check team and user exist: %w", err)
+	}
+	if !teamExists {
+		return team.ErrTeamNotFound
+	}
+	if !userExists {
+		return user.ErrUserNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package token
+
+import (
+	"InternshipTask/internal/domain/auth"
+	"InternshipTask/internal/infrastructure/sqlite"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type sqliteStorage struct {
+	db *sql.DB
+}
+
+func NewSQLiteStorage(db *sql.DB) *sqliteStorage {
+	return &sqliteStorage{
+		db: db,
+	}
+}
+
+func (s *sqliteStorage) conn(ctx context.Context) sqlite.DBTX {
+	return sqlite.Conn(ctx, s.db)
+}
+
+var _ auth.Storager = (*sqliteStorage)(nil)
+
+const columns = `token_id, user_id, role, description, token_hash, created_at, expires_at, revoked_at`
+
+func (s *sqliteStorage) Create(ctx context.Context, t *auth.Token) error {
+	query := `
+		INSERT INTO api_tokens (` + columns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := s.conn(ctx).ExecContext(ctx, query,
+		t.ID,
+		t.UserID,
+		string(t.Role),
+		t.Description,
+		t.Hash,
+		sqlite.FormatTime(t.CreatedAt),
+		sqlite.NullTime(t.ExpiresAt),
+		sqlite.NullTime(t.RevokedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("insert token: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqliteStorage) GetByHash(ctx context.Context, hash string) (*auth.Token, error) {
+	query := `SELECT ` + columns + ` FROM api_tokens WHERE token_hash = ?`
+
+	t, err := scanToken(s.conn(ctx).QueryRowContext(ctx, query, hash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, auth.ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("select token: %w", err)
+	}
+
+	return t, nil
+}
+
+func (s *sqliteStorage) List(ctx context.Context) ([]auth.Token, error) {
+	query := `SELECT ` + columns + ` FROM api_tokens ORDER BY created_at, token_id`
+
+	rows, err := s.conn(ctx).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("select tokens: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]auth.Token, 0)
+	for rows.Next() {
+		t, err := scanToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan token: %w", err)
+		}
+		result = append(result, *t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return result, nil
+}
+
+func (s *sqliteStorage) Revoke(ctx context.Context, id string, at time.Time) (*auth.Token, error) {
+	query := `
+		UPDATE api_tokens
+		SET revoked_at = COALESCE(revoked_at, ?2)
+		WHERE token_id = ?1
+		RETURNING ` + columns
+
+	t, err := scanToken(s.conn(ctx).QueryRowContext(ctx, query, id, sqlite.FormatTime(at)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, auth.ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("revoke token: %w", err)
+	}
+
+	return t, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanToken(row scanner) (*auth.Token, error) {
+	var (
+		t         auth.Token
+		role      string
+		createdAt *time.Time
+	)
+	err := row.Scan(
+		&t.ID,
+		&t.UserID,
+		&role,
+		&t.Description,
+		&t.Hash,
+		sqlite.ScanTime(&createdAt),
+		sqlite.ScanTime(&t.ExpiresAt),
+		sqlite.ScanTime(&t.RevokedAt),
+	)
+	if err != nil {
+		return nil, err
+	}
+	t.Role = auth.Role(role)
+	t.CreatedAt = *createdAt
+	return &t, nil
+}
@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"InternshipTask/internal/domain/txn"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// DBTX is the subset of sql.DB and sql.Tx the storages run queries on.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type txKey struct{}
+
+// Conn returns the transaction started by TxManager for ctx, or db when ctx
+// is not inside a unit of work.
+func Conn(ctx context.Context, db *sql.DB) DBTX {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return loggingConn{tx}
+	}
+	return loggingConn{db}
+}
+
+// InTx runs fn atomically: in a new transaction, or in a savepoint when ctx
+// is already inside a unit of work.
+func InTx(ctx context.Context, db *sql.DB, fn func(tx DBTX) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT storage"); err != nil {
+			return fmt.Errorf("savepoint: %w", err)
+		}
+		if err := fn(loggingConn{tx}); err != nil {
+			tx.ExecContext(ctx, "ROLLBACK TO storage")
+			tx.ExecContext(ctx, "RELEASE storage")
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "RELEASE storage"); err != nil {
+			return fmt.Errorf("release savepoint: %w", err)
+		}
+		return nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(loggingConn{tx}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+type TxManager struct {
+	db *sql.DB
+}
+
+func NewTxManager(db *sql.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+var _ txn.Manager = (*TxManager)(nil)
+
+func (m *TxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// timeLayout is fixed-width so that stored timestamps sort as strings.
+const timeLayout = "2006-01-02T15:04:05.000000000Z"
+
+func FormatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// NullTime is the column value of an optional timestamp.
+func NullTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return FormatTime(*t)
+}
+
+// ScanTime scans an optional timestamp into dst, leaving it nil for NULL.
+func ScanTime(dst **time.Time) sql.Scanner {
+	return timeScanner{dst: dst}
+}
+
+type timeScanner struct {
+	dst **time.Time
+}
+
+func (s timeScanner) Scan(src any) error {
+	var raw string
+	switch v := src.(type) {
+	case nil:
+		*s.dst = nil
+		return nil
+	case time.Time:
+		t := v.UTC()
+		*s.dst = &t
+		return nil
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("scan time: unsupported type %T", src)
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		return fmt.Errorf("scan time: %w", err)
+	}
+	*s.dst = &t
+
+	return nil
+}
+
+// Strings is the column value of a string list stored as a JSON array.
+func Strings(values []string) string {
+	if values == nil {
+		values = []string{}
+	}
+	data, _ := json.Marshal(values)
+	return string(data)
+}
+
+// ScanStrings scans a JSON array of strings, such as the result of
+// json_group_array, into dst.
+func ScanStrings(dst *[]string) sql.Scanner {
+	return stringsScanner{dst: dst}
+}
+
+type stringsScanner struct {
+	dst *[]string
+}
+
+func (s stringsScanner) Scan(src any) error {
+	var raw []byte
+	switch v := src.(type) {
+	case nil:
+		*s.dst = []string{}
+		return nil
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return fmt.Errorf("scan strings: unsupported type %T", src)
+	}
+
+	values := make([]string, 0)
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return fmt.Errorf("scan strings: %w", err)
+	}
+	*s.dst = values
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package user
+
+import (
+	domain "InternshipTask/internal/domain/user"
+	"InternshipTask/internal/infrastructure/sqlite"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type sqliteStorage struct {
+	db *sql.DB
+}
+
+func NewSQLiteStorage(db *sql.DB) *sqliteStorage {
+	return &sqliteStorage{
+		db: db,
+	}
+}
+
+func (s *sqliteStorage) conn(ctx context.Context) sqlite.DBTX {
+	return sqlite.Conn(ctx, s.db)
+}
+
+var _ domain.Storager = (*sqliteStorage)(nil)
+
+// userColumns selects a full user from the users table; skills come back as a
+// JSON array ordered by name.
+const userColumns = `
+	users.user_id, users.username, users.team_name, users.is_active,
+	(SELECT json_group_array(skill) FROM (
+		SELECT skill FROM user_skills WHERE user_skills.user_id = users.user_id ORDER BY skill
+	)),
+	users.time_zone, users.work_start_minute, users.work_end_minute, users.departed_at
+`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanUser scans a row selected with userColumns.
+func scanUser(row scanner) (*domain.User, error) {
+	var u domain.User
+
+	err := row.Scan(&u.UserId, &u.UserName, &u.TeamName, &u.IsActive, sqlite.ScanStrings(&u.Skills),
+		&u.Schedule.TimeZone, &u.Schedule.WorkStart, &u.Schedule.WorkEnd, sqlite.ScanTime(&u.DepartedAt))
+	if err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+func (s *sqliteStorage) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE user_id = ?`
+
+	u, err := scanUser(s.conn(ctx).QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("select user: %w", err)
+	}
+
+	return u, nil
+}
+
+func (s *sqliteStorage) SetIsActive(ctx context.Context, id string, isActive bool) (*domain.User, error) {
+	if err := s.update(ctx, "UPDATE users SET is_active = ? WHERE user_id = ?", isActive, id); err != nil {
+		return nil, fmt.Errorf("update user is_active: %w", err)
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+func (s *sqliteStorage) SetSkills(ctx context.Context, id string, skills []string) (*domain.User, error) {
+	err := sqlite.InTx(ctx, s.db, func(tx sqlite.DBTX) error {
+		var exists bool
+		if err := tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE user_id = ?)", id).Scan(&exists); err != nil {
+			return fmt.Errorf("check user exists: %w", err)
+		}
+		if !exists {
+			return domain.ErrUserNotFound
+		}
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM user_skills WHERE user_id = ?", id); err != nil {
+			return fmt.Errorf("delete user skills: %w", err)
+		}
+
+		// WHERE true keeps SQLite from parsing ON CONFLICT as a join constraint.
+		insertQuery := `
+			INSERT INTO user_skills (user_id, skill)
+			SELECT ?, value FROM json_each(?) WHERE true
+			ON CONFLICT (user_id, skill) DO NOTHING
+		`
+		if _, err := tx.ExecContext(ctx, insertQuery, id, sqlite.Strings(skills)); err != nil {
+			return fmt.Errorf("insert user skills: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+func (s *sqliteStorage) SetSchedule(ctx context.Context, id string, schedule domain.Schedule) (*domain.User, error) {
+	query := `
+		UPDATE users
+		SET time_zone = ?, work_start_minute = ?, work_end_minute = ?
+		WHERE user_id = ?
+	`
+
+	if err := s.update(ctx, query, schedule.TimeZone, schedule.WorkStart, schedule.WorkEnd, id); err != nil {
+		return nil, fmt.Errorf("update user schedule: %w", err)
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+func (s *sqliteStorage) MarkDeparted(ctx context.Context, id string, at time.Time) (*domain.User, error) {
+	query := `
+		UPDATE users
+		SET is_active = 0, departed_at = COALESCE(departed_at, ?)
+		WHERE user_id = ?
+	`
+
+	if err := s.update(ctx, query, sqlite.FormatTime(at), id); err != nil {
+		return nil, fmt.Errorf("update user departed_at: %w", err)
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// update runs a single-row UPDATE and reports ErrUserNotFound when it matched
+// nothing.
+func (s *sqliteStorage) update(ctx context.Context, query string, args ...any) error {
+	res, err := s.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
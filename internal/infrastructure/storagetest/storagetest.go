@@ -0,0 +1,1092 @@
+// Package storagetest is a conformance suite that every storage backend must
+// pass, so that the backends stay interchangeable.
+package storagetest
+
+import (
+	"InternshipTask/internal/domain/audit"
+	"InternshipTask/internal/domain/auth"
+	"InternshipTask/internal/domain/events"
+	"InternshipTask/internal/domain/idempotency"
+	"InternshipTask/internal/domain/pull_request"
+	"InternshipTask/internal/domain/team"
+	"InternshipTask/internal/domain/txn"
+	"InternshipTask/internal/domain/user"
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+type Backend struct {
+	Teams       team.Storager
+	Users       user.Storager
+	PRs         pull_request.Repository
+	Audit       audit.Storager
+	Outbox      events.Outbox
+	Idempotency idempotency.Store
+	Tokens      auth.Storager
+	Tx          txn.Manager
+}
+
+// Factory returns a backend over an empty database.
+type Factory func(t *testing.T) Backend
+
+func Run(t *testing.T, newBackend Factory) {
+	tests := map[string]func(t *testing.T, b Backend){
+		"TeamCreateAndGet":        testTeamCreateAndGet,
+		"TeamUpsertKeepsUserData": testTeamUpsertKeepsUserData,
+		"Memberships":             testMemberships,
+		"Users":                   testUsers,
+		"PullRequestRoundTrip":    testPullRequestRoundTrip,
+		"PullRequestUpdate":       testPullRequestUpdate,
+		"PullRequestQueries":      testPullRequestQueries,
+		"PullRequestList":         testPullRequestList,
+		"Reviews":                 testReviews,
+		"UnitOfWorkRollback":      testUnitOfWorkRollback,
+		"Archive":                 testArchive,
+		"AuditLog":                testAuditLog,
+		"Outbox":                  testOutbox,
+		"Idempotency":             testIdempotency,
+		"Tokens":                  testTokens,
+	}
+
+	names := make([]string, 0, len(tests))
+	for name := range tests {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			tests[name](t, newBackend(t))
+		})
+	}
+}
+
+var base = time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+
+func seedTeam(t *testing.T, b Backend, name string, members ...*user.User) {
+	t.Helper()
+
+	tm := team.NewTeam(name)
+	for i, m := range members {
+		tm.Members[uint(i)] = m
+	}
+	if err := b.Teams.Create(context.Background(), *tm); err != nil {
+		t.Fatalf("Teams.Create(%s) error = %v", name, err)
+	}
+}
+
+func seedPR(t *testing.T, b Backend, id, authorID string, createdAt time.Time, reviewers ...string) *pull_request.PR {
+	t.Helper()
+
+	pr := pull_request.NewPR(id, "PR "+id, authorID, pull_request.OPEN)
+	pr.TeamName = "backend"
+	pr.CreatedAt = &createdAt
+	pr.AssignedReviewers = reviewers
+	pr.RequiredSkills = []string{"go"}
+	pr.ReviewerChange = pull_request.ReviewerChange{Reason: pull_request.ReasonCreated, Actor: "lead", At: createdAt}
+	if err := b.PRs.Create(context.Background(), pr); err != nil {
+		t.Fatalf("PRs.Create(%s) error = %v", id, err)
+	}
+	return pr
+}
+
+func seedBackend(t *testing.T, b Backend) {
+	t.Helper()
+
+	seedTeam(t, b, "backend",
+		user.NewUser("u1", "Alice", "", true),
+		user.NewUser("u2", "Bob", "", true),
+		user.NewUser("u3", "Charlie", "", false),
+	)
+}
+
+func testTeamCreateAndGet(t *testing.T, b Backend) {
+	ctx := context.Background()
+	seedTeam(t, b, "backend",
+		user.NewUser("u2", "Bob", "", true),
+		user.NewUser("u1", "Alice", "", false),
+	)
+
+	got, err := b.Teams.GetByTeamName(ctx, "backend")
+	if err != nil {
+		t.Fatalf("GetByTeamName() error = %v", err)
+	}
+	if len(got.Members) != 2 || got.Members[0].UserId != "u1" || got.Members[1].UserId != "u2" {
+		t.Fatalf("expected members ordered by id, got %+v", got.Members)
+	}
+	alice := got.Members[0]
+	if alice.UserName != "Alice" || alice.TeamName != "backend" || alice.IsActive {
+		t.Fatalf("unexpected member: %+v", alice)
+	}
+	if len(alice.Skills) != 0 || alice.Schedule != user.DefaultSchedule() || alice.DepartedAt != nil {
+		t.Fatalf("expected new users to get defaults, got %+v", alice)
+	}
+
+	if _, err := b.Teams.GetByTeamName(ctx, "missing"); !errors.Is(err, team.ErrTeamNotFound) {
+		t.Fatalf("expected ErrTeamNotFound, got %v", err)
+	}
+
+	if err := b.Teams.Create(ctx, *team.NewTeam("backend")); !errors.Is(err, team.ErrTeamExists) {
+		t.Fatalf("expected ErrTeamExists for a taken name, got %v", err)
+	}
+
+	seedTeam(t, b, "api")
+	names, err := b.Teams.ListTeamNames(ctx)
+	if err != nil || !slices.Equal(names, []string{"api", "backend"}) {
+		t.Fatalf("expected team names in order, got %v, %v", names, err)
+	}
+}
+
+func testTeamUpsertKeepsUserData(t *testing.T, b Backend) {
+	ctx := context.Background()
+	seedBackend(t, b)
+
+	if _, err := b.Users.SetSkills(ctx, "u1", []string{"go"}); err != nil {
+		t.Fatalf("SetSkills() error = %v", err)
+	}
+	if _, err := b.Users.MarkDeparted(ctx, "u2", base); err != nil {
+		t.Fatalf("MarkDeparted() error = %v", err)
+	}
+
+	seedTeam(t, b, "platform",
+		user.NewUser("u1", "Alice A.", "", true),
+		user.NewUser("u2", "Bob", "", true),
+	)
+
+	alice, err := b.Users.GetByID(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if alice.UserName != "Alice A." || alice.TeamName != "platform" || !slices.Equal(alice.Skills, []string{"go"}) {
+		t.Fatalf("expected upsert to rename, move and keep skills, got %+v", alice)
+	}
+
+	bob, err := b.Users.GetByID(ctx, "u2")
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if bob.IsActive || bob.DepartedAt == nil {
+		t.Fatalf("departed user must stay inactive, got %+v", bob)
+	}
+
+	teams, err := b.Teams.GetTeamNamesByUserID(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetTeamNamesByUserID() error = %v", err)
+	}
+	if !slices.Equal(teams, []string{"backend", "platform"}) {
+		t.Fatalf("expected previous membership to be kept, got %v", teams)
+	}
+}
+
+func testMemberships(t *testing.T, b Backend) {
+	ctx := context.Background()
+	seedBackend(t, b)
+	seedTeam(t, b, "platform")
+
+	if err := b.Teams.AddMember(ctx, "missing", "u1"); !errors.Is(err, team.ErrTeamNotFound) {
+		t.Fatalf("expected ErrTeamNotFound, got %v", err)
+	}
+	if err := b.Teams.AddMember(ctx, "platform", "ghost"); !errors.Is(err, user.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+	for range 2 {
+		if err := b.Teams.AddMember(ctx, "platform", "u1"); err != nil {
+			t.Fatalf("AddMember() error = %v", err)
+		}
+	}
+
+	teams, err := b.Teams.GetTeamNamesByUserID(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetTeamNamesByUserID() error = %v", err)
+	}
+	if !slices.Equal(teams, []string{"backend", "platform"}) {
+		t.Fatalf("unexpected teams: %v", teams)
+	}
+
+	got, err := b.Teams.GetByTeamName(ctx, "platform")
+	if err != nil {
+		t.Fatalf("GetByTeamName() error = %v", err)
+	}
+	if len(got.Members) != 1 || got.Members[0].UserId != "u1" || got.Members[0].TeamName != "backend" {
+		t.Fatalf("expected u1 as a secondary member, got %+v", got.Members)
+	}
+
+	if err := b.Teams.RemoveMember(ctx, "backend", "u1"); !errors.Is(err, team.ErrPrimaryMembership) {
+		t.Fatalf("expected ErrPrimaryMembership, got %v", err)
+	}
+	if err := b.Teams.RemoveMember(ctx, "platform", "u1"); err != nil {
+		t.Fatalf("RemoveMember() error = %v", err)
+	}
+	if err := b.Teams.RemoveMember(ctx, "platform", "u1"); !errors.Is(err, team.ErrNotMember) {
+		t.Fatalf("expected ErrNotMember, got %v", err)
+	}
+	if _, err := b.Teams.GetTeamNamesByUserID(ctx, "ghost"); !errors.Is(err, user.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func testUsers(t *testing.T, b Backend) {
+	ctx := context.Background()
+	seedBackend(t, b)
+
+	if _, err := b.Users.GetByID(ctx, "ghost"); !errors.Is(err, user.ErrUserNotFound) {
+		t.Fatalf("GetByID: expected ErrUserNotFound, got %v", err)
+	}
+	if _, err := b.Users.SetIsActive(ctx, "ghost", true); !errors.Is(err, user.ErrUserNotFound) {
+		t.Fatalf("SetIsActive: expected ErrUserNotFound, got %v", err)
+	}
+	if _, err := b.Users.SetSkills(ctx, "ghost", nil); !errors.Is(err, user.ErrUserNotFound) {
+		t.Fatalf("SetSkills: expected ErrUserNotFound, got %v", err)
+	}
+	if _, err := b.Users.SetSchedule(ctx, "ghost", user.DefaultSchedule()); !errors.Is(err, user.ErrUserNotFound) {
+		t.Fatalf("SetSchedule: expected ErrUserNotFound, got %v", err)
+	}
+	if _, err := b.Users.MarkDeparted(ctx, "ghost", base); !errors.Is(err, user.ErrUserNotFound) {
+		t.Fatalf("MarkDeparted: expected ErrUserNotFound, got %v", err)
+	}
+
+	u, err := b.Users.SetIsActive(ctx, "u3", true)
+	if err != nil || !u.IsActive {
+		t.Fatalf("SetIsActive() = %+v, %v", u, err)
+	}
+
+	u, err = b.Users.SetSkills(ctx, "u1", []string{"postgres", "go", "go"})
+	if err != nil || !slices.Equal(u.Skills, []string{"go", "postgres"}) {
+		t.Fatalf("SetSkills() = %+v, %v", u, err)
+	}
+	u, err = b.Users.SetSkills(ctx, "u1", []string{"rust"})
+	if err != nil || !slices.Equal(u.Skills, []string{"rust"}) {
+		t.Fatalf("expected skills to be replaced, got %+v, %v", u, err)
+	}
+
+	schedule := user.Schedule{TimeZone: "Europe/Moscow", WorkStart: 600, WorkEnd: 1170}
+	u, err = b.Users.SetSchedule(ctx, "u1", schedule)
+	if err != nil || u.Schedule != schedule {
+		t.Fatalf("SetSchedule() = %+v, %v", u, err)
+	}
+
+	u, err = b.Users.MarkDeparted(ctx, "u2", base)
+	if err != nil || u.IsActive || u.DepartedAt == nil || !u.DepartedAt.Equal(base) {
+		t.Fatalf("MarkDeparted() = %+v, %v", u, err)
+	}
+	u, err = b.Users.MarkDeparted(ctx, "u2", base.Add(time.Hour))
+	if err != nil || !u.DepartedAt.Equal(base) {
+		t.Fatalf("expected the first departure time to be kept, got %+v, %v", u, err)
+	}
+}
+
+func testPullRequestRoundTrip(t *testing.T, b Backend) {
+	ctx := context.Background()
+	seedBackend(t, b)
+
+	created := seedPR(t, b, "pr-1", "u1", base, "u2", "u3")
+	if created.Version != 1 {
+		t.Fatalf("expected Create to set version 1, got %d", created.Version)
+	}
+
+	got, err := b.PRs.GetByID(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if got.PullRequestName != "PR pr-1" || got.AuthorId != "u1" || got.TeamName != "backend" || got.Status != pull_request.OPEN {
+		t.Fatalf("unexpected pr: %+v", got)
+	}
+	if !slices.Equal(got.AssignedReviewers, []string{"u2", "u3"}) || !slices.Equal(got.RequiredSkills, []string{"go"}) {
+		t.Fatalf("unexpected reviewers or skills: %+v", got)
+	}
+	if got.CreatedAt == nil || !got.CreatedAt.Equal(base) || got.MergedAt != nil || got.Version != 1 {
+		t.Fatalf("unexpected timestamps or version: %+v", got)
+	}
+
+	if _, err := b.PRs.GetByID(ctx, "missing"); !errors.Is(err, pull_request.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if err := b.PRs.Create(ctx, pull_request.NewPR("pr-1", "Again", "u1", pull_request.OPEN)); err == nil {
+		t.Fatalf("expected duplicate Create to fail")
+	}
+}
+
+func testPullRequestUpdate(t *testing.T, b Backend) {
+	ctx := context.Background()
+	seedBackend(t, b)
+	seedPR(t, b, "pr-1", "u1", base, "u2")
+
+	pr, err := b.PRs.GetByID(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	stale := *pr
+
+	pr.AssignedReviewers = []string{"u3"}
+	pr.ReviewerChange = pull_request.ReviewerChange{Reason: pull_request.ReasonReassigned, Actor: "lead", At: base.Add(time.Hour)}
+	if err := b.PRs.Update(ctx, pr); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if pr.Version != 2 {
+		t.Fatalf("expected version 2, got %d", pr.Version)
+	}
+
+	stale.Status = pull_request.MERGED
+	if err := b.PRs.Update(ctx, &stale); !errors.Is(err, pull_request.ErrConflict) {
+		t.Fatalf("expected ErrConflict for a stale version, got %v", err)
+	}
+	missing := pull_request.NewPR("missing", "Missing", "u1", pull_request.OPEN)
+	if err := b.PRs.Update(ctx, missing); !errors.Is(err, pull_request.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	mergedAt := base.Add(2 * time.Hour)
+	pr.Status = pull_request.MERGED
+	pr.MergedAt = &mergedAt
+	pr.AuthorId = "u2"
+	pr.ReviewerChange = pull_request.ReviewerChange{}
+	if err := b.PRs.Update(ctx, pr); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	got, err := b.PRs.GetByID(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if got.Status != pull_request.MERGED || got.MergedAt == nil || !got.MergedAt.Equal(mergedAt) || got.AuthorId != "u2" {
+		t.Fatalf("unexpected pr after update: %+v", got)
+	}
+	if !slices.Equal(got.AssignedReviewers, []string{"u3"}) || got.Version != 3 {
+		t.Fatalf("unexpected reviewers or version: %+v", got)
+	}
+
+	history, err := b.PRs.GetAssignments(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("GetAssignments() error = %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 assignments, got %+v", history)
+	}
+	first, second := history[0], history[1]
+	if first.ReviewerID != "u2" || first.AssignedReason != pull_request.ReasonCreated || first.AssignedBy != "lead" ||
+		first.UnassignedAt == nil || !first.UnassignedAt.Equal(base.Add(time.Hour)) || first.UnassignReason != pull_request.ReasonReassigned {
+		t.Fatalf("unexpected closed assignment: %+v", first)
+	}
+	if second.ReviewerID != "u3" || second.AssignedReason != pull_request.ReasonReassigned || second.UnassignedAt != nil {
+		t.Fatalf("unexpected open assignment: %+v", second)
+	}
+}
+
+func testPullRequestQueries(t *testing.T, b Backend) {
+	ctx := context.Background()
+	seedBackend(t, b)
+	seedPR(t, b, "pr-2", "u1", base.Add(time.Hour), "u2")
+	seedPR(t, b, "pr-1", "u1", base, "u2", "u3")
+	seedPR(t, b, "pr-3", "u2", base.Add(2*time.Hour), "u3")
+
+	pr, err := b.PRs.GetByID(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	pr.AssignedReviewers = []string{"u3"}
+	pr.Status = pull_request.MERGED
+	pr.ReviewerChange = pull_request.ReviewerChange{Reason: pull_request.ReasonReleased, At: base.Add(3 * time.Hour)}
+	if err := b.PRs.Update(ctx, pr); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	reviews, err := b.PRs.GetByReviewerID(ctx, "u3", false)
+	if err != nil {
+		t.Fatalf("GetByReviewerID() error = %v", err)
+	}
+	if ids := shortIDs(reviews); !slices.Equal(ids, []string{"pr-1", "pr-3"}) {
+		t.Fatalf("expected current reviews ordered by creation, got %v", ids)
+	}
+	reviews, err = b.PRs.GetByReviewerID(ctx, "u2", false)
+	if err != nil {
+		t.Fatalf("GetByReviewerID() error = %v", err)
+	}
+	if ids := shortIDs(reviews); !slices.Equal(ids, []string{"pr-2"}) {
+		t.Fatalf("expected unassigned reviews to be skipped, got %v", ids)
+	}
+
+	open, err := b.PRs.GetOpenByAuthorID(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetOpenByAuthorID() error = %v", err)
+	}
+	if ids := shortIDs(open); !slices.Equal(ids, []string{"pr-2"}) {
+		t.Fatalf("expected only open authored PRs, got %v", ids)
+	}
+
+	stats, err := b.PRs.GetReviewerStats(ctx, false)
+	if err != nil {
+		t.Fatalf("GetReviewerStats() error = %v", err)
+	}
+	if stats["u2"] != 2 || stats["u3"] != 2 || len(stats) != 2 {
+		t.Fatalf("expected stats to count the whole history, got %v", stats)
+	}
+
+	load, err := b.PRs.GetOpenReviewCounts(ctx, []string{"u2", "u3", "u1"})
+	if err != nil {
+		t.Fatalf("GetOpenReviewCounts() error = %v", err)
+	}
+	if !maps.Equal(load, map[string]int64{"u2": 1, "u3": 1}) {
+		t.Fatalf("expected only current reviews of OPEN PRs, got %v", load)
+	}
+
+	history, err := b.PRs.GetAssignments(ctx, "missing")
+	if err != nil || len(history) != 0 {
+		t.Fatalf("expected empty history for an unknown PR, got %v, %v", history, err)
+	}
+}
+
+func testPullRequestList(t *testing.T, b Backend) {
+	ctx := context.Background()
+	seedBackend(t, b)
+	seedPR(t, b, "a-2", "u1", base, "u2")
+	seedPR(t, b, "a-1", "u1", base, "u2")
+	seedPR(t, b, "b-1", "u2", base.Add(time.Hour), "u1")
+	seedPR(t, b, "c_1", "u1", base.Add(2*time.Hour), "u3")
+
+	pr, err := b.PRs.GetByID(ctx, "a-2")
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	mergedAt := base.Add(3 * time.Hour)
+	pr.Status = pull_request.MERGED
+	pr.MergedAt = &mergedAt
+	if err := b.PRs.Update(ctx, pr); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	list := func(f pull_request.ListFilter) []string {
+		t.Helper()
+		if f.Sort == "" {
+			f.Sort = pull_request.SortCreatedAsc
+		}
+		prs, err := b.PRs.List(ctx, f)
+		if err != nil {
+			t.Fatalf("List(%+v) error = %v", f, err)
+		}
+		ids := make([]string, 0, len(prs))
+		for _, pr := range prs {
+			ids = append(ids, pr.PullRequestId)
+		}
+		return ids
+	}
+
+	cases := []struct {
+		name   string
+		filter pull_request.ListFilter
+		want   []string
+	}{
+		{"ascending with ties by id", pull_request.ListFilter{}, []string{"a-1", "a-2", "b-1", "c_1"}},
+		{"descending", pull_request.ListFilter{Sort: pull_request.SortCreatedDesc, Limit: 2}, []string{"c_1", "b-1"}},
+		{"after cursor", pull_request.ListFilter{
+			Sort:  pull_request.SortCreatedDesc,
+			After: &pull_request.Cursor{Sort: pull_request.SortCreatedDesc, CreatedAt: base.Add(time.Hour), ID: "b-1"},
+		}, []string{"a-2", "a-1"}},
+		{"status", pull_request.ListFilter{Status: pull_request.MERGED}, []string{"a-2"}},
+		{"author", pull_request.ListFilter{AuthorID: "u2"}, []string{"b-1"}},
+		{"team", pull_request.ListFilter{TeamName: "frontend"}, []string{}},
+		{"current reviewer", pull_request.ListFilter{ReviewerID: "u3"}, []string{"c_1"}},
+		{"name ignores case", pull_request.ListFilter{Name: "pr A"}, []string{"a-1", "a-2"}},
+		{"name escapes wildcards", pull_request.ListFilter{Name: "_"}, []string{"c_1"}},
+		{"created range", pull_request.ListFilter{CreatedFrom: base.Add(time.Hour), CreatedTo: base.Add(2 * time.Hour)}, []string{"b-1"}},
+		{"merged range", pull_request.ListFilter{MergedFrom: base}, []string{"a-2"}},
+	}
+	for _, tc := range cases {
+		if got := list(tc.filter); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+
+	prs, err := b.PRs.List(ctx, pull_request.ListFilter{Sort: pull_request.SortCreatedAsc, Limit: 1})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(prs) != 1 || prs[0].TeamName != "backend" || !slices.Equal(prs[0].AssignedReviewers, []string{"u2"}) ||
+		!slices.Equal(prs[0].RequiredSkills, []string{"go"}) || prs[0].CreatedAt == nil || !prs[0].CreatedAt.Equal(base) {
+		t.Fatalf("expected full PRs, got %+v", prs)
+	}
+
+	if n, err := b.PRs.ArchiveMerged(ctx, base.Add(4*time.Hour), 10); err != nil || n != 1 {
+		t.Fatalf("ArchiveMerged() = %d, %v; want 1", n, err)
+	}
+	if got := list(pull_request.ListFilter{}); !slices.Equal(got, []string{"a-1", "b-1", "c_1"}) {
+		t.Fatalf("expected archived PRs to be hidden, got %v", got)
+	}
+	if got := list(pull_request.ListFilter{IncludeArchived: true, ReviewerID: "u2"}); !slices.Equal(got, []string{"a-1", "a-2"}) {
+		t.Fatalf("expected archived PRs on request, got %v", got)
+	}
+}
+
+func testReviews(t *testing.T, b Backend) {
+	ctx := context.Background()
+	seedBackend(t, b)
+	seedPR(t, b, "pr-2", "u1", base.Add(time.Hour), "u2")
+	seedPR(t, b, "pr-1", "u1", base, "u2", "u3")
+	seedPR(t, b, "pr-3", "u1", base.Add(2*time.Hour), "u3")
+
+	verdictAt := base.Add(90 * time.Minute)
+	if err := b.PRs.SetVerdict(ctx, "pr-1", "u2", pull_request.VerdictApproved, verdictAt); err != nil {
+		t.Fatalf("SetVerdict() error = %v", err)
+	}
+	if err := b.PRs.SetVerdict(ctx, "pr-3", "u2", pull_request.VerdictApproved, verdictAt); !errors.Is(err, pull_request.ErrNotAssigned) {
+		t.Fatalf("expected ErrNotAssigned for a non-reviewer, got %v", err)
+	}
+
+	pr, err := b.PRs.GetByID(ctx, "pr-2")
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	mergedAt := base.Add(3 * time.Hour)
+	pr.Status = pull_request.MERGED
+	pr.MergedAt = &mergedAt
+	if err := b.PRs.Update(ctx, pr); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	reviews, err := b.PRs.ListReviews(ctx, pull_request.ReviewFilter{ReviewerID: "u2"})
+	if err != nil {
+		t.Fatalf("ListReviews() error = %v", err)
+	}
+	if len(reviews) != 2 || reviews[0].PullRequestId != "pr-1" || reviews[1].PullRequestId != "pr-2" {
+		t.Fatalf("expected both reviews oldest first, got %+v", reviews)
+	}
+	first := reviews[0]
+	if first.Verdict != pull_request.VerdictApproved || first.VerdictAt == nil || !first.VerdictAt.Equal(verdictAt) ||
+		!first.AssignedAt.Equal(base) || first.TeamName != "backend" || first.CreatedAt == nil || !first.CreatedAt.Equal(base) {
+		t.Fatalf("unexpected review: %+v", first)
+	}
+	if second := reviews[1]; second.Verdict != pull_request.VerdictPending || second.VerdictAt != nil ||
+		second.MergedAt == nil || !second.MergedAt.Equal(mergedAt) {
+		t.Fatalf("unexpected review: %+v", second)
+	}
+
+	ids := func(f pull_request.ReviewFilter) []string {
+		t.Helper()
+		reviews, err := b.PRs.ListReviews(ctx, f)
+		if err != nil {
+			t.Fatalf("ListReviews(%+v) error = %v", f, err)
+		}
+		result := make([]string, 0, len(reviews))
+		for _, r := range reviews {
+			result = append(result, r.PullRequestId)
+		}
+		return result
+	}
+	cursor := &pull_request.Cursor{Sort: pull_request.SortCreatedAsc, CreatedAt: base, ID: "pr-1"}
+	cases := []struct {
+		name   string
+		filter pull_request.ReviewFilter
+		want   []string
+	}{
+		{"status", pull_request.ReviewFilter{ReviewerID: "u2", Status: pull_request.OPEN}, []string{"pr-1"}},
+		{"team", pull_request.ReviewFilter{ReviewerID: "u3", TeamName: "frontend"}, []string{}},
+		{"created range", pull_request.ReviewFilter{ReviewerID: "u3", CreatedFrom: base.Add(time.Hour)}, []string{"pr-3"}},
+		{"after cursor", pull_request.ReviewFilter{ReviewerID: "u3", After: cursor}, []string{"pr-3"}},
+		{"limit", pull_request.ReviewFilter{ReviewerID: "u3", Limit: 1}, []string{"pr-1"}},
+	}
+	for _, tc := range cases {
+		if got := ids(tc.filter); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+
+	history, err := b.PRs.GetAssignments(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("GetAssignments() error = %v", err)
+	}
+	for _, a := range history {
+		want := pull_request.VerdictPending
+		if a.ReviewerID == "u2" {
+			want = pull_request.VerdictApproved
+		}
+		if a.Verdict != want {
+			t.Fatalf("expected verdict %s for %s, got %+v", want, a.ReviewerID, a)
+		}
+	}
+
+	if n, err := b.PRs.ArchiveMerged(ctx, base.Add(4*time.Hour), 10); err != nil || n != 1 {
+		t.Fatalf("ArchiveMerged() = %d, %v; want 1", n, err)
+	}
+	if got := ids(pull_request.ReviewFilter{ReviewerID: "u2", IncludeArchived: true}); !slices.Equal(got, []string{"pr-1", "pr-2"}) {
+		t.Fatalf("expected archived reviews on request, got %v", got)
+	}
+}
+
+func testArchive(t *testing.T, b Backend) {
+	ctx := context.Background()
+	seedBackend(t, b)
+	merge := func(id string, at time.Time) {
+		t.Helper()
+		pr, err := b.PRs.GetByID(ctx, id)
+		if err != nil {
+			t.Fatalf("GetByID(%s) error = %v", id, err)
+		}
+		pr.Status = pull_request.MERGED
+		pr.MergedAt = &at
+		if err := b.PRs.Update(ctx, pr); err != nil {
+			t.Fatalf("Update(%s) error = %v", id, err)
+		}
+	}
+	seedPR(t, b, "old-1", "u1", base, "u2")
+	seedPR(t, b, "old-2", "u1", base, "u2")
+	seedPR(t, b, "recent", "u1", base, "u2")
+	seedPR(t, b, "open", "u1", base, "u3")
+	merge("old-2", base.Add(time.Hour))
+	merge("old-1", base.Add(2*time.Hour))
+	merge("recent", base.Add(48*time.Hour))
+
+	cutoff := base.Add(24 * time.Hour)
+	if n, err := b.PRs.ArchiveMerged(ctx, cutoff, 1); err != nil || n != 1 {
+		t.Fatalf("ArchiveMerged(limit 1) = %d, %v; want 1", n, err)
+	}
+	if _, err := b.PRs.GetByID(ctx, "old-2"); !errors.Is(err, pull_request.ErrNotFound) {
+		t.Fatalf("expected the oldest merge to be archived first, got %v", err)
+	}
+	if n, err := b.PRs.ArchiveMerged(ctx, cutoff, 10); err != nil || n != 1 {
+		t.Fatalf("ArchiveMerged() = %d, %v; want 1", n, err)
+	}
+	if n, err := b.PRs.ArchiveMerged(ctx, cutoff, 10); err != nil || n != 0 {
+		t.Fatalf("expected nothing left to archive, got %d, %v", n, err)
+	}
+
+	reviews, err := b.PRs.GetByReviewerID(ctx, "u2", false)
+	if err != nil {
+		t.Fatalf("GetByReviewerID() error = %v", err)
+	}
+	if ids := shortIDs(reviews); !slices.Equal(ids, []string{"recent"}) {
+		t.Fatalf("expected archived PRs to be hidden, got %v", ids)
+	}
+	reviews, err = b.PRs.GetByReviewerID(ctx, "u2", true)
+	if err != nil {
+		t.Fatalf("GetByReviewerID() error = %v", err)
+	}
+	if ids := shortIDs(reviews); len(ids) != 3 || !slices.Contains(ids, "old-1") || !slices.Contains(ids, "old-2") {
+		t.Fatalf("expected archived PRs on request, got %v", ids)
+	}
+
+	if stats, err := b.PRs.GetReviewerStats(ctx, false); err != nil || stats["u2"] != 1 || stats["u3"] != 1 {
+		t.Fatalf("expected stats without the archive, got %v, %v", stats, err)
+	}
+	if stats, err := b.PRs.GetReviewerStats(ctx, true); err != nil || stats["u2"] != 3 || stats["u3"] != 1 {
+		t.Fatalf("expected stats with the archive, got %v, %v", stats, err)
+	}
+
+	dup := pull_request.NewPR("old-1", "again", "u1", pull_request.OPEN)
+	if err := b.PRs.Create(ctx, dup); !errors.Is(err, pull_request.ErrPRExists) {
+		t.Fatalf("expected an archived id to stay taken, got %v", err)
+	}
+
+	errAbort := errors.New("abort")
+	err = b.Tx.Do(ctx, func(ctx context.Context) error {
+		if _, err := b.PRs.ArchiveMerged(ctx, base.Add(72*time.Hour), 10); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected errAbort, got %v", err)
+	}
+	if pr, err := b.PRs.GetByID(ctx, "recent"); err != nil || !slices.Equal(pr.AssignedReviewers, []string{"u2"}) {
+		t.Fatalf("expected the archiving to be rolled back, got %+v, %v", pr, err)
+	}
+}
+
+func testUnitOfWorkRollback(t *testing.T, b Backend) {
+	ctx := context.Background()
+	seedBackend(t, b)
+	seedPR(t, b, "pr-1", "u1", base, "u2")
+
+	errAbort := errors.New("abort")
+	err := b.Tx.Do(ctx, func(ctx context.Context) error {
+		tm := team.NewTeam("platform")
+		tm.Members[0] = user.NewUser("u9", "Nina", "", true)
+		if err := b.Teams.Create(ctx, *tm); err != nil {
+			return err
+		}
+		if _, err := b.Users.SetIsActive(ctx, "u1", false); err != nil {
+			return err
+		}
+
+		pr, err := b.PRs.GetByID(ctx, "pr-1")
+		if err != nil {
+			return err
+		}
+		pr.AssignedReviewers = []string{"u3"}
+		if err := b.PRs.Update(ctx, pr); err != nil {
+			return err
+		}
+		if err := b.PRs.Create(ctx, pull_request.NewPR("pr-2", "Doomed", "u1", pull_request.OPEN)); err != nil {
+			return err
+		}
+
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected errAbort, got %v", err)
+	}
+
+	if _, err := b.Teams.GetByTeamName(ctx, "platform"); !errors.Is(err, team.ErrTeamNotFound) {
+		t.Fatalf("expected team creation to be rolled back, got %v", err)
+	}
+	if _, err := b.Users.GetByID(ctx, "u9"); !errors.Is(err, user.ErrUserNotFound) {
+		t.Fatalf("expected member upsert to be rolled back, got %v", err)
+	}
+	if u, err := b.Users.GetByID(ctx, "u1"); err != nil || !u.IsActive {
+		t.Fatalf("expected u1 to stay active, got %+v, %v", u, err)
+	}
+	pr, err := b.PRs.GetByID(ctx, "pr-1")
+	if err != nil || !slices.Equal(pr.AssignedReviewers, []string{"u2"}) || pr.Version != 1 {
+		t.Fatalf("expected pr-1 to be untouched, got %+v, %v", pr, err)
+	}
+	history, err := b.PRs.GetAssignments(ctx, "pr-1")
+	if err != nil || len(history) != 1 || history[0].UnassignedAt != nil {
+		t.Fatalf("expected assignment history to be rolled back, got %+v, %v", history, err)
+	}
+	if _, err := b.PRs.GetByID(ctx, "pr-2"); !errors.Is(err, pull_request.ErrNotFound) {
+		t.Fatalf("expected PR creation to be rolled back, got %v", err)
+	}
+}
+
+func testAuditLog(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	entries := []*audit.Entry{
+		{At: base, Actor: "lead", Action: audit.ActionUserSetIsActive, EntityType: audit.EntityUser, EntityID: "u1",
+			Before: json.RawMessage(`{"user_id":"u1","is_active":true}`), After: json.RawMessage(`{"user_id":"u1","is_active":false}`)},
+		{At: base.Add(time.Hour), Action: audit.ActionPRCreate, EntityType: audit.EntityPullRequest, EntityID: "pr-1",
+			Before: json.RawMessage(`null`), After: json.RawMessage(`{"pull_request_id":"pr-1","assigned_reviewers":["u2"]}`)},
+		{At: base.Add(2 * time.Hour), Actor: "lead", Action: audit.ActionPRMerge, EntityType: audit.EntityPullRequest, EntityID: "pr-1",
+			Before: json.RawMessage(`{"status":"OPEN"}`), After: json.RawMessage(`{"status":"MERGED"}`)},
+	}
+	for _, e := range entries {
+		if err := b.Audit.Append(ctx, e); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+		if e.ID == 0 {
+			t.Fatalf("expected Append to set the id")
+		}
+	}
+
+	all, err := b.Audit.List(ctx, audit.Filter{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(all) != 3 || all[0].ID != entries[2].ID || all[2].ID != entries[0].ID {
+		t.Fatalf("expected all entries newest first, got %+v", all)
+	}
+	got := all[1]
+	if !got.At.Equal(base.Add(time.Hour)) || got.Actor != "" || got.Action != audit.ActionPRCreate ||
+		got.EntityType != audit.EntityPullRequest || got.EntityID != "pr-1" {
+		t.Fatalf("unexpected entry: %+v", got)
+	}
+	assertJSON(t, got.Before, `null`)
+	assertJSON(t, got.After, `{"pull_request_id":"pr-1","assigned_reviewers":["u2"]}`)
+
+	filters := map[string]struct {
+		filter audit.Filter
+		want   []int
+	}{
+		"entity":     {audit.Filter{EntityType: audit.EntityPullRequest, EntityID: "pr-1"}, []int{2, 1}},
+		"actor":      {audit.Filter{Actor: "lead"}, []int{2, 0}},
+		"time range": {audit.Filter{From: base.Add(time.Hour), To: base.Add(2 * time.Hour)}, []int{1}},
+		"limit":      {audit.Filter{Limit: 1}, []int{2}},
+	}
+	for name, tc := range filters {
+		got, err := b.Audit.List(ctx, tc.filter)
+		if err != nil {
+			t.Fatalf("%s: List() error = %v", name, err)
+		}
+		ids := make([]int64, 0, len(got))
+		for _, e := range got {
+			ids = append(ids, e.ID)
+		}
+		want := make([]int64, 0, len(tc.want))
+		for _, i := range tc.want {
+			want = append(want, entries[i].ID)
+		}
+		if !slices.Equal(ids, want) {
+			t.Fatalf("%s: expected ids %v, got %v", name, want, ids)
+		}
+	}
+
+	errAbort := errors.New("abort")
+	err = b.Tx.Do(ctx, func(ctx context.Context) error {
+		e := &audit.Entry{At: base.Add(3 * time.Hour), Action: audit.ActionTeamCreate, EntityType: audit.EntityTeam, EntityID: "platform",
+			Before: json.RawMessage(`null`), After: json.RawMessage(`{}`)}
+		if err := b.Audit.Append(ctx, e); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected errAbort, got %v", err)
+	}
+	if got, err := b.Audit.List(ctx, audit.Filter{EntityType: audit.EntityTeam}); err != nil || len(got) != 0 {
+		t.Fatalf("expected the entry to be rolled back with its unit of work, got %+v, %v", got, err)
+	}
+}
+
+func testOutbox(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	appended := []*events.Event{
+		{Type: events.PullRequestCreated, AggregateType: events.AggregatePullRequest, AggregateID: "pr-1",
+			Payload: json.RawMessage(`{"pull_request_id":"pr-1"}`), OccurredAt: base, NextAttemptAt: base},
+		{Type: events.UserActivityChanged, AggregateType: events.AggregateUser, AggregateID: "u1",
+			Payload: json.RawMessage(`{"user_id":"u1","is_active":false}`), OccurredAt: base, NextAttemptAt: base},
+		{Type: events.PullRequestMerged, AggregateType: events.AggregatePullRequest, AggregateID: "pr-1",
+			Payload: json.RawMessage(`{"pull_request_id":"pr-1","status":"MERGED"}`), OccurredAt: base.Add(time.Hour), NextAttemptAt: base.Add(time.Hour)},
+		{Type: events.PullRequestCreated, AggregateType: events.AggregatePullRequest, AggregateID: "pr-2",
+			Payload: json.RawMessage(`{"pull_request_id":"pr-2"}`), OccurredAt: base, NextAttemptAt: base.Add(2 * time.Hour)},
+	}
+	for _, e := range appended {
+		if err := b.Outbox.Append(ctx, e); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+		if e.ID == 0 {
+			t.Fatalf("expected Append to set the id")
+		}
+	}
+
+	pendingIDs := func(now time.Time) []int64 {
+		t.Helper()
+		pending, err := b.Outbox.Pending(ctx, now, 10)
+		if err != nil {
+			t.Fatalf("Pending() error = %v", err)
+		}
+		ids := make([]int64, 0, len(pending))
+		for _, e := range pending {
+			ids = append(ids, e.ID)
+		}
+		return ids
+	}
+
+	pending, err := b.Outbox.Pending(ctx, base.Add(time.Hour), 10)
+	if err != nil {
+		t.Fatalf("Pending() error = %v", err)
+	}
+	if len(pending) != 2 || pending[0].ID != appended[0].ID || pending[1].ID != appended[1].ID {
+		t.Fatalf("expected the due head of every aggregate oldest first, got %+v", pending)
+	}
+	got := pending[1]
+	if got.Type != events.UserActivityChanged || got.AggregateType != events.AggregateUser || got.AggregateID != "u1" ||
+		!got.OccurredAt.Equal(base) || !got.NextAttemptAt.Equal(base) || got.Attempts != 0 || got.LastError != "" || got.ParkedAt != nil {
+		t.Fatalf("unexpected event: %+v", got)
+	}
+	assertJSON(t, got.Payload, `{"user_id":"u1","is_active":false}`)
+
+	if limited, err := b.Outbox.Pending(ctx, base, 1); err != nil || len(limited) != 1 || limited[0].ID != appended[0].ID {
+		t.Fatalf("expected the limit to keep the oldest event, got %+v, %v", limited, err)
+	}
+	if ids := pendingIDs(base.Add(3 * time.Hour)); !slices.Equal(ids, []int64{appended[0].ID, appended[1].ID, appended[3].ID}) {
+		t.Fatalf("expected one event per aggregate once all are due, got %v", ids)
+	}
+
+	failed := pending[0]
+	failed.Attempts = 1
+	failed.NextAttemptAt = base.Add(2 * time.Hour)
+	failed.LastError = "sink unavailable"
+	if err := b.Outbox.MarkFailed(ctx, failed); err != nil {
+		t.Fatalf("MarkFailed() error = %v", err)
+	}
+	if err := b.Outbox.MarkDelivered(ctx, appended[1].ID); err != nil {
+		t.Fatalf("MarkDelivered() error = %v", err)
+	}
+
+	if ids := pendingIDs(base.Add(time.Hour)); len(ids) != 0 {
+		t.Fatalf("expected an event in backoff to hold back its aggregate, got %v", ids)
+	}
+	pending, err = b.Outbox.Pending(ctx, base.Add(2*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("Pending() error = %v", err)
+	}
+	if len(pending) != 2 || pending[0].ID != appended[0].ID || pending[1].ID != appended[3].ID {
+		t.Fatalf("expected the delivered event to leave the outbox, got %+v", pending)
+	}
+	if got := pending[0]; got.Attempts != 1 || !got.NextAttemptAt.Equal(base.Add(2*time.Hour)) || got.LastError != "sink unavailable" {
+		t.Fatalf("expected the failure to be stored, got %+v", got)
+	}
+
+	parkedAt := base.Add(2 * time.Hour)
+	failed.Attempts = 2
+	failed.ParkedAt = &parkedAt
+	if err := b.Outbox.MarkFailed(ctx, failed); err != nil {
+		t.Fatalf("MarkFailed() error = %v", err)
+	}
+	if ids := pendingIDs(base.Add(2 * time.Hour)); !slices.Equal(ids, []int64{appended[2].ID, appended[3].ID}) {
+		t.Fatalf("expected a parked event to be skipped and stop holding back its aggregate, got %v", ids)
+	}
+
+	errAbort := errors.New("abort")
+	err = b.Tx.Do(ctx, func(ctx context.Context) error {
+		e := &events.Event{Type: events.PullRequestCreated, AggregateType: events.AggregatePullRequest, AggregateID: "pr-3",
+			Payload: json.RawMessage(`{}`), OccurredAt: base, NextAttemptAt: base}
+		if err := b.Outbox.Append(ctx, e); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected errAbort, got %v", err)
+	}
+	if ids := pendingIDs(base.Add(2 * time.Hour)); len(ids) != 2 {
+		t.Fatalf("expected the event to be rolled back with its unit of work, got %v", ids)
+	}
+}
+
+func testIdempotency(t *testing.T, b Backend) {
+	ctx := context.Background()
+	rec := idempotency.Record{Key: "k1", Fingerprint: "f1", CreatedAt: base, ExpiresAt: base.Add(time.Hour)}
+
+	if existing, err := b.Idempotency.Reserve(ctx, rec); err != nil || existing != nil {
+		t.Fatalf("expected a new key to be reserved, got %+v, %v", existing, err)
+	}
+
+	retry := rec
+	retry.Fingerprint = "f2"
+	retry.CreatedAt = base.Add(time.Minute)
+	existing, err := b.Idempotency.Reserve(ctx, retry)
+	if err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if existing == nil || !existing.Pending() || existing.Fingerprint != "f1" || !existing.ExpiresAt.Equal(rec.ExpiresAt) {
+		t.Fatalf("expected the pending record, got %+v", existing)
+	}
+
+	if err := b.Idempotency.Complete(ctx, "k1", 201, []byte(`{"ok":true}`)); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	existing, err = b.Idempotency.Reserve(ctx, retry)
+	if err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if existing == nil || existing.Pending() || existing.Status != 201 || string(existing.Body) != `{"ok":true}` {
+		t.Fatalf("expected the stored response, got %+v", existing)
+	}
+
+	if err := b.Idempotency.Release(ctx, "k1"); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if existing, err := b.Idempotency.Reserve(ctx, retry); err != nil || existing != nil {
+		t.Fatalf("expected a released key to be reserved again, got %+v, %v", existing, err)
+	}
+
+	expired := idempotency.Record{Key: "k2", Fingerprint: "f1", CreatedAt: base, ExpiresAt: base.Add(time.Minute)}
+	if _, err := b.Idempotency.Reserve(ctx, expired); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	later := expired
+	later.Fingerprint = "f3"
+	later.CreatedAt = base.Add(2 * time.Minute)
+	later.ExpiresAt = base.Add(time.Hour)
+	if existing, err := b.Idempotency.Reserve(ctx, later); err != nil || existing != nil {
+		t.Fatalf("expected an expired key to be taken over, got %+v, %v", existing, err)
+	}
+
+	n, err := b.Idempotency.DeleteExpired(ctx, base.Add(time.Hour))
+	if err != nil || n != 2 {
+		t.Fatalf("expected both keys to expire, got %d, %v", n, err)
+	}
+	if existing, err := b.Idempotency.Reserve(ctx, rec); err != nil || existing != nil {
+		t.Fatalf("expected deleted keys to be gone, got %+v, %v", existing, err)
+	}
+}
+
+func testTokens(t *testing.T, b Backend) {
+	ctx := context.Background()
+	expiresAt := base.Add(24 * time.Hour)
+	created := []*auth.Token{
+		{ID: "t1", UserID: "u1", Role: auth.RoleTeamLead, Description: "ci", Hash: "h1", CreatedAt: base, ExpiresAt: &expiresAt},
+		{ID: "t2", UserID: "admin", Role: auth.RoleAdmin, Hash: "h2", CreatedAt: base.Add(time.Minute)},
+	}
+	for _, tok := range created {
+		if err := b.Tokens.Create(ctx, tok); err != nil {
+			t.Fatalf("Create(%s) error = %v", tok.ID, err)
+		}
+	}
+
+	got, err := b.Tokens.GetByHash(ctx, "h1")
+	if err != nil {
+		t.Fatalf("GetByHash() error = %v", err)
+	}
+	if got.ID != "t1" || got.UserID != "u1" || got.Role != auth.RoleTeamLead || got.Description != "ci" ||
+		!got.CreatedAt.Equal(base) || got.ExpiresAt == nil || !got.ExpiresAt.Equal(expiresAt) || got.RevokedAt != nil {
+		t.Fatalf("unexpected token: %+v", got)
+	}
+	if _, err := b.Tokens.GetByHash(ctx, "missing"); !errors.Is(err, auth.ErrTokenNotFound) {
+		t.Fatalf("expected ErrTokenNotFound, got %v", err)
+	}
+
+	revokedAt := base.Add(time.Hour)
+	revoked, err := b.Tokens.Revoke(ctx, "t2", revokedAt)
+	if err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if revoked.ID != "t2" || revoked.RevokedAt == nil || !revoked.RevokedAt.Equal(revokedAt) {
+		t.Fatalf("unexpected revoked token: %+v", revoked)
+	}
+	if again, err := b.Tokens.Revoke(ctx, "t2", revokedAt.Add(time.Hour)); err != nil || !again.RevokedAt.Equal(revokedAt) {
+		t.Fatalf("expected a second revoke to keep the first time, got %+v, %v", again, err)
+	}
+	if _, err := b.Tokens.Revoke(ctx, "missing", revokedAt); !errors.Is(err, auth.ErrTokenNotFound) {
+		t.Fatalf("expected ErrTokenNotFound, got %v", err)
+	}
+
+	list, err := b.Tokens.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list) != 2 || list[0].ID != "t1" || list[1].ID != "t2" || list[1].RevokedAt == nil {
+		t.Fatalf("expected both tokens oldest first, got %+v", list)
+	}
+
+	errAbort := errors.New("abort")
+	err = b.Tx.Do(ctx, func(ctx context.Context) error {
+		if err := b.Tokens.Create(ctx, &auth.Token{ID: "t3", UserID: "u2", Role: auth.RoleMember, Hash: "h3", CreatedAt: base}); err != nil {
+			return err
+		}
+		if _, err := b.Tokens.Revoke(ctx, "t1", revokedAt); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected errAbort, got %v", err)
+	}
+	if _, err := b.Tokens.GetByHash(ctx, "h3"); !errors.Is(err, auth.ErrTokenNotFound) {
+		t.Fatalf("expected the new token to be rolled back, got %v", err)
+	}
+	if got, err := b.Tokens.GetByHash(ctx, "h1"); err != nil || got.RevokedAt != nil {
+		t.Fatalf("expected the revoke to be rolled back, got %+v, %v", got, err)
+	}
+}
+
+// assertJSON compares JSON documents semantically, since Postgres normalizes
+// JSONB formatting and key order.
+func assertJSON(t *testing.T, got json.RawMessage, want string) {
+	t.Helper()
+
+	var g, w any
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("unmarshal %s: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("unmarshal %s: %v", want, err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Fatalf("expected JSON %s, got %s", want, got)
+	}
+}
+
+func shortIDs(prs []pull_request.PullRequestShort) []string {
+	ids := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		ids = append(ids, pr.PullRequestId)
+	}
+	return ids
+}
@@ -0,0 +1,15 @@
+// Package api embeds the API specifications, so that the service can check
+// its requests and responses against them.
+package api
+
+import _ "embed"
+
+// OpenAPI describes the legacy routes.
+//
+//go:embed openapi.yml
+var OpenAPI []byte
+
+// OpenAPIV2 describes the /v2 routes.
+//
+//go:embed openapi-v2.yml
+var OpenAPIV2 []byte